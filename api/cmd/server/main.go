@@ -57,6 +57,17 @@ func main() {
 		}
 	}()
 
+	// Connect to Redis (rate limiting, user events and notification fan-out; the API keeps serving without it)
+	if err := database.ConnectRedis(cfg); err != nil {
+		log.Printf("Warning: %v", err)
+		log.Println("Continuing without rate limiting")
+	}
+	defer func() {
+		if err := database.CloseRedis(); err != nil {
+			log.Printf("Error closing Redis connection: %v", err)
+		}
+	}()
+
 	// Run migrations
 	// Use relative path - requires running from api/ directory
 	// Alternative: Use environment variable MIGRATIONS_PATH for flexibility
@@ -77,18 +88,49 @@ func main() {
 	// Initialize router
 	r := gin.Default()
 
+	// Client IPs come from X-Forwarded-For only when the request arrives through a trusted proxy
+	if err := r.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Initialize repositories
 	postRepo := repository.NewPostRepository(database.GetDB())
 	commentRepo := repository.NewCommentRepository(database.GetDB())
+	relationshipRepo := repository.NewRelationshipRepository(database.GetDB())
+	reactionRepo := repository.NewReactionRepository(database.GetDB())
+	bookmarkRepo := repository.NewBookmarkRepository(database.GetDB())
+	tagRepo := repository.NewTagRepository(database.GetDB())
+	followRepo := repository.NewFollowRepository(database.GetDB())
+	notificationRepo := repository.NewNotificationRepository(database.GetDB())
+	sitemapRepo := repository.NewSitemapRepository(database.GetDB())
 
 	// Initialize services
 	jwtService := service.NewJWTService(cfg)
-	postService := service.NewPostService(postRepo)
-	commentService := service.NewCommentService(commentRepo, postRepo)
+	authClient := service.NewAuthClient(cfg, jwtService)
+	notificationHub := service.NewNotificationHub(database.GetRedis())
+	notificationService := service.NewNotificationService(notificationRepo, relationshipRepo, notificationHub, authClient)
+	postService := service.NewPostService(postRepo, tagRepo)
+	commentService := service.NewCommentService(commentRepo, postRepo, relationshipRepo, notificationService)
+	relationshipService := service.NewRelationshipService(relationshipRepo)
+	reactionService := service.NewReactionService(reactionRepo, postRepo, commentRepo, relationshipRepo, cfg.Reactions.Emojis)
+	bookmarkService := service.NewBookmarkService(bookmarkRepo, postRepo)
+	followService := service.NewFollowService(followRepo, tagRepo, postRepo, authClient)
+	syndicationService := service.NewSyndicationService(cfg, postRepo, tagRepo)
+	sitemapService := service.NewSitemapService(cfg, sitemapRepo, authClient)
+	userDataService := service.NewUserDataService(postRepo, commentRepo, relationshipRepo, reactionRepo, bookmarkRepo, followRepo, notificationRepo)
 
 	// Initialize handlers
-	postHandler := handler.NewPostHandler(postService)
-	commentHandler := handler.NewCommentHandler(commentService)
+	postHandler := handler.NewPostHandler(postService, reactionService, bookmarkService)
+	commentHandler := handler.NewCommentHandler(commentService, reactionService)
+	relationshipHandler := handler.NewRelationshipHandler(relationshipService)
+	reactionHandler := handler.NewReactionHandler(reactionService)
+	bookmarkHandler := handler.NewBookmarkHandler(bookmarkService, postService, reactionService)
+	followHandler := handler.NewFollowHandler(followService, postService, reactionService, bookmarkService)
+	authorHandler := handler.NewAuthorHandler(postService, reactionService, bookmarkService, followService, authClient)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	syndicationHandler := handler.NewSyndicationHandler(syndicationService, authClient)
+	sitemapHandler := handler.NewSitemapHandler(sitemapService)
+	internalHandler := handler.NewInternalHandler(userDataService)
 
 	// Health check endpoint
 	r.GET("/health", handler.HealthCheck)
@@ -99,47 +141,143 @@ func main() {
 	// Swagger documentation
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Rate limiters
+	defaultLimit := middleware.RateLimit("default", cfg.RateLimit.Default, middleware.KeyByIP)
+	writeLimit := middleware.RateLimit("write", cfg.RateLimit.Write, middleware.KeyByUser)
+	tokenLimit := middleware.RateLimit("token", cfg.RateLimit.Token, middleware.KeyByToken)
+
+	// Syndication feeds, sitemaps and robots.txt (public, served at the site root so the
+	// site can proxy them)
+	feeds := r.Group("", defaultLimit)
+	{
+		feeds.GET("/feed.xml", syndicationHandler.RSS)
+		feeds.GET("/atom.xml", syndicationHandler.Atom)
+		feeds.GET("/feed.json", syndicationHandler.JSONFeed)
+		feeds.GET("/sitemap.xml", sitemapHandler.Index)
+		feeds.GET("/sitemaps/:file", sitemapHandler.Sitemap)
+		feeds.GET("/robots.txt", sitemapHandler.Robots)
+	}
+
+	// Signed-in callers see which reactions they have left
+	optionalAuth := middleware.OptionalAuthMiddleware(jwtService, authClient)
+
 	// API routes
 	api := r.Group("/api")
+	api.Use(defaultLimit)
 	{
 		// Posts routes
 		posts := api.Group("/posts")
 		{
 			// Public routes (no authentication required)
-			posts.GET("", postHandler.ListPosts)                              // List all posts
-			posts.GET("/:id", postHandler.GetPost)                            // Get single post
-			posts.GET("/slug/:slug", postHandler.GetPostBySlug)               // Get post by slug
-			posts.GET("/:id/comments", commentHandler.ListCommentsByPost)     // List comments
+			posts.GET("", optionalAuth, postHandler.ListPosts)                          // List all posts
+			posts.GET("/:id", optionalAuth, postHandler.GetPost)                        // Get single post
+			posts.GET("/slug/:slug", optionalAuth, postHandler.GetPostBySlug)           // Get post by slug
+			posts.GET("/:id/comments", optionalAuth, commentHandler.ListCommentsByPost) // List comments (hides muted users when signed in)
 
 			// Protected routes (authentication required)
 			protected := posts.Group("")
-			protected.Use(middleware.AuthMiddleware(jwtService))
+			protected.Use(middleware.AuthMiddleware(jwtService, authClient), writeLimit, tokenLimit)
 			{
-				protected.POST("", postHandler.CreatePost)
-				protected.PUT("/:id", postHandler.UpdatePost)
-				protected.DELETE("/:id", postHandler.DeletePost)
-				protected.POST("/:id/publish", postHandler.PublishPost)
-				protected.POST("/:id/unpublish", postHandler.UnpublishPost)
-				protected.POST("/:id/comments", commentHandler.CreateComment)
+				canWritePosts := middleware.RequirePermission(middleware.PermissionPostsWrite)
+				writePosts := middleware.RequireScope(middleware.ScopePostsWrite)
+				protected.POST("", canWritePosts, writePosts, postHandler.CreatePost)
+				protected.PUT("/:id", canWritePosts, writePosts, postHandler.UpdatePost)
+				protected.DELETE("/:id", canWritePosts, writePosts, postHandler.DeletePost)
+				protected.POST("/:id/publish", canWritePosts, writePosts, postHandler.PublishPost)
+				protected.POST("/:id/unpublish", canWritePosts, writePosts, postHandler.UnpublishPost)
+				protected.POST("/:id/comments", middleware.RequirePermission(middleware.PermissionCommentsWrite),
+					middleware.RequireScope(middleware.ScopeCommentsWrite), commentHandler.CreateComment)
+				protected.POST("/:id/reactions/:type", middleware.RequireScope(middleware.ScopeReactionsWrite),
+					reactionHandler.TogglePostReaction)
 			}
 		}
 
+		// Author pages (public)
+		api.GET("/authors/:username", optionalAuth, authorHandler.GetAuthor)
+
+		// Reaction types (public)
+		api.GET("/reactions", reactionHandler.ListReactionTypes)
+
+		// Tags (public)
+		api.GET("/tags/:tag", optionalAuth, followHandler.GetTag)
+
+		// Posts from followed authors and tags (authentication required)
+		api.GET("/feed", middleware.AuthMiddleware(jwtService, authClient), followHandler.GetFeed)
+
 		// Comments routes
 		comments := api.Group("/comments")
 		{
 			// Public routes
-			comments.GET("/:id", commentHandler.GetComment)
+			comments.GET("/:id", optionalAuth, commentHandler.GetComment)
 
 			// Protected routes (authentication required)
 			protected := comments.Group("")
-			protected.Use(middleware.AuthMiddleware(jwtService))
+			protected.Use(middleware.AuthMiddleware(jwtService, authClient), writeLimit, tokenLimit)
 			{
-				protected.PUT("/:id", commentHandler.UpdateComment)
-				protected.DELETE("/:id", commentHandler.DeleteComment)
-				protected.POST("/:id/approve", commentHandler.ApproveComment)
-				protected.POST("/:id/reject", commentHandler.RejectComment)
+				canWriteComments := middleware.RequirePermission(middleware.PermissionCommentsWrite)
+				canModerateComments := middleware.RequirePermission(middleware.PermissionCommentsModerate)
+				writeComments := middleware.RequireScope(middleware.ScopeCommentsWrite)
+				moderateComments := middleware.RequireScope(middleware.ScopeCommentsModerate)
+				protected.PUT("/:id", canWriteComments, writeComments, commentHandler.UpdateComment)
+				protected.DELETE("/:id", canWriteComments, writeComments, commentHandler.DeleteComment)
+				protected.POST("/:id/approve", canModerateComments, moderateComments, commentHandler.ApproveComment)
+				protected.POST("/:id/reject", canModerateComments, moderateComments, commentHandler.RejectComment)
+				protected.POST("/:id/reactions/:type", middleware.RequireScope(middleware.ScopeReactionsWrite),
+					reactionHandler.ToggleCommentReaction)
 			}
 		}
+
+		// The caller's own blocks, mutes, bookmarks, follows and notifications (authentication required)
+		me := api.Group("/me")
+		me.Use(middleware.AuthMiddleware(jwtService, authClient), writeLimit, tokenLimit)
+		{
+			relationships := me.Group("", middleware.RequireScope(middleware.ScopeRelationshipsWrite))
+			relationships.GET("/blocks", relationshipHandler.ListBlocks)
+			relationships.PUT("/blocks/:user_id", relationshipHandler.Block)
+			relationships.DELETE("/blocks/:user_id", relationshipHandler.Unblock)
+			relationships.GET("/mutes", relationshipHandler.ListMutes)
+			relationships.PUT("/mutes/:user_id", relationshipHandler.Mute)
+			relationships.DELETE("/mutes/:user_id", relationshipHandler.Unmute)
+
+			bookmarks := me.Group("", middleware.RequireScope(middleware.ScopeBookmarksWrite))
+			bookmarks.GET("/bookmarks", bookmarkHandler.ListBookmarks)
+			bookmarks.PUT("/bookmarks/:post_id", bookmarkHandler.AddBookmark)
+			bookmarks.DELETE("/bookmarks/:post_id", bookmarkHandler.RemoveBookmark)
+			bookmarks.GET("/bookmark-lists", bookmarkHandler.ListLists)
+			bookmarks.POST("/bookmark-lists", bookmarkHandler.CreateList)
+			bookmarks.PUT("/bookmark-lists/:id", bookmarkHandler.RenameList)
+			bookmarks.DELETE("/bookmark-lists/:id", bookmarkHandler.DeleteList)
+
+			follows := me.Group("", middleware.RequireScope(middleware.ScopeFollowsWrite))
+			follows.GET("/following", followHandler.ListFollowing)
+			follows.PUT("/following/authors/:user_id", followHandler.FollowAuthor)
+			follows.DELETE("/following/authors/:user_id", followHandler.UnfollowAuthor)
+			follows.PUT("/following/tags/:tag", followHandler.FollowTag)
+			follows.DELETE("/following/tags/:tag", followHandler.UnfollowTag)
+
+			notifications := me.Group("", middleware.RequireScope(middleware.ScopeNotificationsWrite))
+			notifications.GET("/notifications", notificationHandler.ListNotifications)
+			notifications.GET("/notifications/stream", notificationHandler.StreamNotifications)
+			notifications.POST("/notifications/read-all", notificationHandler.MarkAllRead)
+			notifications.POST("/notifications/:id/read", notificationHandler.MarkRead)
+		}
+	}
+
+	// Internal routes (service tokens only)
+	internal := r.Group("/internal")
+	{
+		internal.GET("/users/:id/export", middleware.ServiceAuthMiddleware(jwtService, middleware.ScopeUsersExport), internalHandler.ExportUserData)
+	}
+
+	// Consume user lifecycle events from the auth service (e.g. account deletion)
+	eventsCtx, stopEvents := context.WithCancel(context.Background())
+	defer stopEvents()
+	if redisClient := database.GetRedis(); redisClient != nil {
+		go service.NewUserEventConsumer(redisClient, userDataService).Run(eventsCtx)
+		go notificationHub.Run(eventsCtx)
+	} else {
+		log.Println("Warning: Redis unavailable, user events from the auth service will not be processed")
+		log.Println("Warning: Redis unavailable, notification streams only receive notifications created on this instance")
 	}
 
 	// Create HTTP server
@@ -162,6 +300,10 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopEvents()
+
+	// Open notification streams would otherwise hold up the shutdown
+	notificationHub.Close()
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
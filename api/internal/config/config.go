@@ -3,8 +3,10 @@ package config
 import (
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -12,16 +14,23 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Auth     AuthConfig
+	App       AppConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Auth      AuthConfig
+	Redis     RedisConfig
+	RateLimit RateLimitConfig
+	Reactions ReactionConfig
+	Site      SiteConfig
+	Feeds     FeedConfig
+	Robots    RobotsConfig
 }
 
 // AppConfig holds application-level configuration
 type AppConfig struct {
-	Env  string
-	Port string
+	Env            string
+	Port           string
+	TrustedProxies []string // Proxy CIDRs allowed to set X-Forwarded-For; empty trusts none
 }
 
 // DatabaseConfig holds database configuration
@@ -44,9 +53,60 @@ type JWTConfig struct {
 
 // AuthConfig holds auth service configuration
 type AuthConfig struct {
-	ServiceURL string
+	ServiceURL     string
+	RequestTimeout time.Duration
 }
 
+// RedisConfig holds Redis configuration
+type RedisConfig struct {
+	Host     string
+	Port     string
+	Password string
+	DB       int
+}
+
+// RateLimitConfig holds per-route-group rate limits. A zero limit disables the rule.
+type RateLimitConfig struct {
+	Default RateLimitRule // All /api routes, per IP
+	Write   RateLimitRule // Authenticated write routes, per user
+	Token   RateLimitRule // Authenticated write routes, per access token or PAT
+}
+
+// RateLimitRule allows Limit requests per sliding Window
+type RateLimitRule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// ReactionConfig holds the reaction types offered on posts and comments
+type ReactionConfig struct {
+	Emojis []string // Offered alongside "like"
+}
+
+// SiteConfig describes the public website, for absolute links in syndication feeds and sitemaps
+type SiteConfig struct {
+	URL         string // Base URL the site is served from, without a trailing slash
+	Title       string
+	Description string
+}
+
+// FeedConfig holds RSS, Atom and JSON Feed settings
+type FeedConfig struct {
+	Size        int  // Posts per feed
+	FullContent bool // Include full post content by default, rather than the excerpt
+}
+
+// RobotsConfig holds the crawler rules published in robots.txt
+type RobotsConfig struct {
+	Disallow []string // Path prefixes crawlers should stay out of
+}
+
+// maxFeedSize caps FEED_SIZE so a feed request stays cheap
+const maxFeedSize = 100
+
+// maxReactionTypeLength matches the reactions.type column
+const maxReactionTypeLength = 32
+
 var config *Config
 
 // Load reads configuration from environment variables
@@ -58,8 +118,9 @@ func Load() (*Config, error) {
 
 	cfg := &Config{
 		App: AppConfig{
-			Env:  getEnv("APP_ENV", "dev"),
-			Port: getEnv("APP_PORT", "8081"),
+			Env:            getEnv("APP_ENV", "dev"),
+			Port:           getEnv("APP_PORT", "8081"),
+			TrustedProxies: getEnvAsList("TRUSTED_PROXIES", ""),
 		},
 		Database: DatabaseConfig{
 			Host:            getEnv("DB_HOST", "localhost"),
@@ -76,8 +137,39 @@ func Load() (*Config, error) {
 			Secret: getEnv("JWT_SECRET", ""),
 		},
 		Auth: AuthConfig{
-			ServiceURL: getEnv("AUTH_SERVICE_URL", "http://localhost:8082"),
+			ServiceURL:     getEnv("AUTH_SERVICE_URL", "http://localhost:8082"),
+			RequestTimeout: getEnvAsDuration("AUTH_SERVICE_TIMEOUT", 5*time.Second),
+		},
+		Redis: RedisConfig{
+			Host:     getEnv("REDIS_HOST", "localhost"),
+			Port:     getEnv("REDIS_PORT", "6379"),
+			Password: getEnv("REDIS_PASSWORD", ""),
+			DB:       getEnvAsInt("REDIS_DB", 0),
+		},
+		RateLimit: RateLimitConfig{
+			Default: getEnvAsRateLimit("RATE_LIMIT_DEFAULT", RateLimitRule{Limit: 300, Window: time.Minute}),
+			Write:   getEnvAsRateLimit("RATE_LIMIT_WRITE", RateLimitRule{Limit: 60, Window: time.Minute}),
+			Token:   getEnvAsRateLimit("RATE_LIMIT_TOKEN", RateLimitRule{Limit: 30, Window: time.Minute}),
+		},
+		Reactions: ReactionConfig{
+			Emojis: getEnvAsList("REACTION_EMOJIS", "🎉,😂,😮,😢,🔥"),
+		},
+		Site: SiteConfig{
+			URL:         strings.TrimRight(getEnv("SITE_URL", "http://localhost:3000"), "/"),
+			Title:       getEnv("SITE_TITLE", "Inkstack"),
+			Description: getEnv("SITE_DESCRIPTION", "Blog and knowledge hub"),
+		},
+		Feeds: FeedConfig{
+			Size:        getEnvAsInt("FEED_SIZE", 20),
+			FullContent: getEnvAsBool("FEED_FULL_CONTENT", true),
 		},
+		Robots: RobotsConfig{
+			Disallow: getEnvAsList("ROBOTS_DISALLOW", "/api/,/login,/register"),
+		},
+	}
+
+	if !getEnvAsBool("RATE_LIMIT_ENABLED", true) {
+		cfg.RateLimit = RateLimitConfig{}
 	}
 
 	// Validate required configuration
@@ -114,6 +206,24 @@ func (c *Config) validate() error {
 	if len(c.JWT.Secret) < 32 {
 		return fmt.Errorf("JWT_SECRET must be at least 32 characters long")
 	}
+	for _, emoji := range c.Reactions.Emojis {
+		if len(emoji) > maxReactionTypeLength || strings.Contains(emoji, "/") {
+			return fmt.Errorf("REACTION_EMOJIS contains an invalid reaction: %q", emoji)
+		}
+	}
+	siteURL, err := url.Parse(c.Site.URL)
+	if err != nil || (siteURL.Scheme != "http" && siteURL.Scheme != "https") || siteURL.Host == "" ||
+		siteURL.RawQuery != "" || siteURL.Fragment != "" {
+		return fmt.Errorf("SITE_URL must be an absolute http(s) URL, got %q", c.Site.URL)
+	}
+	if c.Feeds.Size < 1 || c.Feeds.Size > maxFeedSize {
+		return fmt.Errorf("FEED_SIZE must be between 1 and %d", maxFeedSize)
+	}
+	for _, path := range c.Robots.Disallow {
+		if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " \t\r\n") {
+			return fmt.Errorf("ROBOTS_DISALLOW contains an invalid path: %q", path)
+		}
+	}
 	return nil
 }
 
@@ -167,3 +277,52 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return value
 }
+
+// getEnvAsBool reads an environment variable as boolean or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		log.Printf("Invalid boolean value for %s: %s, using default: %t", key, valueStr, defaultValue)
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsList reads an environment variable as a comma-separated list or returns the default list
+func getEnvAsList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvAsRateLimit reads an environment variable as a "<limit>/<window>" rule, e.g. "100/1m"
+func getEnvAsRateLimit(key string, defaultValue RateLimitRule) RateLimitRule {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	parts := strings.SplitN(valueStr, "/", 2)
+	if len(parts) != 2 {
+		log.Printf("Invalid rate limit value for %s: %s, using default: %d/%s", key, valueStr, defaultValue.Limit, defaultValue.Window)
+		return defaultValue
+	}
+	limit, err := strconv.Atoi(parts[0])
+	if err != nil || limit < 0 {
+		log.Printf("Invalid rate limit value for %s: %s, using default: %d/%s", key, valueStr, defaultValue.Limit, defaultValue.Window)
+		return defaultValue
+	}
+	window, err := time.ParseDuration(parts[1])
+	if err != nil || window <= 0 {
+		log.Printf("Invalid rate limit value for %s: %s, using default: %d/%s", key, valueStr, defaultValue.Limit, defaultValue.Window)
+		return defaultValue
+	}
+	return RateLimitRule{Limit: limit, Window: window}
+}
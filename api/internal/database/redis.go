@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"inkstack/internal/config"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var redisClient *redis.Client
+
+// slidingWindowScript atomically records a hit in a sliding window log and reports
+// whether it is within the limit. Redis server time is used so replicas agree.
+// Returns {allowed, count, ms until the oldest hit leaves the window}.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local member = ARGV[3]
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, now .. '-' .. member)
+	redis.call('PEXPIRE', key, window)
+	count = count + 1
+	allowed = 1
+end
+
+local reset = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, count, reset}
+`)
+
+// RateLimitResult describes the outcome of a rate limit check
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+}
+
+// ConnectRedis establishes a connection to Redis
+func ConnectRedis(cfg *config.Config) error {
+	addr := fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port)
+
+	redisClient = redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+
+	// Test connection
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		// Leave the client unset so rate limiting is skipped instead of dialing on every request
+		redisClient.Close()
+		redisClient = nil
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	log.Println("Redis connection established successfully")
+	return nil
+}
+
+// GetRedis returns the Redis client instance
+func GetRedis() *redis.Client {
+	return redisClient
+}
+
+// CloseRedis closes the Redis connection
+func CloseRedis() error {
+	if redisClient != nil {
+		log.Println("Redis connection closed")
+		return redisClient.Close()
+	}
+	return nil
+}
+
+// AllowRequest records a request against a sliding window rate limit shared by all replicas
+func AllowRequest(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+	if redisClient == nil {
+		return nil, fmt.Errorf("redis not initialized")
+	}
+
+	member := fmt.Sprintf("%d", time.Now().UnixNano())
+	values, err := slidingWindowScript.Run(ctx, redisClient, []string{"ratelimit:api:" + key},
+		window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := limit - int(values[1])
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &RateLimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  remaining,
+		ResetAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
+
+// IsTokenBlacklisted checks whether the auth service has revoked an access token. The
+// auth service keeps its blacklist in the same Redis.
+func IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+	if redisClient == nil {
+		return false, fmt.Errorf("redis not initialized")
+	}
+
+	result, err := redisClient.Exists(ctx, "blacklist:"+token).Result()
+	if err != nil {
+		return false, err
+	}
+	return result > 0, nil
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"errors"
+	"inkstack/internal/models"
+	"inkstack/internal/service"
+	"inkstack/internal/util"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthorHandler handles HTTP requests for author pages
+type AuthorHandler struct {
+	postService   service.PostService
+	followService service.FollowService
+	authClient    *service.AuthClient
+	views         postViews
+}
+
+// NewAuthorHandler creates a new author handler
+func NewAuthorHandler(postService service.PostService, reactionService service.ReactionService, bookmarkService service.BookmarkService, followService service.FollowService, authClient *service.AuthClient) *AuthorHandler {
+	return &AuthorHandler{
+		postService:   postService,
+		followService: followService,
+		authClient:    authClient,
+		views:         postViews{posts: postService, reactions: reactionService, bookmarks: bookmarkService},
+	}
+}
+
+// GetAuthor handles GET /api/authors/:username
+// @Summary Get author page
+// @Description Get an author's public profile, follower and following counts, and published posts
+// @Tags authors
+// @Produce json
+// @Param username path string true "Author username"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /api/authors/{username} [get]
+func (h *AuthorHandler) GetAuthor(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	profile, err := h.authClient.GetUserProfile(c.Request.Context(), c.Param("username"))
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			util.RespondNotFound(c, "Author")
+			return
+		}
+		util.RespondWithError(c, http.StatusBadGateway, "failed to retrieve author profile")
+		return
+	}
+
+	posts, total, err := h.postService.ListPublishedPostsByAuthor(profile.ID, page, pageSize)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve posts")
+		return
+	}
+
+	postsResponse, err := h.views.toPostsResponse(c, posts)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve posts")
+		return
+	}
+
+	counts, err := h.followService.AuthorCounts(profile.ID)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve follower counts")
+		return
+	}
+	followedByMe, err := h.followService.IsFollowing(optionalUserID(c), models.FollowTargetAuthor, profile.ID)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve follower counts")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"author":         profile,
+		"followers":      counts.Followers,
+		"following":      counts.Following,
+		"followed_by_me": followedByMe,
+		"posts":          postsResponse,
+		"pagination":     util.CalculatePagination(page, pageSize, total),
+	})
+}
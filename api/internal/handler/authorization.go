@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"inkstack/internal/middleware"
+	"inkstack/internal/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authorizeOwner allows the owner of a resource, or a user whose role grants the
+// override permission, and responds with 403 otherwise. An empty override
+// permission restricts the action to the owner.
+func authorizeOwner(c *gin.Context, ownerID uint, overridePermission string) bool {
+	if userID, ok := c.Get("user_id"); ok && userID.(uint) == ownerID {
+		return true
+	}
+
+	if overridePermission != "" && middleware.HasPermission(c, overridePermission) {
+		return true
+	}
+
+	util.RespondWithError(c, http.StatusForbidden, "you do not have permission to modify this resource")
+	return false
+}
+
+// optionalUserID returns the signed-in user's ID on routes using
+// OptionalAuthMiddleware, or 0 for anonymous callers
+func optionalUserID(c *gin.Context) uint {
+	if userID, ok := c.Get("user_id"); ok {
+		return userID.(uint)
+	}
+	return 0
+}
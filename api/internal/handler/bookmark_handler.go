@@ -0,0 +1,414 @@
+package handler
+
+import (
+	"errors"
+	"inkstack/internal/models"
+	"inkstack/internal/service"
+	"inkstack/internal/util"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BookmarkHandler handles HTTP requests for bookmarks and reading lists
+type BookmarkHandler struct {
+	service service.BookmarkService
+	views   postViews
+}
+
+// NewBookmarkHandler creates a new bookmark handler
+func NewBookmarkHandler(service service.BookmarkService, postService service.PostService, reactions service.ReactionService) *BookmarkHandler {
+	return &BookmarkHandler{
+		service: service,
+		views:   postViews{posts: postService, reactions: reactions, bookmarks: service},
+	}
+}
+
+// Request/Response DTOs
+
+type AddBookmarkRequest struct {
+	ListID *uint `json:"list_id"` // Omit to leave the bookmark unlisted
+}
+
+type BookmarkListRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type BookmarkResponse struct {
+	PostID    uint          `json:"post_id"`
+	ListID    *uint         `json:"list_id"`
+	Available bool          `json:"available"` // False once the post is unpublished or deleted
+	Post      *PostResponse `json:"post"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type UserBookmarkResponse struct {
+	PostID    uint      `json:"post_id"`
+	ListID    *uint     `json:"list_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BookmarkListResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ListBookmarks handles GET /api/me/bookmarks
+// @Summary List bookmarks
+// @Description Get the caller's bookmarks, newest first. Bookmarks of posts that have been unpublished or deleted are returned with available false and no post.
+// @Tags bookmarks
+// @Produce json
+// @Param list_id query int false "Only bookmarks in this list"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param limit query int false "Page size" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/me/bookmarks [get]
+func (h *BookmarkHandler) ListBookmarks(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var listID *uint
+	if value := c.Query("list_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			util.RespondBadRequest(c, "invalid list ID")
+			return
+		}
+		listID = new(uint)
+		*listID = uint(id)
+	}
+
+	cursor, err := util.DecodeCursor(c.Query("cursor"))
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	bookmarks, next, err := h.service.ListBookmarks(userID.(uint), listID, cursor, limit)
+	if err != nil {
+		if errors.Is(err, service.ErrBookmarkListNotFound) {
+			util.RespondNotFound(c, "Bookmark list")
+			return
+		}
+		util.RespondInternalError(c, "failed to retrieve bookmarks")
+		return
+	}
+
+	bookmarksResponse, err := h.toBookmarksResponse(c, bookmarks)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve bookmarks")
+		return
+	}
+
+	var nextCursor *string
+	if next != 0 {
+		encoded := util.EncodeCursor(next)
+		nextCursor = &encoded
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bookmarks":   bookmarksResponse,
+		"next_cursor": nextCursor,
+	})
+}
+
+// AddBookmark handles PUT /api/me/bookmarks/:post_id
+// @Summary Bookmark a post
+// @Description Save a published post for later, optionally in one of the caller's lists. Bookmarking a post again moves it to the given list, or out of any list if list_id is omitted.
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Param post_id path int true "Post ID"
+// @Param request body AddBookmarkRequest false "List to add the bookmark to"
+// @Success 200 {object} BookmarkResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/me/bookmarks/{post_id} [put]
+func (h *BookmarkHandler) AddBookmark(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid post ID")
+		return
+	}
+
+	// The body is optional
+	var req AddBookmarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	bookmark, err := h.service.AddBookmark(userID.(uint), uint(postID), req.ListID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPostNotFound):
+			util.RespondNotFound(c, "Post")
+		case errors.Is(err, service.ErrBookmarkListNotFound):
+			util.RespondNotFound(c, "Bookmark list")
+		default:
+			util.RespondBadRequest(c, err.Error())
+		}
+		return
+	}
+
+	responses, err := h.toBookmarksResponse(c, []models.Bookmark{*bookmark})
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve bookmark")
+		return
+	}
+
+	c.JSON(http.StatusOK, responses[0])
+}
+
+// RemoveBookmark handles DELETE /api/me/bookmarks/:post_id
+// @Summary Remove a bookmark
+// @Description Remove a post from the caller's bookmarks
+// @Tags bookmarks
+// @Param post_id path int true "Post ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/me/bookmarks/{post_id} [delete]
+func (h *BookmarkHandler) RemoveBookmark(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid post ID")
+		return
+	}
+
+	if err := h.service.RemoveBookmark(userID.(uint), uint(postID)); err != nil {
+		if errors.Is(err, service.ErrBookmarkNotFound) {
+			util.RespondNotFound(c, "Bookmark")
+			return
+		}
+		util.RespondInternalError(c, "failed to remove bookmark")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// ListLists handles GET /api/me/bookmark-lists
+// @Summary List reading lists
+// @Description Get the caller's reading lists in name order
+// @Tags bookmarks
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/me/bookmark-lists [get]
+func (h *BookmarkHandler) ListLists(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	lists, err := h.service.ListLists(userID.(uint))
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve bookmark lists")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"lists": toBookmarkListsResponse(lists),
+	})
+}
+
+// CreateList handles POST /api/me/bookmark-lists
+// @Summary Create a reading list
+// @Description Create a named list to sort bookmarks into. Names are unique per user.
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Param request body BookmarkListRequest true "List name"
+// @Success 201 {object} BookmarkListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/me/bookmark-lists [post]
+func (h *BookmarkHandler) CreateList(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req BookmarkListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	list, err := h.service.CreateList(userID.(uint), req.Name)
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusCreated, toBookmarkListResponse(list))
+}
+
+// RenameList handles PUT /api/me/bookmark-lists/:id
+// @Summary Rename a reading list
+// @Description Change the name of one of the caller's reading lists
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Param id path int true "List ID"
+// @Param request body BookmarkListRequest true "New list name"
+// @Success 200 {object} BookmarkListResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/me/bookmark-lists/{id} [put]
+func (h *BookmarkHandler) RenameList(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	listID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid list ID")
+		return
+	}
+
+	var req BookmarkListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	list, err := h.service.RenameList(userID.(uint), uint(listID), req.Name)
+	if err != nil {
+		if errors.Is(err, service.ErrBookmarkListNotFound) {
+			util.RespondNotFound(c, "Bookmark list")
+			return
+		}
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toBookmarkListResponse(list))
+}
+
+// DeleteList handles DELETE /api/me/bookmark-lists/:id
+// @Summary Delete a reading list
+// @Description Delete one of the caller's reading lists. Bookmarks in the list are kept and become unlisted.
+// @Tags bookmarks
+// @Param id path int true "List ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/me/bookmark-lists/{id} [delete]
+func (h *BookmarkHandler) DeleteList(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	listID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid list ID")
+		return
+	}
+
+	if err := h.service.DeleteList(userID.(uint), uint(listID)); err != nil {
+		if errors.Is(err, service.ErrBookmarkListNotFound) {
+			util.RespondNotFound(c, "Bookmark list")
+			return
+		}
+		util.RespondInternalError(c, "failed to delete bookmark list")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Helper functions
+
+// toBookmarksResponse converts bookmarks, including the posts that are still available
+func (h *BookmarkHandler) toBookmarksResponse(c *gin.Context, bookmarks []models.Bookmark) ([]BookmarkResponse, error) {
+	var posts []models.Post
+	for _, bookmark := range bookmarks {
+		if bookmark.Post != nil {
+			posts = append(posts, *bookmark.Post)
+		}
+	}
+
+	postsResponse, err := h.views.toPostsResponse(c, posts)
+	if err != nil {
+		return nil, err
+	}
+	postsByID := make(map[uint]*PostResponse, len(postsResponse))
+	for i := range postsResponse {
+		postsByID[postsResponse[i].ID] = &postsResponse[i]
+	}
+
+	responses := make([]BookmarkResponse, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		responses[i] = toBookmarkResponse(&bookmark, postsByID[bookmark.PostID])
+	}
+	return responses, nil
+}
+
+func toBookmarkResponse(bookmark *models.Bookmark, post *PostResponse) BookmarkResponse {
+	return BookmarkResponse{
+		PostID:    bookmark.PostID,
+		ListID:    bookmark.ListID,
+		Available: post != nil,
+		Post:      post,
+		CreatedAt: bookmark.CreatedAt,
+	}
+}
+
+func toBookmarkListResponse(list *models.BookmarkList) BookmarkListResponse {
+	return BookmarkListResponse{
+		ID:        list.ID,
+		Name:      list.Name,
+		CreatedAt: list.CreatedAt,
+		UpdatedAt: list.UpdatedAt,
+	}
+}
+
+func toBookmarkListsResponse(lists []models.BookmarkList) []BookmarkListResponse {
+	responses := make([]BookmarkListResponse, len(lists))
+	for i, list := range lists {
+		responses[i] = toBookmarkListResponse(&list)
+	}
+	return responses
+}
+
+func toUserBookmarksResponse(bookmarks []models.Bookmark) []UserBookmarkResponse {
+	responses := make([]UserBookmarkResponse, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		responses[i] = UserBookmarkResponse{
+			PostID:    bookmark.PostID,
+			ListID:    bookmark.ListID,
+			CreatedAt: bookmark.CreatedAt,
+		}
+	}
+	return responses
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"errors"
+	"inkstack/internal/middleware"
 	"inkstack/internal/models"
 	"inkstack/internal/service"
 	"inkstack/internal/util"
@@ -13,12 +15,16 @@ import (
 
 // CommentHandler handles HTTP requests for comments
 type CommentHandler struct {
-	service service.CommentService
+	service   service.CommentService
+	reactions service.ReactionService
 }
 
 // NewCommentHandler creates a new comment handler
-func NewCommentHandler(service service.CommentService) *CommentHandler {
-	return &CommentHandler{service: service}
+func NewCommentHandler(service service.CommentService, reactions service.ReactionService) *CommentHandler {
+	return &CommentHandler{
+		service:   service,
+		reactions: reactions,
+	}
 }
 
 // Request/Response DTOs
@@ -33,14 +39,15 @@ type UpdateCommentRequest struct {
 }
 
 type CommentResponse struct {
-	ID        uint      `json:"id"`
-	PostID    uint      `json:"post_id"`
-	UserID    uint      `json:"user_id"`
-	ParentID  *uint     `json:"parent_id"`
-	Content   string    `json:"content"`
-	Status    string    `json:"status"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uint               `json:"id"`
+	PostID    uint               `json:"post_id"`
+	UserID    uint               `json:"user_id"`
+	ParentID  *uint              `json:"parent_id"`
+	Content   string             `json:"content"`
+	Status    string             `json:"status"`
+	Reactions []ReactionResponse `json:"reactions"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
 }
 
 // CreateComment handles POST /api/posts/:id/comments
@@ -53,6 +60,7 @@ type CommentResponse struct {
 // @Param request body CreateCommentRequest true "Comment details"
 // @Success 201 {object} CommentResponse
 // @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
 // @Router /api/posts/{id}/comments [post]
 func (h *CommentHandler) CreateComment(c *gin.Context) {
 	postID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -76,11 +84,15 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 
 	comment, err := h.service.CreateComment(uint(postID), userID.(uint), req.Content, req.ParentID)
 	if err != nil {
+		if errors.Is(err, service.ErrBlocked) {
+			util.RespondWithError(c, http.StatusForbidden, err.Error())
+			return
+		}
 		util.RespondBadRequest(c, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusCreated, toCommentResponse(comment))
+	h.respondWithComment(c, http.StatusCreated, comment)
 }
 
 // GetComment handles GET /api/comments/:id
@@ -106,12 +118,12 @@ func (h *CommentHandler) GetComment(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, toCommentResponse(comment))
+	h.respondWithComment(c, http.StatusOK, comment)
 }
 
 // ListCommentsByPost handles GET /api/posts/:id/comments
 // @Summary List comments for a post
-// @Description Get all comments for a specific post
+// @Description Get all comments for a specific post. Comments by users the caller has muted are left out.
 // @Tags comments
 // @Produce json
 // @Param id path int true "Post ID"
@@ -125,14 +137,23 @@ func (h *CommentHandler) ListCommentsByPost(c *gin.Context) {
 		return
 	}
 
-	comments, err := h.service.ListCommentsByPost(uint(postID))
+	// Signed-in viewers don't see comments from users they have muted
+	viewerID := optionalUserID(c)
+
+	comments, err := h.service.ListCommentsByPost(uint(postID), viewerID)
 	if err != nil {
 		util.RespondBadRequest(c, err.Error())
 		return
 	}
 
+	commentsResponse, err := toCommentsResponseWithReactions(c, h.reactions, comments)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve reactions")
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"comments": toCommentsResponse(comments),
+		"comments": commentsResponse,
 	})
 }
 
@@ -154,6 +175,15 @@ func (h *CommentHandler) UpdateComment(c *gin.Context) {
 		return
 	}
 
+	existing, err := h.service.GetComment(uint(id))
+	if err != nil {
+		util.RespondNotFound(c, "Comment")
+		return
+	}
+	if !authorizeOwner(c, existing.UserID, "") {
+		return
+	}
+
 	var req UpdateCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		util.RespondBadRequest(c, err.Error())
@@ -166,7 +196,7 @@ func (h *CommentHandler) UpdateComment(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, toCommentResponse(comment))
+	h.respondWithComment(c, http.StatusOK, comment)
 }
 
 // DeleteComment handles DELETE /api/comments/:id
@@ -185,6 +215,15 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 		return
 	}
 
+	existing, err := h.service.GetComment(uint(id))
+	if err != nil {
+		util.RespondNotFound(c, "Comment")
+		return
+	}
+	if !authorizeOwner(c, existing.UserID, middleware.PermissionCommentsModerate) {
+		return
+	}
+
 	if err := h.service.DeleteComment(uint(id)); err != nil {
 		util.RespondNotFound(c, "Comment")
 		return
@@ -215,7 +254,7 @@ func (h *CommentHandler) ApproveComment(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, toCommentResponse(comment))
+	h.respondWithComment(c, http.StatusOK, comment)
 }
 
 // RejectComment handles POST /api/comments/:id/reject
@@ -240,11 +279,41 @@ func (h *CommentHandler) RejectComment(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, toCommentResponse(comment))
+	h.respondWithComment(c, http.StatusOK, comment)
 }
 
 // Helper functions
 
+// respondWithComment responds with a comment and its reaction counts
+func (h *CommentHandler) respondWithComment(c *gin.Context, status int, comment *models.Comment) {
+	responses, err := toCommentsResponseWithReactions(c, h.reactions, []models.Comment{*comment})
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve reactions")
+		return
+	}
+	c.JSON(status, responses[0])
+}
+
+// toCommentsResponseWithReactions converts comments and fills in their reaction
+// counts, flagging the caller's own reactions when they are signed in
+func toCommentsResponseWithReactions(c *gin.Context, reactions service.ReactionService, comments []models.Comment) ([]CommentResponse, error) {
+	ids := make([]uint, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+
+	summaries, err := reactions.Summaries(models.ReactionTargetComment, ids, optionalUserID(c))
+	if err != nil {
+		return nil, err
+	}
+
+	responses := toCommentsResponse(comments)
+	for i := range responses {
+		responses[i].Reactions = toReactionsResponse(summaries[responses[i].ID])
+	}
+	return responses, nil
+}
+
 func toCommentResponse(comment *models.Comment) CommentResponse {
 	return CommentResponse{
 		ID:        comment.ID,
@@ -253,6 +322,7 @@ func toCommentResponse(comment *models.Comment) CommentResponse {
 		ParentID:  comment.ParentID,
 		Content:   comment.Content,
 		Status:    comment.Status,
+		Reactions: []ReactionResponse{},
 		CreatedAt: comment.CreatedAt,
 		UpdatedAt: comment.UpdatedAt,
 	}
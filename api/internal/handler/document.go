@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serveDocument writes a rendered public document such as a feed or sitemap, compressed
+// with gzip when the client accepts it. Documents are rendered deterministically, so a
+// hash of the served bytes is a strong validator. ServeContent answers If-None-Match and
+// If-Modified-Since with 304 Not Modified.
+func serveDocument(c *gin.Context, contentType, cacheControl string, body []byte, modified time.Time) {
+	c.Header("Vary", "Accept-Encoding")
+	if acceptsGzip(c.GetHeader("Accept-Encoding")) {
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		if _, err := writer.Write(body); err == nil && writer.Close() == nil {
+			body = compressed.Bytes()
+			c.Header("Content-Encoding", "gzip")
+		}
+	}
+
+	sum := sha256.Sum256(body)
+	c.Header("Content-Type", contentType)
+	c.Header("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	c.Header("Cache-Control", cacheControl)
+	http.ServeContent(c.Writer, c.Request, "", modified, bytes.NewReader(body))
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, explicitly or
+// through *, with a nonzero quality
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "x-gzip" && coding != "*" {
+			continue
+		}
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.EqualFold(strings.TrimSpace(name), "q") {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err != nil || q == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
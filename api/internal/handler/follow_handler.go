@@ -0,0 +1,327 @@
+package handler
+
+import (
+	"errors"
+	"inkstack/internal/models"
+	"inkstack/internal/service"
+	"inkstack/internal/util"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FollowHandler handles HTTP requests for following authors and tags, and the feed built from them
+type FollowHandler struct {
+	service service.FollowService
+	views   postViews
+}
+
+// NewFollowHandler creates a new follow handler
+func NewFollowHandler(service service.FollowService, postService service.PostService, reactions service.ReactionService, bookmarks service.BookmarkService) *FollowHandler {
+	return &FollowHandler{
+		service: service,
+		views:   postViews{posts: postService, reactions: reactions, bookmarks: bookmarks},
+	}
+}
+
+// Request/Response DTOs
+
+type FollowingResponse struct {
+	Type      string    `json:"type"`
+	UserID    uint      `json:"user_id,omitempty"` // Set for authors
+	Tag       string    `json:"tag,omitempty"`     // Set for tags
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserFollowResponse struct {
+	TargetType string    `json:"target_type"`
+	TargetID   uint      `json:"target_id"` // The author's user ID or the tag ID
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type TagResponse struct {
+	Name         string `json:"name"`
+	Followers    int64  `json:"followers"`
+	FollowedByMe bool   `json:"followed_by_me"`
+}
+
+// GetFeed handles GET /api/feed
+// @Summary Get the caller's feed
+// @Description Get published posts by the authors, and with the tags, the caller follows, newest first
+// @Tags follows
+// @Produce json
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param limit query int false "Page size" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/feed [get]
+func (h *FollowHandler) GetFeed(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	beforeAt, beforeID, err := util.DecodeTimeCursor(c.Query("cursor"))
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	posts, more, err := h.service.Feed(userID.(uint), beforeAt, beforeID, limit)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve feed")
+		return
+	}
+
+	postsResponse, err := h.views.toPostsResponse(c, posts)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve feed")
+		return
+	}
+
+	var nextCursor *string
+	if more {
+		last := posts[len(posts)-1]
+		encoded := util.EncodeTimeCursor(*last.PublishedAt, last.ID)
+		nextCursor = &encoded
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"posts":       postsResponse,
+		"next_cursor": nextCursor,
+	})
+}
+
+// ListFollowing handles GET /api/me/following
+// @Summary List followed authors and tags
+// @Description Get the authors and tags the caller follows, newest first
+// @Tags follows
+// @Produce json
+// @Param type query string false "Only authors or tags (author, tag)"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/me/following [get]
+func (h *FollowHandler) ListFollowing(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	following, total, err := h.service.ListFollowing(userID.(uint), c.Query("type"), page, pageSize)
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"following":  toFollowingsResponse(following),
+		"pagination": util.CalculatePagination(page, pageSize, total),
+	})
+}
+
+// FollowAuthor handles PUT /api/me/following/authors/:user_id
+// @Summary Follow an author
+// @Description Add an author's published posts to the caller's feed. Following an already followed author succeeds.
+// @Tags follows
+// @Produce json
+// @Param user_id path int true "Author's user ID"
+// @Success 200 {object} FollowingResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /api/me/following/authors/{user_id} [put]
+func (h *FollowHandler) FollowAuthor(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	authorID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid user ID")
+		return
+	}
+
+	following, err := h.service.FollowAuthor(c.Request.Context(), userID.(uint), uint(authorID))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAuthorNotFound):
+			util.RespondNotFound(c, "Author")
+		case errors.Is(err, service.ErrAuthorsUnavailable):
+			util.RespondWithError(c, http.StatusBadGateway, "failed to retrieve author profile")
+		default:
+			util.RespondBadRequest(c, err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, toFollowingResponse(following))
+}
+
+// UnfollowAuthor handles DELETE /api/me/following/authors/:user_id
+// @Summary Unfollow an author
+// @Description Remove an author's posts from the caller's feed
+// @Tags follows
+// @Param user_id path int true "Author's user ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/me/following/authors/{user_id} [delete]
+func (h *FollowHandler) UnfollowAuthor(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	authorID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid user ID")
+		return
+	}
+
+	if err := h.service.UnfollowAuthor(userID.(uint), uint(authorID)); err != nil {
+		if errors.Is(err, service.ErrFollowNotFound) {
+			util.RespondNotFound(c, "Follow")
+			return
+		}
+		util.RespondInternalError(c, "failed to unfollow author")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// FollowTag handles PUT /api/me/following/tags/:tag
+// @Summary Follow a tag
+// @Description Add published posts with a tag to the caller's feed. Following an already followed tag succeeds.
+// @Tags follows
+// @Produce json
+// @Param tag path string true "Tag name"
+// @Success 200 {object} FollowingResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/me/following/tags/{tag} [put]
+func (h *FollowHandler) FollowTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	following, err := h.service.FollowTag(userID.(uint), c.Param("tag"))
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toFollowingResponse(following))
+}
+
+// UnfollowTag handles DELETE /api/me/following/tags/:tag
+// @Summary Unfollow a tag
+// @Description Remove posts with a tag from the caller's feed
+// @Tags follows
+// @Param tag path string true "Tag name"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/me/following/tags/{tag} [delete]
+func (h *FollowHandler) UnfollowTag(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	if err := h.service.UnfollowTag(userID.(uint), c.Param("tag")); err != nil {
+		if errors.Is(err, service.ErrFollowNotFound) {
+			util.RespondNotFound(c, "Follow")
+			return
+		}
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// GetTag handles GET /api/tags/:tag
+// @Summary Get a tag
+// @Description Get a tag's follower count, and whether the caller follows it when signed in
+// @Tags follows
+// @Produce json
+// @Param tag path string true "Tag name"
+// @Success 200 {object} TagResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/tags/{tag} [get]
+func (h *FollowHandler) GetTag(c *gin.Context) {
+	tag, followers, err := h.service.GetTag(c.Param("tag"))
+	if err != nil {
+		if errors.Is(err, service.ErrTagNotFound) {
+			util.RespondNotFound(c, "Tag")
+			return
+		}
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	followedByMe, err := h.service.IsFollowing(optionalUserID(c), models.FollowTargetTag, tag.ID)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, TagResponse{
+		Name:         tag.Name,
+		Followers:    followers,
+		FollowedByMe: followedByMe,
+	})
+}
+
+// Helper functions
+
+func toFollowingResponse(following *service.Following) FollowingResponse {
+	response := FollowingResponse{
+		Type:      following.Type,
+		CreatedAt: following.CreatedAt,
+	}
+	if following.Type == models.FollowTargetTag {
+		response.Tag = following.Name
+	} else {
+		response.UserID = following.ID
+	}
+	return response
+}
+
+func toFollowingsResponse(followings []service.Following) []FollowingResponse {
+	responses := make([]FollowingResponse, len(followings))
+	for i, following := range followings {
+		responses[i] = toFollowingResponse(&following)
+	}
+	return responses
+}
+
+func toUserFollowsResponse(follows []models.Follow) []UserFollowResponse {
+	responses := make([]UserFollowResponse, len(follows))
+	for i, follow := range follows {
+		responses[i] = UserFollowResponse{
+			TargetType: follow.TargetType,
+			TargetID:   follow.TargetID,
+			CreatedAt:  follow.CreatedAt,
+		}
+	}
+	return responses
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"inkstack/internal/service"
+	"inkstack/internal/util"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InternalHandler handles HTTP requests from other Inkstack services
+type InternalHandler struct {
+	userDataService service.UserDataService
+}
+
+// NewInternalHandler creates a new internal handler
+func NewInternalHandler(userDataService service.UserDataService) *InternalHandler {
+	return &InternalHandler{userDataService: userDataService}
+}
+
+// ExportUserData handles GET /internal/users/:id/export
+// Called by the auth service to include a user's posts, comments, blocks, mutes, reactions, bookmarks, follows and notifications in their data export.
+func (h *InternalHandler) ExportUserData(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || userID == 0 {
+		util.RespondBadRequest(c, "invalid user ID")
+		return
+	}
+
+	data, err := h.userDataService.ExportUserData(uint(userID))
+	if err != nil {
+		util.RespondInternalError(c, "failed to export user data")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"posts":          toPostsResponse(data.Posts),
+		"comments":       toCommentsResponse(data.Comments),
+		"relationships":  toRelationshipsResponse(data.Relationships),
+		"reactions":      toUserReactionsResponse(data.Reactions),
+		"bookmarks":      toUserBookmarksResponse(data.Bookmarks),
+		"bookmark_lists": toBookmarkListsResponse(data.BookmarkLists),
+		"follows":        toUserFollowsResponse(data.Follows),
+		"notifications":  toNotificationsResponse(data.Notifications),
+	})
+}
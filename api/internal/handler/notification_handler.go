@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"errors"
+	"inkstack/internal/models"
+	"inkstack/internal/service"
+	"inkstack/internal/util"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeat is how often an idle notification stream sends a ping, so proxies
+// don't close it and clients notice dropped connections
+const streamHeartbeat = 25 * time.Second
+
+// NotificationHandler handles HTTP requests for the notification inbox
+type NotificationHandler struct {
+	service service.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(service service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// Request/Response DTOs
+
+type NotificationResponse struct {
+	ID        uint       `json:"id"`
+	Type      string     `json:"type"`
+	ActorID   *uint      `json:"actor_id"` // Null for moderation outcomes
+	PostID    *uint      `json:"post_id"`
+	CommentID *uint      `json:"comment_id"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ListNotifications handles GET /api/me/notifications
+// @Summary List notifications
+// @Description Get the caller's notifications, newest first, with the number still unread
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param limit query int false "Page size" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/me/notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	unreadOnly, err := strconv.ParseBool(c.DefaultQuery("unread", "false"))
+	if err != nil {
+		util.RespondBadRequest(c, "invalid unread filter")
+		return
+	}
+
+	cursor, err := util.DecodeCursor(c.Query("cursor"))
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	notifications, next, err := h.service.ListNotifications(userID.(uint), unreadOnly, cursor, limit)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve notifications")
+		return
+	}
+
+	unreadCount, err := h.service.UnreadCount(userID.(uint))
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve notifications")
+		return
+	}
+
+	var nextCursor *string
+	if next != 0 {
+		encoded := util.EncodeCursor(next)
+		nextCursor = &encoded
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": toNotificationsResponse(notifications),
+		"unread_count":  unreadCount,
+		"next_cursor":   nextCursor,
+	})
+}
+
+// MarkRead handles POST /api/me/notifications/:id/read
+// @Summary Mark a notification read
+// @Description Mark one of the caller's notifications read. Marking it again keeps the original read time.
+// @Tags notifications
+// @Param id path int true "Notification ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/me/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid notification ID")
+		return
+	}
+
+	if err := h.service.MarkRead(userID.(uint), uint(id)); err != nil {
+		if errors.Is(err, service.ErrNotificationNotFound) {
+			util.RespondNotFound(c, "Notification")
+			return
+		}
+		util.RespondInternalError(c, "failed to mark notification read")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// MarkAllRead handles POST /api/me/notifications/read-all
+// @Summary Mark all notifications read
+// @Description Mark every unread notification of the caller read
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/me/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	count, err := h.service.MarkAllRead(userID.(uint))
+	if err != nil {
+		util.RespondInternalError(c, "failed to mark notifications read")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"marked_read": count,
+	})
+}
+
+// StreamNotifications handles GET /api/me/notifications/stream
+// @Summary Stream notifications
+// @Description Server-Sent Events stream of the caller's new notifications. Sends an unread_count event on connect, a notification event for each new notification and a ping event when idle. Notifications missed while disconnected are in the inbox.
+// @Tags notifications
+// @Produce text/event-stream
+// @Success 200 {object} NotificationResponse
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/me/notifications/stream [get]
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	// Subscribe before counting so nothing created in between is missed
+	notifications, unsubscribe := h.service.Subscribe(userID.(uint))
+	defer unsubscribe()
+
+	unreadCount, err := h.service.UnreadCount(userID.(uint))
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve notifications")
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx buffering the stream
+	c.SSEvent("unread_count", gin.H{"unread_count": unreadCount})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case notification, ok := <-notifications:
+			if !ok {
+				// The server is shutting down
+				return false
+			}
+			c.SSEvent("notification", toNotificationResponse(&notification))
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", gin.H{"time": time.Now().UTC()})
+			return true
+		}
+	})
+}
+
+// Helper functions
+
+func toNotificationResponse(notification *models.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:        notification.ID,
+		Type:      notification.Type,
+		ActorID:   notification.ActorID,
+		PostID:    notification.PostID,
+		CommentID: notification.CommentID,
+		Read:      notification.ReadAt != nil,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+func toNotificationsResponse(notifications []models.Notification) []NotificationResponse {
+	responses := make([]NotificationResponse, len(notifications))
+	for i := range notifications {
+		responses[i] = toNotificationResponse(&notifications[i])
+	}
+	return responses
+}
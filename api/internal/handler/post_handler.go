@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"inkstack/internal/middleware"
 	"inkstack/internal/models"
 	"inkstack/internal/service"
 	"inkstack/internal/util"
@@ -14,43 +15,59 @@ import (
 // PostHandler handles HTTP requests for posts
 type PostHandler struct {
 	service service.PostService
+	views   postViews
 }
 
 // NewPostHandler creates a new post handler
-func NewPostHandler(service service.PostService) *PostHandler {
-	return &PostHandler{service: service}
+func NewPostHandler(service service.PostService, reactions service.ReactionService, bookmarks service.BookmarkService) *PostHandler {
+	return &PostHandler{
+		service: service,
+		views:   postViews{posts: service, reactions: reactions, bookmarks: bookmarks},
+	}
+}
+
+// postViews fills in the parts of post responses that are not stored on the post itself
+type postViews struct {
+	posts     service.PostService
+	reactions service.ReactionService
+	bookmarks service.BookmarkService
 }
 
 // Request/Response DTOs
 
 type CreatePostRequest struct {
-	Title   string `json:"title" binding:"required,max=255"`
-	Content string `json:"content" binding:"required"`
-	Excerpt string `json:"excerpt"`
-	Slug    string `json:"slug"`
+	Title   string   `json:"title" binding:"required,max=255"`
+	Content string   `json:"content" binding:"required"`
+	Excerpt string   `json:"excerpt"`
+	Slug    string   `json:"slug"`
+	Tags    []string `json:"tags"`
 	// AuthorID is extracted from JWT token, not from request body
 }
 
 type UpdatePostRequest struct {
-	Title   *string `json:"title" binding:"omitempty,max=255"`
-	Content *string `json:"content"`
-	Excerpt *string `json:"excerpt"`
-	Slug    *string `json:"slug"`
-	Status  *string `json:"status" binding:"omitempty,oneof=draft published archived"`
+	Title   *string   `json:"title" binding:"omitempty,max=255"`
+	Content *string   `json:"content"`
+	Excerpt *string   `json:"excerpt"`
+	Slug    *string   `json:"slug"`
+	Status  *string   `json:"status" binding:"omitempty,oneof=draft published archived"`
+	Tags    *[]string `json:"tags"` // Replaces all tags when present
 }
 
 type PostResponse struct {
-	ID          uint       `json:"id"`
-	Title       string     `json:"title"`
-	Slug        string     `json:"slug"`
-	Content     string     `json:"content"`
-	Excerpt     string     `json:"excerpt"`
-	AuthorID    uint       `json:"author_id"`
-	Status      string     `json:"status"`
-	PublishedAt *time.Time `json:"published_at"`
-	ViewCount   int        `json:"view_count"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
+	ID          uint               `json:"id"`
+	Title       string             `json:"title"`
+	Slug        string             `json:"slug"`
+	Content     string             `json:"content"`
+	Excerpt     string             `json:"excerpt"`
+	AuthorID    uint               `json:"author_id"`
+	Status      string             `json:"status"`
+	Tags        []string           `json:"tags"`
+	PublishedAt *time.Time         `json:"published_at"`
+	ViewCount   int                `json:"view_count"`
+	Reactions   []ReactionResponse `json:"reactions"`
+	Bookmarked  bool               `json:"bookmarked"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
 }
 
 // CreatePost handles POST /api/posts
@@ -77,13 +94,13 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 		return
 	}
 
-	post, err := h.service.CreatePost(req.Title, req.Content, req.Excerpt, req.Slug, userID.(uint))
+	post, err := h.service.CreatePost(req.Title, req.Content, req.Excerpt, req.Slug, userID.(uint), req.Tags)
 	if err != nil {
 		util.RespondBadRequest(c, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusCreated, toPostResponse(post))
+	h.respondWithPost(c, http.StatusCreated, post)
 }
 
 // GetPost handles GET /api/posts/:id
@@ -109,7 +126,7 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, toPostResponse(post))
+	h.respondWithPost(c, http.StatusOK, post)
 }
 
 // GetPostBySlug handles GET /api/posts/slug/:slug
@@ -135,7 +152,7 @@ func (h *PostHandler) GetPostBySlug(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, toPostResponse(post))
+	h.respondWithPost(c, http.StatusOK, post)
 }
 
 // ListPosts handles GET /api/posts
@@ -174,7 +191,11 @@ func (h *PostHandler) ListPosts(c *gin.Context) {
 	}
 
 	pagination := util.CalculatePagination(page, pageSize, total)
-	postsResponse := toPostsResponse(posts)
+	postsResponse, err := h.views.toPostsResponse(c, posts)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve posts")
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"posts":      postsResponse,
@@ -200,6 +221,15 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
+	existing, err := h.service.FindPost(uint(id))
+	if err != nil {
+		util.RespondNotFound(c, "Post")
+		return
+	}
+	if !authorizeOwner(c, existing.AuthorID, middleware.PermissionPostsEditAny) {
+		return
+	}
+
 	var req UpdatePostRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		util.RespondBadRequest(c, err.Error())
@@ -223,6 +253,9 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 	if req.Status != nil {
 		updates["status"] = *req.Status
 	}
+	if req.Tags != nil {
+		updates["tags"] = *req.Tags
+	}
 
 	post, err := h.service.UpdatePost(uint(id), updates)
 	if err != nil {
@@ -230,7 +263,7 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, toPostResponse(post))
+	h.respondWithPost(c, http.StatusOK, post)
 }
 
 // DeletePost handles DELETE /api/posts/:id
@@ -249,6 +282,15 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 		return
 	}
 
+	existing, err := h.service.FindPost(uint(id))
+	if err != nil {
+		util.RespondNotFound(c, "Post")
+		return
+	}
+	if !authorizeOwner(c, existing.AuthorID, middleware.PermissionPostsEditAny) {
+		return
+	}
+
 	if err := h.service.DeletePost(uint(id)); err != nil {
 		util.RespondNotFound(c, "Post")
 		return
@@ -273,13 +315,22 @@ func (h *PostHandler) PublishPost(c *gin.Context) {
 		return
 	}
 
+	existing, err := h.service.FindPost(uint(id))
+	if err != nil {
+		util.RespondNotFound(c, "Post")
+		return
+	}
+	if !authorizeOwner(c, existing.AuthorID, middleware.PermissionPostsEditAny) {
+		return
+	}
+
 	post, err := h.service.PublishPost(uint(id))
 	if err != nil {
 		util.RespondBadRequest(c, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, toPostResponse(post))
+	h.respondWithPost(c, http.StatusOK, post)
 }
 
 // UnpublishPost handles POST /api/posts/:id/unpublish
@@ -298,17 +349,71 @@ func (h *PostHandler) UnpublishPost(c *gin.Context) {
 		return
 	}
 
+	existing, err := h.service.FindPost(uint(id))
+	if err != nil {
+		util.RespondNotFound(c, "Post")
+		return
+	}
+	if !authorizeOwner(c, existing.AuthorID, middleware.PermissionPostsEditAny) {
+		return
+	}
+
 	post, err := h.service.UnpublishPost(uint(id))
 	if err != nil {
 		util.RespondBadRequest(c, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, toPostResponse(post))
+	h.respondWithPost(c, http.StatusOK, post)
 }
 
 // Helper functions
 
+// respondWithPost responds with a post, its tags and reaction counts, and whether the caller bookmarked it
+func (h *PostHandler) respondWithPost(c *gin.Context, status int, post *models.Post) {
+	responses, err := h.views.toPostsResponse(c, []models.Post{*post})
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve post")
+		return
+	}
+	c.JSON(status, responses[0])
+}
+
+// toPostsResponse converts posts and fills in their tags and reaction counts. When the
+// caller is signed in, it also flags their own reactions and the posts they bookmarked.
+func (v postViews) toPostsResponse(c *gin.Context, posts []models.Post) ([]PostResponse, error) {
+	ids := make([]uint, len(posts))
+	for i, post := range posts {
+		ids[i] = post.ID
+	}
+	viewerID := optionalUserID(c)
+
+	tags, err := v.posts.TagsByPost(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := v.reactions.Summaries(models.ReactionTargetPost, ids, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarked, err := v.bookmarks.BookmarkedPostIDs(viewerID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := toPostsResponse(posts)
+	for i := range responses {
+		if postTags, ok := tags[responses[i].ID]; ok {
+			responses[i].Tags = postTags
+		}
+		responses[i].Reactions = toReactionsResponse(summaries[responses[i].ID])
+		responses[i].Bookmarked = bookmarked[responses[i].ID]
+	}
+	return responses, nil
+}
+
 func toPostResponse(post *models.Post) PostResponse {
 	return PostResponse{
 		ID:          post.ID,
@@ -318,8 +423,10 @@ func toPostResponse(post *models.Post) PostResponse {
 		Excerpt:     post.Excerpt,
 		AuthorID:    post.AuthorID,
 		Status:      post.Status,
+		Tags:        []string{},
 		PublishedAt: post.PublishedAt,
 		ViewCount:   post.ViewCount,
+		Reactions:   []ReactionResponse{},
 		CreatedAt:   post.CreatedAt,
 		UpdatedAt:   post.UpdatedAt,
 	}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"inkstack/internal/models"
+	"inkstack/internal/service"
+	"inkstack/internal/util"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReactionHandler handles HTTP requests for reactions on posts and comments
+type ReactionHandler struct {
+	service service.ReactionService
+}
+
+// NewReactionHandler creates a new reaction handler
+func NewReactionHandler(service service.ReactionService) *ReactionHandler {
+	return &ReactionHandler{service: service}
+}
+
+// Request/Response DTOs
+
+type ReactionResponse struct {
+	Type        string `json:"type"`
+	Count       int64  `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"`
+}
+
+type UserReactionResponse struct {
+	TargetType string    `json:"target_type"`
+	TargetID   uint      `json:"target_id"`
+	Type       string    `json:"type"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ToggleReactionResponse struct {
+	Type      string             `json:"type"`
+	Reacted   bool               `json:"reacted"`
+	Reactions []ReactionResponse `json:"reactions"`
+}
+
+// ListReactionTypes handles GET /api/reactions
+// @Summary List reaction types
+// @Description Get the reaction types that can be left on posts and comments, in display order
+// @Tags reactions
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/reactions [get]
+func (h *ReactionHandler) ListReactionTypes(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"types": h.service.Types(),
+	})
+}
+
+// TogglePostReaction handles POST /api/posts/:id/reactions/:type
+// @Summary Toggle a reaction on a post
+// @Description Add the caller's reaction to a published post, or remove it if already present
+// @Tags reactions
+// @Produce json
+// @Param id path int true "Post ID"
+// @Param type path string true "Reaction type"
+// @Success 200 {object} ToggleReactionResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/posts/{id}/reactions/{type} [post]
+func (h *ReactionHandler) TogglePostReaction(c *gin.Context) {
+	h.toggle(c, models.ReactionTargetPost, "Post")
+}
+
+// ToggleCommentReaction handles POST /api/comments/:id/reactions/:type
+// @Summary Toggle a reaction on a comment
+// @Description Add the caller's reaction to a comment on a post they can read, or remove it if already present
+// @Tags reactions
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param type path string true "Reaction type"
+// @Success 200 {object} ToggleReactionResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/comments/{id}/reactions/{type} [post]
+func (h *ReactionHandler) ToggleCommentReaction(c *gin.Context) {
+	h.toggle(c, models.ReactionTargetComment, "Comment")
+}
+
+// toggle adds or removes the caller's reaction and responds with the target's updated counts
+func (h *ReactionHandler) toggle(c *gin.Context, targetType, resource string) {
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid "+targetType+" ID")
+		return
+	}
+
+	userID := c.GetUint("user_id")
+	reactionType := c.Param("type")
+
+	reacted, err := h.service.Toggle(targetType, uint(targetID), userID, reactionType)
+	if err != nil {
+		if errors.Is(err, service.ErrReactionTargetNotFound) {
+			util.RespondNotFound(c, resource)
+			return
+		}
+		if errors.Is(err, service.ErrReactionBlocked) {
+			util.RespondWithError(c, http.StatusForbidden, err.Error())
+			return
+		}
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	summary, err := h.service.Summary(targetType, uint(targetID), userID)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve reactions")
+		return
+	}
+
+	c.JSON(http.StatusOK, ToggleReactionResponse{
+		Type:      reactionType,
+		Reacted:   reacted,
+		Reactions: toReactionsResponse(summary),
+	})
+}
+
+// Helper functions
+
+func toReactionsResponse(summaries []service.ReactionSummary) []ReactionResponse {
+	responses := make([]ReactionResponse, len(summaries))
+	for i, summary := range summaries {
+		responses[i] = ReactionResponse{
+			Type:        summary.Type,
+			Count:       summary.Count,
+			ReactedByMe: summary.ReactedByMe,
+		}
+	}
+	return responses
+}
+
+func toUserReactionsResponse(reactions []models.Reaction) []UserReactionResponse {
+	responses := make([]UserReactionResponse, len(reactions))
+	for i, reaction := range reactions {
+		responses[i] = UserReactionResponse{
+			TargetType: reaction.TargetType,
+			TargetID:   reaction.TargetID,
+			Type:       reaction.Type,
+			CreatedAt:  reaction.CreatedAt,
+		}
+	}
+	return responses
+}
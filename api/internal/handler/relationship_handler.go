@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"errors"
+	"inkstack/internal/models"
+	"inkstack/internal/service"
+	"inkstack/internal/util"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RelationshipHandler handles HTTP requests for blocking and muting users
+type RelationshipHandler struct {
+	service service.RelationshipService
+}
+
+// NewRelationshipHandler creates a new relationship handler
+func NewRelationshipHandler(service service.RelationshipService) *RelationshipHandler {
+	return &RelationshipHandler{service: service}
+}
+
+type RelationshipResponse struct {
+	UserID    uint      `json:"user_id"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListBlocks handles GET /api/me/blocks
+// @Summary List blocked users
+// @Description Get the users the caller has blocked. Blocked users cannot comment on the caller's posts or reply to their comments.
+// @Tags relationships
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/me/blocks [get]
+func (h *RelationshipHandler) ListBlocks(c *gin.Context) {
+	h.list(c, models.RelationshipBlock, "blocks")
+}
+
+// Block handles PUT /api/me/blocks/:user_id
+// @Summary Block a user
+// @Description Stop a user from commenting on the caller's posts or replying to their comments. Blocking an already blocked user succeeds.
+// @Tags relationships
+// @Produce json
+// @Param user_id path int true "User ID to block"
+// @Success 200 {object} RelationshipResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/me/blocks/{user_id} [put]
+func (h *RelationshipHandler) Block(c *gin.Context) {
+	h.add(c, models.RelationshipBlock)
+}
+
+// Unblock handles DELETE /api/me/blocks/:user_id
+// @Summary Unblock a user
+// @Description Remove a block
+// @Tags relationships
+// @Produce json
+// @Param user_id path int true "Blocked user ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/me/blocks/{user_id} [delete]
+func (h *RelationshipHandler) Unblock(c *gin.Context) {
+	h.remove(c, models.RelationshipBlock, "Block")
+}
+
+// ListMutes handles GET /api/me/mutes
+// @Summary List muted users
+// @Description Get the users the caller has muted. Comments by muted users are left out of comment listings for the caller.
+// @Tags relationships
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/me/mutes [get]
+func (h *RelationshipHandler) ListMutes(c *gin.Context) {
+	h.list(c, models.RelationshipMute, "mutes")
+}
+
+// Mute handles PUT /api/me/mutes/:user_id
+// @Summary Mute a user
+// @Description Hide a user's comments from the caller. Muting an already muted user succeeds.
+// @Tags relationships
+// @Produce json
+// @Param user_id path int true "User ID to mute"
+// @Success 200 {object} RelationshipResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/me/mutes/{user_id} [put]
+func (h *RelationshipHandler) Mute(c *gin.Context) {
+	h.add(c, models.RelationshipMute)
+}
+
+// Unmute handles DELETE /api/me/mutes/:user_id
+// @Summary Unmute a user
+// @Description Remove a mute
+// @Tags relationships
+// @Produce json
+// @Param user_id path int true "Muted user ID"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/me/mutes/{user_id} [delete]
+func (h *RelationshipHandler) Unmute(c *gin.Context) {
+	h.remove(c, models.RelationshipMute, "Mute")
+}
+
+// list responds with a page of the caller's relationships of one kind
+func (h *RelationshipHandler) list(c *gin.Context, kind, key string) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	relationships, total, err := h.service.List(userID.(uint), kind, page, pageSize)
+	if err != nil {
+		util.RespondInternalError(c, "failed to retrieve "+key)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		key:          toRelationshipsResponse(relationships),
+		"pagination": util.CalculatePagination(page, pageSize, total),
+	})
+}
+
+// add blocks or mutes the user named in the path
+func (h *RelationshipHandler) add(c *gin.Context, kind string) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid user ID")
+		return
+	}
+
+	relationship, err := h.service.Add(userID.(uint), uint(targetID), kind)
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, toRelationshipResponse(relationship))
+}
+
+// remove unblocks or unmutes the user named in the path
+func (h *RelationshipHandler) remove(c *gin.Context, kind, resource string) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	targetID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid user ID")
+		return
+	}
+
+	if err := h.service.Remove(userID.(uint), uint(targetID), kind); err != nil {
+		if errors.Is(err, service.ErrRelationshipNotFound) {
+			util.RespondNotFound(c, resource)
+			return
+		}
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Helper functions
+
+func toRelationshipResponse(relationship *models.UserRelationship) RelationshipResponse {
+	return RelationshipResponse{
+		UserID:    relationship.TargetID,
+		Kind:      relationship.Kind,
+		CreatedAt: relationship.CreatedAt,
+	}
+}
+
+func toRelationshipsResponse(relationships []models.UserRelationship) []RelationshipResponse {
+	responses := make([]RelationshipResponse, len(relationships))
+	for i, relationship := range relationships {
+		responses[i] = toRelationshipResponse(&relationship)
+	}
+	return responses
+}
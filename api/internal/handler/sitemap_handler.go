@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/xml"
+	"errors"
+	"inkstack/internal/service"
+	"inkstack/internal/util"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sitemapMaxAge is how long clients and proxies may cache a sitemap before revalidating
+const sitemapMaxAge = "public, max-age=3600"
+
+// sitemapNS is the sitemaps.org protocol namespace (https://www.sitemaps.org/protocol.html)
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// sitemapFilePattern matches sitemap file names such as posts-2.xml
+var sitemapFilePattern = regexp.MustCompile(`^([a-z]+)-([1-9][0-9]{0,8})\.xml$`)
+
+// SitemapHandler handles HTTP requests for sitemaps and robots.txt
+type SitemapHandler struct {
+	service service.SitemapService
+}
+
+// NewSitemapHandler creates a new sitemap handler
+func NewSitemapHandler(service service.SitemapService) *SitemapHandler {
+	return &SitemapHandler{service: service}
+}
+
+// Sitemap documents
+
+type sitemapIndexDocument struct {
+	XMLName  xml.Name         `xml:"sitemapindex"`
+	XMLNS    string           `xml:"xmlns,attr"`
+	Sitemaps []sitemapElement `xml:"sitemap"`
+}
+
+type sitemapElement struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type urlSetDocument struct {
+	XMLName xml.Name         `xml:"urlset"`
+	XMLNS   string           `xml:"xmlns,attr"`
+	URLs    []sitemapElement `xml:"url"`
+}
+
+// Index handles GET /sitemap.xml
+// @Summary Sitemap index
+// @Description Sitemap index listing a sitemap for the site's fixed pages and a sitemap per 50,000 published posts, authors and tags. Served gzip-compressed when accepted; supports conditional GET with ETag and Last-Modified.
+// @Tags sitemaps
+// @Produce xml
+// @Success 200 {string} string
+// @Router /sitemap.xml [get]
+func (h *SitemapHandler) Index(c *gin.Context) {
+	sitemaps, err := h.service.Index()
+	if err != nil {
+		util.RespondInternalError(c, "failed to build sitemap index")
+		return
+	}
+
+	body, err := marshalXML(sitemapIndexDocument{XMLNS: sitemapNS, Sitemaps: toSitemapElements(sitemaps)})
+	if err != nil {
+		util.RespondInternalError(c, "failed to build sitemap index")
+		return
+	}
+
+	serveDocument(c, "application/xml; charset=utf-8", sitemapMaxAge, body, sitemapUpdated(sitemaps))
+}
+
+// Sitemap handles GET /sitemaps/:file
+// @Summary Sitemap
+// @Description One page of a sitemap section, named {section}-{page}.xml, where section is pages, posts, authors or tags. Served gzip-compressed when accepted; supports conditional GET with ETag and Last-Modified.
+// @Tags sitemaps
+// @Produce xml
+// @Param file path string true "Sitemap file, e.g. posts-1.xml"
+// @Success 200 {string} string
+// @Failure 404 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /sitemaps/{file} [get]
+func (h *SitemapHandler) Sitemap(c *gin.Context) {
+	match := sitemapFilePattern.FindStringSubmatch(c.Param("file"))
+	if match == nil {
+		util.RespondNotFound(c, "Sitemap")
+		return
+	}
+	page, _ := strconv.Atoi(match[2])
+
+	urls, err := h.service.Sitemap(c.Request.Context(), match[1], page)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSitemapNotFound):
+			util.RespondNotFound(c, "Sitemap")
+		case errors.Is(err, service.ErrAuthorsUnavailable):
+			util.RespondWithError(c, http.StatusBadGateway, "failed to retrieve author profiles")
+		default:
+			util.RespondInternalError(c, "failed to build sitemap")
+		}
+		return
+	}
+
+	body, err := marshalXML(urlSetDocument{XMLNS: sitemapNS, URLs: toSitemapElements(urls)})
+	if err != nil {
+		util.RespondInternalError(c, "failed to build sitemap")
+		return
+	}
+
+	serveDocument(c, "application/xml; charset=utf-8", sitemapMaxAge, body, sitemapUpdated(urls))
+}
+
+// Robots handles GET /robots.txt
+// @Summary robots.txt
+// @Description Crawler rules for the site, pointing at the sitemap index
+// @Tags sitemaps
+// @Produce plain
+// @Success 200 {string} string
+// @Router /robots.txt [get]
+func (h *SitemapHandler) Robots(c *gin.Context) {
+	c.Header("Cache-Control", sitemapMaxAge)
+	c.String(http.StatusOK, h.service.Robots())
+}
+
+// Helper functions
+
+func toSitemapElements(urls []service.SitemapURL) []sitemapElement {
+	elements := make([]sitemapElement, len(urls))
+	for i, u := range urls {
+		elements[i] = sitemapElement{Loc: u.Loc}
+		if !u.LastModified.IsZero() {
+			elements[i].LastMod = u.LastModified.UTC().Format(time.RFC3339)
+		}
+	}
+	return elements
+}
+
+// sitemapUpdated returns when any URL in a sitemap last changed, or zero if unknown
+func sitemapUpdated(urls []service.SitemapURL) time.Time {
+	var updated time.Time
+	for _, u := range urls {
+		if u.LastModified.After(updated) {
+			updated = u.LastModified
+		}
+	}
+	return updated
+}
@@ -0,0 +1,452 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"html"
+	"inkstack/internal/service"
+	"inkstack/internal/util"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Syndication formats
+const (
+	formatRSS  = "rss"
+	formatAtom = "atom"
+	formatJSON = "json"
+)
+
+// Feed content modes, chosen with ?content=
+const (
+	feedContentFull    = "full"
+	feedContentExcerpt = "excerpt"
+)
+
+// feedMaxAge is how long clients and proxies may cache a feed before revalidating
+const feedMaxAge = "public, max-age=300"
+
+// SyndicationHandler handles HTTP requests for RSS, Atom and JSON feeds
+type SyndicationHandler struct {
+	service    service.SyndicationService
+	authClient *service.AuthClient
+}
+
+// NewSyndicationHandler creates a new syndication handler
+func NewSyndicationHandler(service service.SyndicationService, authClient *service.AuthClient) *SyndicationHandler {
+	return &SyndicationHandler{
+		service:    service,
+		authClient: authClient,
+	}
+}
+
+// RSS 2.0 documents (https://www.rssboard.org/rss-specification)
+
+type rssDocument struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomNS    string     `xml:"xmlns:atom,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"content:encoded,omitempty"`
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// Atom 1.0 documents (RFC 4287)
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Author    atomAuthor  `xml:"author"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    atomText       `xml:"summary"`
+	Content    *atomText      `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// JSON Feed 1.1 documents (https://www.jsonfeed.org/version/1.1/)
+
+type jsonFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url"`
+	FeedURL     string           `json:"feed_url"`
+	Description string           `json:"description"`
+	Authors     []jsonFeedAuthor `json:"authors"`
+	Items       []jsonFeedItem   `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonFeedItem struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title"`
+	ContentHTML   string   `json:"content_html,omitempty"`
+	ContentText   string   `json:"content_text,omitempty"`
+	Summary       string   `json:"summary"`
+	DatePublished string   `json:"date_published"`
+	DateModified  string   `json:"date_modified"`
+	Tags          []string `json:"tags,omitempty"`
+}
+
+// RSS handles GET /feed.xml
+// @Summary RSS feed
+// @Description RSS 2.0 feed of the latest published posts, optionally by one author or with one tag. Supports conditional GET with ETag and Last-Modified.
+// @Tags feeds
+// @Produce xml
+// @Param author query string false "Only posts by this author (username)"
+// @Param tag query string false "Only posts with this tag"
+// @Param content query string false "full or excerpt; defaults to FEED_FULL_CONTENT"
+// @Success 200 {string} string
+// @Success 304 {string} string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /feed.xml [get]
+func (h *SyndicationHandler) RSS(c *gin.Context) {
+	h.serve(c, formatRSS)
+}
+
+// Atom handles GET /atom.xml
+// @Summary Atom feed
+// @Description Atom 1.0 feed of the latest published posts, optionally by one author or with one tag. Supports conditional GET with ETag and Last-Modified.
+// @Tags feeds
+// @Produce xml
+// @Param author query string false "Only posts by this author (username)"
+// @Param tag query string false "Only posts with this tag"
+// @Param content query string false "full or excerpt; defaults to FEED_FULL_CONTENT"
+// @Success 200 {string} string
+// @Success 304 {string} string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /atom.xml [get]
+func (h *SyndicationHandler) Atom(c *gin.Context) {
+	h.serve(c, formatAtom)
+}
+
+// JSONFeed handles GET /feed.json
+// @Summary JSON Feed
+// @Description JSON Feed 1.1 of the latest published posts, optionally by one author or with one tag. Supports conditional GET with ETag and Last-Modified.
+// @Tags feeds
+// @Produce json
+// @Param author query string false "Only posts by this author (username)"
+// @Param tag query string false "Only posts with this tag"
+// @Param content query string false "full or excerpt; defaults to FEED_FULL_CONTENT"
+// @Success 200 {object} map[string]interface{}
+// @Success 304 {string} string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /feed.json [get]
+func (h *SyndicationHandler) JSONFeed(c *gin.Context) {
+	h.serve(c, formatJSON)
+}
+
+// serve builds the requested feed and writes it in format, answering conditional requests
+func (h *SyndicationHandler) serve(c *gin.Context, format string) {
+	author, tag, content := c.Query("author"), c.Query("tag"), c.Query("content")
+	if author != "" && tag != "" {
+		util.RespondBadRequest(c, "use either author or tag, not both")
+		return
+	}
+	if content != "" && content != feedContentFull && content != feedContentExcerpt {
+		util.RespondBadRequest(c, "content must be full or excerpt")
+		return
+	}
+
+	var feed *service.SyndicationFeed
+	var err error
+	switch {
+	case author != "":
+		profile, profileErr := h.authClient.GetUserProfile(c.Request.Context(), author)
+		if profileErr != nil {
+			if errors.Is(profileErr, service.ErrUserNotFound) {
+				util.RespondNotFound(c, "Author")
+				return
+			}
+			util.RespondWithError(c, http.StatusBadGateway, "failed to retrieve author profile")
+			return
+		}
+		feed, err = h.service.AuthorFeed(profile)
+	case tag != "":
+		feed, err = h.service.TagFeed(tag)
+	default:
+		feed, err = h.service.SiteFeed()
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrTagNotFound) {
+			util.RespondNotFound(c, "Tag")
+			return
+		}
+		util.RespondInternalError(c, "failed to build feed")
+		return
+	}
+
+	full := feed.FullContent
+	if content != "" {
+		full = content == feedContentFull
+	}
+
+	// The feed's own URL, with its query in a canonical order. The Atom ID leaves out
+	// the content mode, which doesn't make it a different feed.
+	query := url.Values{}
+	for _, key := range []string{"author", "tag"} {
+		if value := c.Query(key); value != "" {
+			query.Set(key, value)
+		}
+	}
+	feedID := feedURL(feed.SiteURL+c.Request.URL.Path, query)
+	if content != "" {
+		query.Set("content", content)
+	}
+	selfURL := feedURL(feed.SiteURL+c.Request.URL.Path, query)
+
+	var body []byte
+	var contentType string
+	switch format {
+	case formatRSS:
+		body, err = renderRSS(feed, selfURL, full)
+		contentType = "application/rss+xml; charset=utf-8"
+	case formatAtom:
+		body, err = renderAtom(feed, selfURL, feedID, full)
+		contentType = "application/atom+xml; charset=utf-8"
+	default:
+		body, err = renderJSONFeed(feed, selfURL, full)
+		contentType = "application/feed+json; charset=utf-8"
+	}
+	if err != nil {
+		util.RespondInternalError(c, "failed to build feed")
+		return
+	}
+
+	serveDocument(c, contentType, feedMaxAge, body, feedUpdated(feed))
+}
+
+// Helper functions
+
+// feedURL appends query to base, if there is one
+func feedURL(base string, query url.Values) string {
+	if len(query) == 0 {
+		return base
+	}
+	return base + "?" + query.Encode()
+}
+
+// feedUpdated returns when a feed's newest change happened, or the zero time if it has no posts
+func feedUpdated(feed *service.SyndicationFeed) time.Time {
+	var updated time.Time
+	for _, item := range feed.Items {
+		if item.UpdatedAt.After(updated) {
+			updated = item.UpdatedAt
+		}
+		if item.PublishedAt.After(updated) {
+			updated = item.PublishedAt
+		}
+	}
+	return updated
+}
+
+func renderRSS(feed *service.SyndicationFeed, selfURL string, full bool) ([]byte, error) {
+	channel := rssChannel{
+		Title:       feed.Title,
+		Link:        feed.HomeURL,
+		Description: feed.Description,
+		AtomLink:    atomLink{Href: selfURL, Rel: "self", Type: "application/rss+xml"},
+		Generator:   "Inkstack",
+		Items:       make([]rssItem, len(feed.Items)),
+	}
+	if updated := feedUpdated(feed); !updated.IsZero() {
+		channel.LastBuildDate = updated.Format(time.RFC1123Z)
+	}
+
+	for i, item := range feed.Items {
+		channel.Items[i] = rssItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			GUID:        rssGUID{IsPermaLink: false, Value: item.ID},
+			PubDate:     item.PublishedAt.Format(time.RFC1123Z),
+			Description: html.EscapeString(item.Summary),
+			Categories:  item.Tags,
+		}
+		if full {
+			channel.Items[i].Content = textToHTML(item.Content)
+		}
+	}
+
+	return marshalXML(rssDocument{
+		Version:   "2.0",
+		AtomNS:    "http://www.w3.org/2005/Atom",
+		ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		Channel:   channel,
+	})
+}
+
+func renderAtom(feed *service.SyndicationFeed, selfURL, feedID string, full bool) ([]byte, error) {
+	// An empty feed still needs an updated time; use a fixed one so the body stays stable
+	updated := feedUpdated(feed)
+	if updated.IsZero() {
+		updated = time.Unix(0, 0).UTC()
+	}
+
+	document := atomFeed{
+		ID:       feedID,
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Updated:  updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: feed.HomeURL, Rel: "alternate", Type: "text/html"},
+			{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+		},
+		Author:    atomAuthor{Name: feed.AuthorName, URI: feed.AuthorURL},
+		Generator: "Inkstack",
+		Entries:   make([]atomEntry, len(feed.Items)),
+	}
+
+	for i, item := range feed.Items {
+		entry := atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Link:      atomLink{Href: item.URL, Rel: "alternate", Type: "text/html"},
+			Published: item.PublishedAt.Format(time.RFC3339),
+			Updated:   item.UpdatedAt.Format(time.RFC3339),
+			Summary:   atomText{Type: "text", Value: item.Summary},
+		}
+		if full {
+			entry.Content = &atomText{Type: "html", Value: textToHTML(item.Content)}
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: tag})
+		}
+		document.Entries[i] = entry
+	}
+
+	return marshalXML(document)
+}
+
+func renderJSONFeed(feed *service.SyndicationFeed, selfURL string, full bool) ([]byte, error) {
+	document := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.HomeURL,
+		FeedURL:     selfURL,
+		Description: feed.Description,
+		Authors:     []jsonFeedAuthor{{Name: feed.AuthorName, URL: feed.AuthorURL}},
+		Items:       make([]jsonFeedItem, len(feed.Items)),
+	}
+
+	for i, item := range feed.Items {
+		document.Items[i] = jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.URL,
+			Title:         item.Title,
+			Summary:       item.Summary,
+			DatePublished: item.PublishedAt.Format(time.RFC3339),
+			DateModified:  item.UpdatedAt.Format(time.RFC3339),
+			Tags:          item.Tags,
+		}
+		// Every item needs content_html or content_text
+		if full {
+			document.Items[i].ContentHTML = textToHTML(item.Content)
+		} else {
+			document.Items[i].ContentText = item.Summary
+		}
+	}
+
+	// Keep the HTML in content_html readable
+	var body bytes.Buffer
+	encoder := json.NewEncoder(&body)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+func marshalXML(document interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// textToHTML renders plain text post content as HTML, with blank-line separated
+// paragraphs and line breaks kept
+func textToHTML(text string) string {
+	var b strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		b.WriteString("</p>")
+	}
+	return b.String()
+}
@@ -0,0 +1,398 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"inkstack/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	testSiteURL = "https://blog.example.com"
+
+	// The plain text content of the test post and the HTML every format must carry for it
+	testContent     = "Tom & Jerry use <b>tags</b>\n\nSecond paragraph\nwith a line break"
+	testContentHTML = "<p>Tom &amp; Jerry use &lt;b&gt;tags&lt;/b&gt;</p><p>Second paragraph<br>with a line break</p>"
+)
+
+var (
+	testPublished = time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	testUpdated   = time.Date(2026, 3, 2, 18, 45, 10, 0, time.UTC)
+)
+
+// fakeSyndicationService serves a fixed feed with one post
+type fakeSyndicationService struct{}
+
+func (fakeSyndicationService) SiteFeed() (*service.SyndicationFeed, error) {
+	return testFeed(), nil
+}
+
+func (fakeSyndicationService) AuthorFeed(author *service.UserProfile) (*service.SyndicationFeed, error) {
+	return testFeed(), nil
+}
+
+func (fakeSyndicationService) TagFeed(tag string) (*service.SyndicationFeed, error) {
+	if tag != "go" {
+		return nil, service.ErrTagNotFound
+	}
+	return testFeed(), nil
+}
+
+func testFeed() *service.SyndicationFeed {
+	return &service.SyndicationFeed{
+		Title:       "Inkstack",
+		Description: "Posts & notes",
+		SiteURL:     testSiteURL,
+		HomeURL:     testSiteURL + "/",
+		AuthorName:  "Inkstack",
+		AuthorURL:   testSiteURL + "/",
+		FullContent: true,
+		Items: []service.SyndicationItem{{
+			ID:          "tag:blog.example.com,2026-02-28:posts/7",
+			URL:         testSiteURL + "/posts/hello-world",
+			Title:       "Hello <world>",
+			Summary:     "Tom & Jerry",
+			Content:     testContent,
+			Tags:        []string{"go", "web"},
+			PublishedAt: testPublished,
+			UpdatedAt:   testUpdated,
+		}},
+	}
+}
+
+func serveFeed(t *testing.T, path string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	h := NewSyndicationHandler(fakeSyndicationService{}, nil)
+	r := gin.New()
+	r.GET("/feed.xml", h.RSS)
+	r.GET("/atom.xml", h.Atom)
+	r.GET("/feed.json", h.JSONFeed)
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// RSS 2.0 with the Atom and content modules, as read by a namespace-aware parser
+
+type testRSS struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		// Before Link, which would otherwise match atom:link too
+		SelfLink struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"http://www.w3.org/2005/Atom link"`
+		Title         string `xml:"title"`
+		Link          string `xml:"link"`
+		Description   string `xml:"description"`
+		LastBuildDate string `xml:"lastBuildDate"`
+		Items         []struct {
+			Title string `xml:"title"`
+			Link  string `xml:"link"`
+			GUID  struct {
+				IsPermaLink string `xml:"isPermaLink,attr"`
+				Value       string `xml:",chardata"`
+			} `xml:"guid"`
+			PubDate     string   `xml:"pubDate"`
+			Description string   `xml:"description"`
+			Content     *string  `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			Categories  []string `xml:"category"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+func TestRSSFeed(t *testing.T) {
+	w := serveFeed(t, "/feed.xml", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/rss+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if !strings.HasPrefix(w.Body.String(), xml.Header) {
+		t.Error("missing XML declaration")
+	}
+
+	var feed testRSS
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+
+	if feed.Version != "2.0" {
+		t.Errorf("version = %q, want 2.0", feed.Version)
+	}
+	channel := feed.Channel
+	if channel.Title != "Inkstack" || channel.Link != testSiteURL+"/" || channel.Description != "Posts & notes" {
+		t.Errorf("channel title, link or description wrong: %+v", channel)
+	}
+	if channel.SelfLink.Href != testSiteURL+"/feed.xml" || channel.SelfLink.Rel != "self" || channel.SelfLink.Type != "application/rss+xml" {
+		t.Errorf("atom:link = %+v", channel.SelfLink)
+	}
+	if built, err := time.Parse(time.RFC1123Z, channel.LastBuildDate); err != nil || !built.Equal(testUpdated) {
+		t.Errorf("lastBuildDate = %q, want RFC 822 date of %v", channel.LastBuildDate, testUpdated)
+	}
+
+	if len(channel.Items) != 1 {
+		t.Fatalf("got %d items, want 1", len(channel.Items))
+	}
+	item := channel.Items[0]
+	if item.Title != "Hello <world>" || item.Link != testSiteURL+"/posts/hello-world" {
+		t.Errorf("item title or link wrong: %q %q", item.Title, item.Link)
+	}
+	if item.GUID.Value != "tag:blog.example.com,2026-02-28:posts/7" || item.GUID.IsPermaLink != "false" {
+		t.Errorf("guid = %+v, want a non-permalink tag URI", item.GUID)
+	}
+	if published, err := time.Parse(time.RFC1123Z, item.PubDate); err != nil || !published.Equal(testPublished) {
+		t.Errorf("pubDate = %q, want RFC 822 date of %v", item.PubDate, testPublished)
+	}
+	// description holds entity-encoded HTML
+	if item.Description != "Tom &amp; Jerry" {
+		t.Errorf("description = %q", item.Description)
+	}
+	if item.Content == nil || *item.Content != testContentHTML {
+		t.Errorf("content:encoded = %v, want %q", item.Content, testContentHTML)
+	}
+	if strings.Join(item.Categories, ",") != "go,web" {
+		t.Errorf("categories = %v", item.Categories)
+	}
+}
+
+// Atom 1.0 (RFC 4287), as read by a namespace-aware parser
+
+type testAtomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type testAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type testAtom struct {
+	XMLName xml.Name       `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string         `xml:"http://www.w3.org/2005/Atom id"`
+	Title   string         `xml:"http://www.w3.org/2005/Atom title"`
+	Updated string         `xml:"http://www.w3.org/2005/Atom updated"`
+	Links   []testAtomLink `xml:"http://www.w3.org/2005/Atom link"`
+	Author  struct {
+		Name string `xml:"http://www.w3.org/2005/Atom name"`
+		URI  string `xml:"http://www.w3.org/2005/Atom uri"`
+	} `xml:"http://www.w3.org/2005/Atom author"`
+	Entries []struct {
+		ID         string        `xml:"http://www.w3.org/2005/Atom id"`
+		Title      string        `xml:"http://www.w3.org/2005/Atom title"`
+		Link       testAtomLink  `xml:"http://www.w3.org/2005/Atom link"`
+		Published  string        `xml:"http://www.w3.org/2005/Atom published"`
+		Updated    string        `xml:"http://www.w3.org/2005/Atom updated"`
+		Summary    testAtomText  `xml:"http://www.w3.org/2005/Atom summary"`
+		Content    *testAtomText `xml:"http://www.w3.org/2005/Atom content"`
+		Categories []struct {
+			Term string `xml:"term,attr"`
+		} `xml:"http://www.w3.org/2005/Atom category"`
+	} `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+func TestAtomFeed(t *testing.T) {
+	w := serveFeed(t, "/atom.xml?tag=go&content=full", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/atom+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+
+	var feed testAtom
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+
+	// The content mode is not part of the feed's identity
+	if feed.ID != testSiteURL+"/atom.xml?tag=go" {
+		t.Errorf("id = %q", feed.ID)
+	}
+	if feed.Title != "Inkstack" {
+		t.Errorf("title = %q", feed.Title)
+	}
+	if updated, err := time.Parse(time.RFC3339, feed.Updated); err != nil || !updated.Equal(testUpdated) {
+		t.Errorf("updated = %q, want RFC 3339 date of %v", feed.Updated, testUpdated)
+	}
+	if feed.Author.Name == "" {
+		t.Error("feed has no author name")
+	}
+
+	links := map[string]testAtomLink{}
+	for _, link := range feed.Links {
+		links[link.Rel] = link
+	}
+	if links["self"].Href != testSiteURL+"/atom.xml?content=full&tag=go" || links["self"].Type != "application/atom+xml" {
+		t.Errorf("self link = %+v", links["self"])
+	}
+	if links["alternate"].Href != testSiteURL+"/" {
+		t.Errorf("alternate link = %+v", links["alternate"])
+	}
+
+	if len(feed.Entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(feed.Entries))
+	}
+	entry := feed.Entries[0]
+	if entry.ID != "tag:blog.example.com,2026-02-28:posts/7" || entry.Title != "Hello <world>" {
+		t.Errorf("entry id or title wrong: %q %q", entry.ID, entry.Title)
+	}
+	if entry.Link.Href != testSiteURL+"/posts/hello-world" || entry.Link.Rel != "alternate" {
+		t.Errorf("entry link = %+v", entry.Link)
+	}
+	if published, err := time.Parse(time.RFC3339, entry.Published); err != nil || !published.Equal(testPublished) {
+		t.Errorf("published = %q", entry.Published)
+	}
+	if updated, err := time.Parse(time.RFC3339, entry.Updated); err != nil || !updated.Equal(testUpdated) {
+		t.Errorf("entry updated = %q", entry.Updated)
+	}
+	if entry.Summary.Type != "text" || entry.Summary.Value != "Tom & Jerry" {
+		t.Errorf("summary = %+v", entry.Summary)
+	}
+	if entry.Content == nil || entry.Content.Type != "html" || entry.Content.Value != testContentHTML {
+		t.Errorf("content = %+v, want type html with %q", entry.Content, testContentHTML)
+	}
+	if len(entry.Categories) != 2 || entry.Categories[0].Term != "go" {
+		t.Errorf("categories = %+v", entry.Categories)
+	}
+}
+
+func TestJSONFeed(t *testing.T) {
+	w := serveFeed(t, "/feed.json", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/feed+json; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	// HTML is kept readable rather than escaped as \u003c
+	if strings.Contains(w.Body.String(), `\u003c`) {
+		t.Error("content_html is HTML-escaped")
+	}
+
+	var feed map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if feed["version"] != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("version = %v", feed["version"])
+	}
+	if feed["title"] != "Inkstack" || feed["home_page_url"] != testSiteURL+"/" || feed["feed_url"] != testSiteURL+"/feed.json" {
+		t.Errorf("title, home_page_url or feed_url wrong: %v", feed)
+	}
+	if authors, _ := feed["authors"].([]interface{}); len(authors) != 1 {
+		t.Errorf("authors = %v", feed["authors"])
+	}
+
+	items, _ := feed["items"].([]interface{})
+	if len(items) != 1 {
+		t.Fatalf("got %d items, want 1", len(items))
+	}
+	item := items[0].(map[string]interface{})
+	if item["id"] != "tag:blog.example.com,2026-02-28:posts/7" || item["url"] != testSiteURL+"/posts/hello-world" {
+		t.Errorf("item id or url wrong: %v", item)
+	}
+	if item["content_html"] != testContentHTML {
+		t.Errorf("content_html = %v, want %q", item["content_html"], testContentHTML)
+	}
+	if _, ok := item["content_text"]; ok {
+		t.Error("item has content_text alongside content_html")
+	}
+	for key, want := range map[string]time.Time{"date_published": testPublished, "date_modified": testUpdated} {
+		value, _ := item[key].(string)
+		if parsed, err := time.Parse(time.RFC3339, value); err != nil || !parsed.Equal(want) {
+			t.Errorf("%s = %q, want RFC 3339 date of %v", key, value, want)
+		}
+	}
+}
+
+func TestFeedExcerpts(t *testing.T) {
+	var rss testRSS
+	if err := xml.Unmarshal(serveFeed(t, "/feed.xml?content=excerpt", nil).Body.Bytes(), &rss); err != nil {
+		t.Fatalf("invalid RSS: %v", err)
+	}
+	if len(rss.Channel.Items) != 1 || rss.Channel.Items[0].Content != nil {
+		t.Error("RSS item has content:encoded with content=excerpt")
+	}
+
+	var atom testAtom
+	if err := xml.Unmarshal(serveFeed(t, "/atom.xml?content=excerpt", nil).Body.Bytes(), &atom); err != nil {
+		t.Fatalf("invalid Atom: %v", err)
+	}
+	if len(atom.Entries) != 1 || atom.Entries[0].Content != nil {
+		t.Error("Atom entry has content with content=excerpt")
+	}
+
+	var feed struct {
+		Items []map[string]interface{} `json:"items"`
+	}
+	if err := json.Unmarshal(serveFeed(t, "/feed.json?content=excerpt", nil).Body.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid JSON Feed: %v", err)
+	}
+	// Every JSON Feed item needs content_html or content_text
+	if len(feed.Items) != 1 || feed.Items[0]["content_text"] != "Tom & Jerry" || feed.Items[0]["content_html"] != nil {
+		t.Errorf("JSON Feed item = %v, want the summary as content_text only", feed.Items)
+	}
+}
+
+func TestFeedConditionalGet(t *testing.T) {
+	for _, path := range []string{"/feed.xml", "/atom.xml", "/feed.json"} {
+		first := serveFeed(t, path, nil)
+		etag := first.Header().Get("ETag")
+		lastModified := first.Header().Get("Last-Modified")
+		if etag == "" || lastModified == "" {
+			t.Fatalf("%s: ETag %q, Last-Modified %q", path, etag, lastModified)
+		}
+		if lastModified != testUpdated.Format(http.TimeFormat) {
+			t.Errorf("%s: Last-Modified = %q, want the newest update", path, lastModified)
+		}
+
+		for name, header := range map[string]http.Header{
+			"If-None-Match":     {"If-None-Match": {etag}},
+			"If-Modified-Since": {"If-Modified-Since": {lastModified}},
+		} {
+			w := serveFeed(t, path, header)
+			if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+				t.Errorf("%s with %s: status %d, %d bytes; want 304 and no body", path, name, w.Code, w.Body.Len())
+			}
+		}
+
+		stale := serveFeed(t, path, http.Header{"If-None-Match": {`"stale"`}})
+		if stale.Code != http.StatusOK {
+			t.Errorf("%s with a stale ETag: status %d, want 200", path, stale.Code)
+		}
+	}
+}
+
+func TestFeedErrors(t *testing.T) {
+	for path, want := range map[string]int{
+		"/feed.xml?content=all":           http.StatusBadRequest,
+		"/feed.xml?author=ann&tag=go":     http.StatusBadRequest,
+		"/atom.xml?tag=no-such-tag":       http.StatusNotFound,
+		"/feed.json?tag=go&content=full":  http.StatusOK,
+		"/feed.json?tag=go&content=other": http.StatusBadRequest,
+	} {
+		if w := serveFeed(t, path, nil); w.Code != want {
+			t.Errorf("%s: status %d, want %d", path, w.Code, want)
+		}
+	}
+}
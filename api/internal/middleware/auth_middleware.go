@@ -1,81 +1,118 @@
 package middleware
 
 import (
+	"inkstack/internal/database"
 	"inkstack/internal/service"
+	"log"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT tokens and extracts user information
-func AuthMiddleware(jwtService *service.JWTService) gin.HandlerFunc {
+// Personal access token scopes enforced per route
+const (
+	ScopePostsWrite         = "posts:write"
+	ScopeCommentsWrite      = "comments:write"
+	ScopeCommentsModerate   = "comments:moderate"
+	ScopeRelationshipsWrite = "relationships:write"
+	ScopeReactionsWrite     = "reactions:write"
+	ScopeBookmarksWrite     = "bookmarks:write"
+	ScopeFollowsWrite       = "follows:write"
+	ScopeNotificationsWrite = "notifications:write"
+)
+
+// Role permissions enforced per route. Unlike scopes, which limit what a delegated
+// token may do, permissions come from the user's role.
+const (
+	PermissionPostsWrite       = "posts:write"
+	PermissionPostsEditAny     = "posts:edit_any"
+	PermissionCommentsWrite    = "comments:write"
+	PermissionCommentsModerate = "comments:moderate"
+)
+
+// AuthMiddleware validates JWT tokens or personal access tokens and extracts user information.
+// Browser clients using cookie sessions send the access token in a cookie instead of the header.
+func AuthMiddleware(jwtService *service.JWTService, authClient *service.AuthClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Extract token from Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		token, fromCookie, errMessage := requestToken(c)
+		if errMessage != "" {
 			c.JSON(401, gin.H{
-				"error": "Authorization header required",
+				"error": errMessage,
+			})
+			c.Abort()
+			return
+		}
+
+		// Browsers send cookies on cross-site requests too, so cookie sessions need a CSRF token
+		if fromCookie && !validCSRF(c) {
+			c.JSON(403, gin.H{
+				"error": "Missing or invalid CSRF token",
 			})
 			c.Abort()
 			return
 		}
 
-		// Check Bearer prefix
-		if !strings.HasPrefix(authHeader, "Bearer ") {
+		// Personal access tokens are opaque and must be checked by the auth service
+		if service.IsPersonalAccessToken(token) {
+			result, err := authClient.ValidateToken(c.Request.Context(), token)
+			if err != nil {
+				c.JSON(401, gin.H{
+					"error": "Invalid, revoked or expired token",
+				})
+				c.Abort()
+				return
+			}
+
+			setPersonalAccessTokenContext(c, result)
+			c.Next()
+			return
+		}
+
+		// Validate token
+		claims, err := jwtService.ValidateAccessToken(token)
+		if err != nil {
 			c.JSON(401, gin.H{
-				"error": "Invalid authorization header format. Use: Bearer <token>",
+				"error": "Invalid or expired token",
 			})
 			c.Abort()
 			return
 		}
 
-		// Extract token
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == "" {
+		if isRevoked(c, authClient, token, claims) {
 			c.JSON(401, gin.H{
-				"error": "Token is required",
+				"error": "Token has been revoked",
 			})
 			c.Abort()
 			return
 		}
 
-		// Validate token
-		claims, err := jwtService.ValidateToken(token)
-		if err != nil {
+		// Client credentials tokens carry no user and cannot act on user resources
+		if claims.UserID == 0 {
 			c.JSON(401, gin.H{
-				"error": "Invalid or expired token",
+				"error": "Token does not identify a user",
 			})
 			c.Abort()
 			return
 		}
 
 		// Store user info in context for handlers
-		c.Set("user_id", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
+		setClaimsContext(c, claims)
 
 		c.Next()
 	}
 }
 
 // OptionalAuthMiddleware extracts user info if token exists, but doesn't require it
-func OptionalAuthMiddleware(jwtService *service.JWTService) gin.HandlerFunc {
+func OptionalAuthMiddleware(jwtService *service.JWTService, authClient *service.AuthClient) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.Next()
-			return
-		}
-
-		if strings.HasPrefix(authHeader, "Bearer ") {
-			token := strings.TrimPrefix(authHeader, "Bearer ")
-			claims, err := jwtService.ValidateToken(token)
-			if err == nil {
-				c.Set("user_id", claims.UserID)
-				c.Set("email", claims.Email)
-				c.Set("username", claims.Username)
-				c.Set("role", claims.Role)
+		token, fromCookie, errMessage := requestToken(c)
+		if errMessage == "" && (!fromCookie || validCSRF(c)) {
+			if service.IsPersonalAccessToken(token) {
+				if result, err := authClient.ValidateToken(c.Request.Context(), token); err == nil {
+					setPersonalAccessTokenContext(c, result)
+				}
+			} else if claims, err := jwtService.ValidateAccessToken(token); err == nil && claims.UserID != 0 && !isRevoked(c, authClient, token, claims) {
+				setClaimsContext(c, claims)
 			}
 		}
 
@@ -106,3 +143,121 @@ func RequireRole(role string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequirePermission checks that the authenticated user's role grants a permission
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasPermission(c, permission) {
+			c.JSON(403, gin.H{
+				"error":               "Insufficient permissions",
+				"required_permission": permission,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// HasPermission reports whether the authenticated user's role grants a permission
+func HasPermission(c *gin.Context, permission string) bool {
+	permissions, exists := c.Get("permissions")
+	if !exists {
+		return false
+	}
+
+	granted, _ := permissions.([]string)
+	for _, p := range granted {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireScope checks that a delegated token (personal access token or OAuth client token)
+// was granted the given scope. First-party sessions are not scope-restricted.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, restricted := c.Get("scopes")
+		if !restricted {
+			c.Next()
+			return
+		}
+
+		granted, _ := scopes.([]string)
+		for _, s := range granted {
+			if s == scope {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(403, gin.H{
+			"error":          "Token is missing required scope",
+			"required_scope": scope,
+		})
+		c.Abort()
+	}
+}
+
+// isRevoked reports whether the auth service has revoked an access token, such as by
+// sign-out or OAuth token revocation. The blacklist lives in the Redis both services
+// share. Without it, OAuth client tokens are checked with the auth service instead, so
+// a client's revocation always takes effect; first-party tokens are then trusted until
+// they expire.
+func isRevoked(c *gin.Context, authClient *service.AuthClient, token string, claims *service.JWTClaims) bool {
+	if database.GetRedis() != nil {
+		blacklisted, err := database.IsTokenBlacklisted(c.Request.Context(), token)
+		if err == nil {
+			return blacklisted
+		}
+		log.Printf("Failed to check token blacklist: %v", err)
+	}
+
+	if claims.ClientID == "" {
+		return false
+	}
+	_, err := authClient.ValidateToken(c.Request.Context(), token)
+	return err != nil
+}
+
+// setClaimsContext stores JWT claims in the request context
+func setClaimsContext(c *gin.Context, claims *service.JWTClaims) {
+	c.Set("user_id", claims.UserID)
+	c.Set("email", claims.Email)
+	c.Set("username", claims.Username)
+	c.Set("role", claims.Role)
+	c.Set("permissions", claims.Permissions)
+
+	// Impersonation tokens name the staff member acting as the user; log every
+	// request they make so actions can be attributed
+	if claims.Actor != nil {
+		c.Set("impersonator_id", claims.Actor.UserID)
+		c.Set("impersonator_username", claims.Actor.Username)
+		c.Set("impersonation_session", claims.ID)
+		log.Printf("Impersonation: %s (user %d) acting as %s (user %d), session %s: %s %s",
+			claims.Actor.Username, claims.Actor.UserID, claims.Username, claims.UserID, claims.ID, c.Request.Method, c.Request.URL.Path)
+	}
+
+	if claims.ClientID != "" {
+		c.Set("auth_type", "oauth_access_token")
+		c.Set("client_id", claims.ClientID)
+		c.Set("scopes", strings.Fields(claims.Scope))
+		return
+	}
+
+	c.Set("auth_type", "access_token")
+}
+
+// setPersonalAccessTokenContext stores a validated personal access token identity in the request context
+func setPersonalAccessTokenContext(c *gin.Context, result *service.TokenValidation) {
+	c.Set("user_id", result.UserID)
+	c.Set("email", result.Email)
+	c.Set("username", result.Username)
+	c.Set("role", result.Role)
+	c.Set("permissions", result.Permissions)
+	c.Set("auth_type", "personal_access_token")
+	c.Set("scopes", result.Scopes)
+}
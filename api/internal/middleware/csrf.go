@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cookie session names set by the auth service
+const (
+	// AccessTokenCookie holds the access token (httpOnly)
+	AccessTokenCookie = "inkstack_access"
+
+	// CSRFCookie holds the CSRF token, which the client echoes in CSRFHeader
+	CSRFCookie = "inkstack_csrf"
+
+	// CSRFHeader must match CSRFCookie on unsafe requests authenticated by cookie
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// requestToken finds the access token in the Authorization header or, failing that,
+// the access token cookie. Returns an error message if neither holds a usable token.
+func requestToken(c *gin.Context) (token string, fromCookie bool, errMessage string) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		if cookie, err := c.Cookie(AccessTokenCookie); err == nil && cookie != "" {
+			return cookie, true, ""
+		}
+		return "", false, "Authorization header required"
+	}
+
+	// Check Bearer prefix
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return "", false, "Invalid authorization header format. Use: Bearer <token>"
+	}
+
+	// Extract token
+	token = strings.TrimPrefix(authHeader, "Bearer ")
+	if token == "" {
+		return "", false, "Token is required"
+	}
+
+	return token, false, ""
+}
+
+// validCSRF checks the double-submit CSRF token. Safe methods never change state and are always allowed.
+func validCSRF(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	cookie, err := c.Cookie(CSRFCookie)
+	header := c.GetHeader(CSRFHeader)
+	if err != nil || cookie == "" || header == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"inkstack/internal/config"
+	"inkstack/internal/database"
+	"inkstack/internal/util"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKeyFunc derives the identity a rate limit is applied to
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// KeyByIP rate limits by client IP address
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByUser rate limits by authenticated user, falling back to IP address.
+// Must run after AuthMiddleware to see the user.
+func KeyByUser(c *gin.Context) string {
+	if userID, exists := c.Get("user_id"); exists {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return KeyByIP(c)
+}
+
+// KeyByToken rate limits by bearer token or session cookie, falling back to IP address
+func KeyByToken(c *gin.Context) string {
+	if token, _, errMessage := requestToken(c); errMessage == "" {
+		sum := sha256.Sum256([]byte(token))
+		return "token:" + hex.EncodeToString(sum[:])
+	}
+	return KeyByIP(c)
+}
+
+// RateLimit creates middleware enforcing a Redis-backed sliding window limit.
+// Requests are allowed through if Redis is unavailable.
+func RateLimit(name string, rule config.RateLimitRule, keyFunc RateLimitKeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if rule.Limit <= 0 {
+			c.Next()
+			return
+		}
+
+		result, err := database.AllowRequest(c.Request.Context(), name+":"+keyFunc(c), rule.Limit, rule.Window)
+		if err != nil {
+			log.Printf("Rate limiter unavailable, allowing request: %v", err)
+			c.Next()
+			return
+		}
+
+		resetSeconds := strconv.Itoa(int(math.Ceil(result.ResetAfter.Seconds())))
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", resetSeconds)
+
+		if !result.Allowed {
+			c.Header("Retry-After", resetSeconds)
+			util.RespondWithError(c, http.StatusTooManyRequests, "Rate limit exceeded", "Please retry after "+resetSeconds+" seconds")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"inkstack/internal/service"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Service token scopes for internal endpoints
+const (
+	ScopeUsersExport = "users:export"
+)
+
+// AuthServiceClientID identifies the auth service in the service tokens it signs
+const AuthServiceClientID = "inkstack-auth"
+
+// ServiceAuthMiddleware only admits service tokens signed by the auth service
+// that carry the given scope. These are never issued to OAuth clients.
+func ServiceAuthMiddleware(jwtService *service.JWTService, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			c.JSON(401, gin.H{
+				"error": "Service token required",
+			})
+			c.Abort()
+			return
+		}
+
+		claims, err := jwtService.ValidateToken(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			c.JSON(401, gin.H{
+				"error": "Invalid or expired token",
+			})
+			c.Abort()
+			return
+		}
+
+		if !slices.Contains(claims.Audience, service.ServiceAudience) ||
+			claims.ClientID != AuthServiceClientID || claims.UserID != 0 {
+			c.JSON(403, gin.H{
+				"error": "Only internal services may call this endpoint",
+			})
+			c.Abort()
+			return
+		}
+
+		if !slices.Contains(strings.Fields(claims.Scope), scope) {
+			c.JSON(403, gin.H{
+				"error": "Insufficient scope",
+				"scope": scope,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
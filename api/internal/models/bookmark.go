@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Bookmark records that a user saved a post to read later, optionally in one of their lists
+type Bookmark struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:uq_bookmarks" json:"user_id"`
+	PostID    uint      `gorm:"not null;uniqueIndex:uq_bookmarks;index" json:"post_id"`
+	ListID    *uint     `gorm:"index" json:"list_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Post      *Post     `gorm:"foreignKey:PostID" json:"post,omitempty"`
+}
+
+// TableName specifies the table name for the Bookmark model
+func (Bookmark) TableName() string {
+	return "bookmarks"
+}
+
+// BookmarkList is a user-named reading list of bookmarks
+type BookmarkList struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:uq_bookmark_lists_name" json:"user_id"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex:uq_bookmark_lists_name" json:"name" validate:"required,max=100"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for the BookmarkList model
+func (BookmarkList) TableName() string {
+	return "bookmark_lists"
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Follow target types
+const (
+	FollowTargetAuthor = "author" // TargetID is the author's user ID
+	FollowTargetTag    = "tag"    // TargetID is a tag ID
+)
+
+// Follow records that a user follows an author or tag. Published posts from
+// followed authors and tags make up the user's feed.
+type Follow struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	FollowerID uint      `gorm:"not null;uniqueIndex:uq_follows" json:"follower_id"`
+	TargetType string    `gorm:"type:varchar(10);not null;uniqueIndex:uq_follows;index:idx_follows_target" json:"target_type" validate:"oneof=author tag"`
+	TargetID   uint      `gorm:"not null;uniqueIndex:uq_follows;index:idx_follows_target" json:"target_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the Follow model
+func (Follow) TableName() string {
+	return "follows"
+}
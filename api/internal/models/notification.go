@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Notification types
+const (
+	NotificationCommentReply    = "comment_reply"    // Someone replied to the recipient's comment
+	NotificationPostComment     = "post_comment"     // Someone commented on the recipient's post
+	NotificationMention         = "mention"          // Someone @mentioned the recipient in a comment
+	NotificationCommentApproved = "comment_approved" // A moderator approved the recipient's comment
+	NotificationCommentRejected = "comment_rejected" // A moderator rejected the recipient's comment
+)
+
+// Notification tells a user about activity on their posts and comments
+type Notification struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index:idx_notifications_user_id" json:"user_id"`
+	Type      string     `gorm:"type:varchar(32);not null" json:"type" validate:"oneof=comment_reply post_comment mention comment_approved comment_rejected"`
+	ActorID   *uint      `gorm:"index" json:"actor_id"`
+	PostID    *uint      `json:"post_id"`
+	CommentID *uint      `json:"comment_id"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for the Notification model
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Reaction target types
+const (
+	ReactionTargetPost    = "post"
+	ReactionTargetComment = "comment"
+)
+
+// ReactionLike is the reaction type that is always available, alongside the configured emoji
+const ReactionLike = "like"
+
+// Reaction records that a user reacted to a post or comment. A user may leave
+// each reaction type once per target.
+type Reaction struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	TargetType string    `gorm:"type:varchar(10);not null;uniqueIndex:uq_reactions" json:"target_type" validate:"oneof=post comment"`
+	TargetID   uint      `gorm:"not null;uniqueIndex:uq_reactions" json:"target_id"`
+	UserID     uint      `gorm:"not null;uniqueIndex:uq_reactions;index" json:"user_id"`
+	Type       string    `gorm:"type:varchar(32);not null;uniqueIndex:uq_reactions" json:"type"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the Reaction model
+func (Reaction) TableName() string {
+	return "reactions"
+}
+
+// ReactionCount is the number of reactions of one type on a target
+type ReactionCount struct {
+	TargetID uint
+	Type     string
+	Count    int64
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Tag is a topic posts can be tagged with and users can follow
+type Tag struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"name" validate:"required,max=50"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the Tag model
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// PostTag links a post to one of its tags
+type PostTag struct {
+	PostID uint `gorm:"primaryKey" json:"post_id"`
+	TagID  uint `gorm:"primaryKey;index" json:"tag_id"`
+}
+
+// TableName specifies the table name for the PostTag model
+func (PostTag) TableName() string {
+	return "post_tags"
+}
+
+// PostTagName is the name of one tag on a post
+type PostTagName struct {
+	PostID uint
+	Name   string
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Relationship kinds
+const (
+	RelationshipBlock = "block" // The target cannot reply to the user's posts or comments
+	RelationshipMute  = "mute"  // The target's comments are hidden from the user
+)
+
+// UserRelationship records that a user has blocked or muted another user
+type UserRelationship struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:uq_user_relationships" json:"user_id"`
+	TargetID  uint      `gorm:"not null;uniqueIndex:uq_user_relationships;index" json:"target_id"`
+	Kind      string    `gorm:"type:varchar(10);not null;uniqueIndex:uq_user_relationships" json:"kind" validate:"oneof=block mute"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the UserRelationship model
+func (UserRelationship) TableName() string {
+	return "user_relationships"
+}
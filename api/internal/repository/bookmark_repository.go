@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"inkstack/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BookmarkRepository defines the interface for bookmark and reading list data operations
+type BookmarkRepository interface {
+	Save(bookmark *models.Bookmark) error
+	Delete(userID, postID uint) (bool, error)
+	FindByUserAndPost(userID, postID uint) (*models.Bookmark, error)
+	FindByUser(userID uint, listID *uint, beforeID uint, limit int) ([]models.Bookmark, error)
+	FindBookmarkedPostIDs(userID uint, postIDs []uint) ([]uint, error)
+	CreateList(list *models.BookmarkList) error
+	UpdateList(list *models.BookmarkList) error
+	DeleteList(userID, listID uint) (bool, error)
+	FindListByID(userID, listID uint) (*models.BookmarkList, error)
+	FindListByName(userID uint, name string) (*models.BookmarkList, error)
+	FindListsByUser(userID uint) ([]models.BookmarkList, error)
+	FindAllByUser(userID uint) ([]models.Bookmark, error)
+	DeleteAllByUser(userID uint) error
+}
+
+// bookmarkRepository implements BookmarkRepository
+type bookmarkRepository struct {
+	db *gorm.DB
+}
+
+// NewBookmarkRepository creates a new bookmark repository
+func NewBookmarkRepository(db *gorm.DB) BookmarkRepository {
+	return &bookmarkRepository{db: db}
+}
+
+// Save records a bookmark, or moves an existing bookmark of the same post to bookmark.ListID
+func (r *bookmarkRepository) Save(bookmark *models.Bookmark) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "post_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"list_id"}),
+	}).Omit("Post").Create(bookmark).Error
+}
+
+// Delete removes a bookmark and reports whether it existed
+func (r *bookmarkRepository) Delete(userID, postID uint) (bool, error) {
+	result := r.db.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&models.Bookmark{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindByUserAndPost finds a user's bookmark of a post
+func (r *bookmarkRepository) FindByUserAndPost(userID, postID uint) (*models.Bookmark, error) {
+	var bookmark models.Bookmark
+	err := r.db.Where("user_id = ? AND post_id = ?", userID, postID).First(&bookmark).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bookmark, nil
+}
+
+// FindByUser retrieves up to limit of a user's bookmarks with IDs below beforeID, newest
+// first, optionally only those in one list. A beforeID of 0 starts from the newest.
+// Bookmarked posts are loaded even if soft-deleted.
+func (r *bookmarkRepository) FindByUser(userID uint, listID *uint, beforeID uint, limit int) ([]models.Bookmark, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if listID != nil {
+		query = query.Where("list_id = ?", *listID)
+	}
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+
+	var bookmarks []models.Bookmark
+	err := query.Preload("Post", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).
+		Order("id DESC").
+		Limit(limit).
+		Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+// FindBookmarkedPostIDs returns which of the given posts a user has bookmarked
+func (r *bookmarkRepository) FindBookmarkedPostIDs(userID uint, postIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(postIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&models.Bookmark{}).
+		Where("user_id = ? AND post_id IN ?", userID, postIDs).
+		Pluck("post_id", &ids).Error
+	return ids, err
+}
+
+// CreateList creates a new reading list
+func (r *bookmarkRepository) CreateList(list *models.BookmarkList) error {
+	return r.db.Create(list).Error
+}
+
+// UpdateList updates an existing reading list
+func (r *bookmarkRepository) UpdateList(list *models.BookmarkList) error {
+	return r.db.Save(list).Error
+}
+
+// DeleteList removes one of a user's reading lists and reports whether it existed.
+// Bookmarks in the list become unlisted (ON DELETE SET NULL).
+func (r *bookmarkRepository) DeleteList(userID, listID uint) (bool, error) {
+	result := r.db.Where("user_id = ? AND id = ?", userID, listID).Delete(&models.BookmarkList{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindListByID finds one of a user's reading lists by ID
+func (r *bookmarkRepository) FindListByID(userID, listID uint) (*models.BookmarkList, error) {
+	var list models.BookmarkList
+	err := r.db.Where("user_id = ? AND id = ?", userID, listID).First(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// FindListByName finds one of a user's reading lists by name
+func (r *bookmarkRepository) FindListByName(userID uint, name string) (*models.BookmarkList, error) {
+	var list models.BookmarkList
+	err := r.db.Where("user_id = ? AND name = ?", userID, name).First(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// FindListsByUser retrieves every reading list a user has made, in name order
+func (r *bookmarkRepository) FindListsByUser(userID uint) ([]models.BookmarkList, error) {
+	var lists []models.BookmarkList
+	err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&lists).Error
+	return lists, err
+}
+
+// FindAllByUser retrieves every bookmark a user has made
+func (r *bookmarkRepository) FindAllByUser(userID uint) ([]models.Bookmark, error) {
+	var bookmarks []models.Bookmark
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+// DeleteAllByUser removes every bookmark and reading list a user has made
+func (r *bookmarkRepository) DeleteAllByUser(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.Bookmark{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.BookmarkList{}).Error
+	})
+}
@@ -6,6 +6,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// DeletedCommentContent replaces the text of a deleted user's comment that still has replies
+const DeletedCommentContent = "[deleted]"
+
 // CommentRepository defines the interface for comment data operations
 type CommentRepository interface {
 	Create(comment *models.Comment) error
@@ -17,6 +20,8 @@ type CommentRepository interface {
 	Delete(id uint) error
 	UpdateStatus(id uint, status string) error
 	CountByPost(postID uint) (int64, error)
+	FindAllByUser(userID uint) ([]models.Comment, error)
+	DeleteAllByUser(userID uint) error
 }
 
 // commentRepository implements CommentRepository
@@ -94,3 +99,24 @@ func (r *commentRepository) CountByPost(postID uint) (int64, error) {
 	err := r.db.Model(&models.Comment{}).Where("post_id = ?", postID).Count(&count).Error
 	return count, err
 }
+
+// FindAllByUser retrieves every comment by a user
+func (r *commentRepository) FindAllByUser(userID uint) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&comments).Error
+	return comments, err
+}
+
+// DeleteAllByUser permanently removes a user's comments. Comments that other users
+// replied to are anonymized instead, so deleting them doesn't cascade to the replies.
+func (r *commentRepository) DeleteAllByUser(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&models.Comment{}).
+			Where("user_id = ? AND EXISTS (SELECT 1 FROM comments replies WHERE replies.parent_id = comments.id)", userID).
+			Updates(map[string]interface{}{"user_id": 0, "content": DeletedCommentContent}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("user_id = ?", userID).Delete(&models.Comment{}).Error
+	})
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"inkstack/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FollowRepository defines the interface for follow data operations
+type FollowRepository interface {
+	Create(follow *models.Follow) error
+	Delete(followerID uint, targetType string, targetID uint) (bool, error)
+	Exists(followerID uint, targetType string, targetID uint) (bool, error)
+	FindByFollower(followerID uint, targetType string, limit, offset int) ([]models.Follow, error)
+	CountByFollower(followerID uint, targetType string) (int64, error)
+	CountFollowers(targetType string, targetID uint) (int64, error)
+	FindAllByUser(userID uint) ([]models.Follow, error)
+	DeleteAllByUser(userID uint) error
+}
+
+// followRepository implements FollowRepository
+type followRepository struct {
+	db *gorm.DB
+}
+
+// NewFollowRepository creates a new follow repository
+func NewFollowRepository(db *gorm.DB) FollowRepository {
+	return &followRepository{db: db}
+}
+
+// Create records a follow. Recording one that already exists is not an error.
+func (r *followRepository) Create(follow *models.Follow) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(follow).Error
+}
+
+// Delete removes a follow and reports whether it existed
+func (r *followRepository) Delete(followerID uint, targetType string, targetID uint) (bool, error) {
+	result := r.db.Where("follower_id = ? AND target_type = ? AND target_id = ?", followerID, targetType, targetID).
+		Delete(&models.Follow{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Exists checks whether followerID follows the given author or tag
+func (r *followRepository) Exists(followerID uint, targetType string, targetID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Follow{}).
+		Where("follower_id = ? AND target_type = ? AND target_id = ?", followerID, targetType, targetID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindByFollower retrieves the authors or tags a user follows with pagination, newest
+// first. An empty targetType includes both.
+func (r *followRepository) FindByFollower(followerID uint, targetType string, limit, offset int) ([]models.Follow, error) {
+	query := r.db.Where("follower_id = ?", followerID)
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+
+	var follows []models.Follow
+	err := query.Limit(limit).Offset(offset).
+		Order("created_at DESC").
+		Find(&follows).Error
+	return follows, err
+}
+
+// CountByFollower returns the number of authors or tags a user follows. An empty
+// targetType counts both.
+func (r *followRepository) CountByFollower(followerID uint, targetType string) (int64, error) {
+	query := r.db.Model(&models.Follow{}).Where("follower_id = ?", followerID)
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
+
+// CountFollowers returns the number of users following an author or tag
+func (r *followRepository) CountFollowers(targetType string, targetID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Follow{}).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Count(&count).Error
+	return count, err
+}
+
+// FindAllByUser retrieves every author and tag a user follows
+func (r *followRepository) FindAllByUser(userID uint) ([]models.Follow, error) {
+	var follows []models.Follow
+	err := r.db.Where("follower_id = ?", userID).Order("created_at ASC").Find(&follows).Error
+	return follows, err
+}
+
+// DeleteAllByUser removes every follow made by a user, and every follow of them as an author
+func (r *followRepository) DeleteAllByUser(userID uint) error {
+	return r.db.Where("follower_id = ? OR (target_type = ? AND target_id = ?)", userID, models.FollowTargetAuthor, userID).
+		Delete(&models.Follow{}).Error
+}
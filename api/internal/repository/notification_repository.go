@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"inkstack/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepository defines the interface for notification data operations
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	FindByUser(userID uint, unreadOnly bool, beforeID uint, limit int) ([]models.Notification, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(userID, id uint) (bool, error)
+	MarkAllRead(userID uint) (int64, error)
+	FindAllByUser(userID uint) ([]models.Notification, error)
+	DeleteAllByUser(userID uint) error
+}
+
+// notificationRepository implements NotificationRepository
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Create creates a new notification
+func (r *notificationRepository) Create(notification *models.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+// FindByUser retrieves up to limit of a user's notifications with IDs below beforeID,
+// newest first, optionally only unread ones. A beforeID of 0 starts from the newest.
+func (r *notificationRepository) FindByUser(userID uint, unreadOnly bool, beforeID uint, limit int) ([]models.Notification, error) {
+	query := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+
+	var notifications []models.Notification
+	err := query.Order("id DESC").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+// CountUnread returns the number of a user's unread notifications
+func (r *notificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks one of a user's notifications read and reports whether it exists.
+// Marking a notification that is already read keeps its original read time.
+func (r *notificationRepository) MarkRead(userID, id uint) (bool, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND id = ?", userID, id).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkAllRead marks all of a user's unread notifications read and returns how many there were
+func (r *notificationRepository) MarkAllRead(userID uint) (int64, error) {
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// FindAllByUser retrieves every notification a user has received
+func (r *notificationRepository) FindAllByUser(userID uint) ([]models.Notification, error) {
+	var notifications []models.Notification
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&notifications).Error
+	return notifications, err
+}
+
+// DeleteAllByUser removes every notification a user has received or caused
+func (r *notificationRepository) DeleteAllByUser(userID uint) error {
+	return r.db.Where("user_id = ? OR actor_id = ?", userID, userID).
+		Delete(&models.Notification{}).Error
+}
@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"fmt"
 	"inkstack/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,11 +16,23 @@ type PostRepository interface {
 	FindAll(limit, offset int) ([]models.Post, error)
 	FindByAuthor(authorID uint, limit, offset int) ([]models.Post, error)
 	FindByStatus(status string, limit, offset int) ([]models.Post, error)
+	FindPublishedByAuthor(authorID uint, limit, offset int) ([]models.Post, error)
 	Update(post *models.Post) error
 	Delete(id uint) error
 	IncrementViewCount(id uint) error
 	Count() (int64, error)
 	CountByAuthor(authorID uint) (int64, error)
+	CountPublishedByAuthor(authorID uint) (int64, error)
+	FindAllByAuthor(authorID uint) ([]models.Post, error)
+	DeleteAllByAuthor(authorID uint) error
+	FindFeed(followerID uint, before *FeedPosition, limit int) ([]models.Post, error)
+	FindLatestPublished(authorID, tagID uint, limit int) ([]models.Post, error)
+}
+
+// FeedPosition is a post's place in a feed, which is ordered by publish time and then ID
+type FeedPosition struct {
+	PublishedAt time.Time
+	ID          uint
 }
 
 // postRepository implements PostRepository
@@ -83,6 +97,16 @@ func (r *postRepository) FindByStatus(status string, limit, offset int) ([]model
 	return posts, err
 }
 
+// FindPublishedByAuthor retrieves an author's published posts, newest first, with pagination
+func (r *postRepository) FindPublishedByAuthor(authorID uint, limit, offset int) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.db.Where("author_id = ? AND status = ?", authorID, "published").
+		Limit(limit).Offset(offset).
+		Order("published_at DESC").
+		Find(&posts).Error
+	return posts, err
+}
+
 // Update updates a post
 func (r *postRepository) Update(post *models.Post) error {
 	return r.db.Save(post).Error
@@ -112,3 +136,92 @@ func (r *postRepository) CountByAuthor(authorID uint) (int64, error) {
 	err := r.db.Model(&models.Post{}).Where("author_id = ?", authorID).Count(&count).Error
 	return count, err
 }
+
+// CountPublishedByAuthor returns the number of published posts by an author
+func (r *postRepository) CountPublishedByAuthor(authorID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Post{}).Where("author_id = ? AND status = ?", authorID, "published").Count(&count).Error
+	return count, err
+}
+
+// FindAllByAuthor retrieves every post by an author, in any status
+func (r *postRepository) FindAllByAuthor(authorID uint) ([]models.Post, error) {
+	var posts []models.Post
+	err := r.db.Where("author_id = ?", authorID).Order("created_at ASC").Find(&posts).Error
+	return posts, err
+}
+
+// DeleteAllByAuthor permanently deletes every post by an author, including soft-deleted ones.
+// Comments on those posts are removed by ON DELETE CASCADE.
+func (r *postRepository) DeleteAllByAuthor(authorID uint) error {
+	return r.db.Unscoped().Where("author_id = ?", authorID).Delete(&models.Post{}).Error
+}
+
+// feedQuery selects the next page of a feed. For each followed author or tag, a
+// LATERAL subquery takes at most a page of their newest published posts (using
+// idx_posts_feed and idx_post_tags_tag_id), so the work is bounded by the number
+// of follows times the page size, however many posts they have. %[1]s is replaced
+// with the keyset condition when paging past the first page.
+const feedQuery = `
+SELECT * FROM posts WHERE id IN (
+	SELECT latest.id FROM follows f
+	CROSS JOIN LATERAL (
+		SELECT p.id FROM posts p
+		WHERE p.author_id = f.target_id AND p.status = 'published' AND p.deleted_at IS NULL
+			AND p.published_at IS NOT NULL %[1]s
+		ORDER BY p.published_at DESC, p.id DESC
+		LIMIT @limit
+	) latest
+	WHERE f.follower_id = @follower AND f.target_type = 'author'
+	UNION
+	SELECT latest.id FROM follows f
+	CROSS JOIN LATERAL (
+		SELECT p.id FROM post_tags pt
+		JOIN posts p ON p.id = pt.post_id
+		WHERE pt.tag_id = f.target_id AND p.status = 'published' AND p.deleted_at IS NULL
+			AND p.published_at IS NOT NULL %[1]s
+		ORDER BY p.published_at DESC, p.id DESC
+		LIMIT @limit
+	) latest
+	WHERE f.follower_id = @follower AND f.target_type = 'tag'
+)
+ORDER BY published_at DESC, id DESC
+LIMIT @limit`
+
+// feedKeyset restricts a feed query to posts after the previous page
+const feedKeyset = "AND (p.published_at, p.id) < (@before_at, @before_id)"
+
+// FindFeed retrieves up to limit published posts by the authors, or with the tags, a user
+// follows, newest first. A nil before starts from the newest post.
+func (r *postRepository) FindFeed(followerID uint, before *FeedPosition, limit int) ([]models.Post, error) {
+	args := map[string]interface{}{
+		"follower": followerID,
+		"limit":    limit,
+	}
+	keyset := ""
+	if before != nil {
+		keyset = feedKeyset
+		args["before_at"] = before.PublishedAt
+		args["before_id"] = before.ID
+	}
+
+	var posts []models.Post
+	err := r.db.Raw(fmt.Sprintf(feedQuery, keyset), args).Scan(&posts).Error
+	return posts, err
+}
+
+// FindLatestPublished retrieves up to limit of the most recently published posts, newest
+// first, optionally only those by one author and/or with one tag. A zero ID matches any.
+func (r *postRepository) FindLatestPublished(authorID, tagID uint, limit int) ([]models.Post, error) {
+	query := r.db.Where("status = ? AND published_at IS NOT NULL", "published")
+	if authorID != 0 {
+		query = query.Where("author_id = ?", authorID)
+	}
+	if tagID != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM post_tags WHERE post_tags.post_id = posts.id AND post_tags.tag_id = ?)", tagID)
+	}
+
+	var posts []models.Post
+	err := query.Order("published_at DESC, id DESC").Limit(limit).Find(&posts).Error
+	return posts, err
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"inkstack/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReactionRepository defines the interface for reaction data operations
+type ReactionRepository interface {
+	Create(reaction *models.Reaction) error
+	Delete(targetType string, targetID, userID uint, reactionType string) (bool, error)
+	CountByTargets(targetType string, targetIDs []uint) ([]models.ReactionCount, error)
+	FindByUserAndTargets(userID uint, targetType string, targetIDs []uint) ([]models.Reaction, error)
+	FindAllByUser(userID uint) ([]models.Reaction, error)
+	DeleteAllByUser(userID uint) error
+}
+
+// reactionRepository implements ReactionRepository
+type reactionRepository struct {
+	db *gorm.DB
+}
+
+// NewReactionRepository creates a new reaction repository
+func NewReactionRepository(db *gorm.DB) ReactionRepository {
+	return &reactionRepository{db: db}
+}
+
+// Create records a reaction. Recording one that already exists is not an error.
+func (r *reactionRepository) Create(reaction *models.Reaction) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction).Error
+}
+
+// Delete removes a reaction and reports whether it existed
+func (r *reactionRepository) Delete(targetType string, targetID, userID uint, reactionType string) (bool, error) {
+	result := r.db.Where("target_type = ? AND target_id = ? AND user_id = ? AND type = ?", targetType, targetID, userID, reactionType).
+		Delete(&models.Reaction{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CountByTargets returns the number of reactions of each type on each of the given targets
+func (r *reactionRepository) CountByTargets(targetType string, targetIDs []uint) ([]models.ReactionCount, error) {
+	var counts []models.ReactionCount
+	if len(targetIDs) == 0 {
+		return counts, nil
+	}
+	err := r.db.Model(&models.Reaction{}).
+		Select("target_id, type, COUNT(*) AS count").
+		Where("target_type = ? AND target_id IN ?", targetType, targetIDs).
+		Group("target_id, type").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// FindByUserAndTargets retrieves the reactions a user has left on the given targets
+func (r *reactionRepository) FindByUserAndTargets(userID uint, targetType string, targetIDs []uint) ([]models.Reaction, error) {
+	var reactions []models.Reaction
+	if len(targetIDs) == 0 {
+		return reactions, nil
+	}
+	err := r.db.Where("user_id = ? AND target_type = ? AND target_id IN ?", userID, targetType, targetIDs).
+		Find(&reactions).Error
+	return reactions, err
+}
+
+// FindAllByUser retrieves every reaction a user has left
+func (r *reactionRepository) FindAllByUser(userID uint) ([]models.Reaction, error) {
+	var reactions []models.Reaction
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&reactions).Error
+	return reactions, err
+}
+
+// DeleteAllByUser removes every reaction a user has left
+func (r *reactionRepository) DeleteAllByUser(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.Reaction{}).Error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"inkstack/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RelationshipRepository defines the interface for block and mute data operations
+type RelationshipRepository interface {
+	Create(relationship *models.UserRelationship) error
+	Delete(userID, targetID uint, kind string) (bool, error)
+	FindByUser(userID uint, kind string, limit, offset int) ([]models.UserRelationship, error)
+	CountByUser(userID uint, kind string) (int64, error)
+	Exists(userID, targetID uint, kind string) (bool, error)
+	FindTargetIDs(userID uint, kind string) ([]uint, error)
+	FindAllByUser(userID uint) ([]models.UserRelationship, error)
+	DeleteAllByUser(userID uint) error
+}
+
+// relationshipRepository implements RelationshipRepository
+type relationshipRepository struct {
+	db *gorm.DB
+}
+
+// NewRelationshipRepository creates a new relationship repository
+func NewRelationshipRepository(db *gorm.DB) RelationshipRepository {
+	return &relationshipRepository{db: db}
+}
+
+// Create records a relationship. Recording one that already exists is not an error.
+func (r *relationshipRepository) Create(relationship *models.UserRelationship) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(relationship).Error
+}
+
+// Delete removes a relationship and reports whether it existed
+func (r *relationshipRepository) Delete(userID, targetID uint, kind string) (bool, error) {
+	result := r.db.Where("user_id = ? AND target_id = ? AND kind = ?", userID, targetID, kind).
+		Delete(&models.UserRelationship{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindByUser retrieves a user's relationships of one kind with pagination, newest first
+func (r *relationshipRepository) FindByUser(userID uint, kind string, limit, offset int) ([]models.UserRelationship, error) {
+	var relationships []models.UserRelationship
+	err := r.db.Where("user_id = ? AND kind = ?", userID, kind).
+		Limit(limit).Offset(offset).
+		Order("created_at DESC").
+		Find(&relationships).Error
+	return relationships, err
+}
+
+// CountByUser returns the number of a user's relationships of one kind
+func (r *relationshipRepository) CountByUser(userID uint, kind string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserRelationship{}).
+		Where("user_id = ? AND kind = ?", userID, kind).
+		Count(&count).Error
+	return count, err
+}
+
+// Exists checks whether userID has a relationship of the given kind with targetID
+func (r *relationshipRepository) Exists(userID, targetID uint, kind string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.UserRelationship{}).
+		Where("user_id = ? AND target_id = ? AND kind = ?", userID, targetID, kind).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindTargetIDs retrieves the IDs of every user userID has a relationship of the given kind with
+func (r *relationshipRepository) FindTargetIDs(userID uint, kind string) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&models.UserRelationship{}).
+		Where("user_id = ? AND kind = ?", userID, kind).
+		Pluck("target_id", &ids).Error
+	return ids, err
+}
+
+// FindAllByUser retrieves every block and mute a user has made
+func (r *relationshipRepository) FindAllByUser(userID uint) ([]models.UserRelationship, error) {
+	var relationships []models.UserRelationship
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&relationships).Error
+	return relationships, err
+}
+
+// DeleteAllByUser removes every relationship made by or targeting a user
+func (r *relationshipRepository) DeleteAllByUser(userID uint) error {
+	return r.db.Where("user_id = ? OR target_id = ?", userID, userID).
+		Delete(&models.UserRelationship{}).Error
+}
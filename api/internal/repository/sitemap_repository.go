@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sitemap sections
+const (
+	SitemapPosts   = "posts"   // Loc is the post's slug
+	SitemapAuthors = "authors" // Loc is the author's user ID
+	SitemapTags    = "tags"    // Loc is the tag name
+)
+
+// sitemapSources select the pages of each sitemap section as (sort_key, loc, last_modified),
+// counting only published posts
+var sitemapSources = map[string]string{
+	SitemapPosts: `
+		SELECT id AS sort_key, slug AS loc, updated_at AS last_modified FROM posts
+		WHERE status = 'published' AND deleted_at IS NULL AND published_at IS NOT NULL`,
+	SitemapAuthors: `
+		SELECT author_id AS sort_key, CAST(author_id AS TEXT) AS loc, MAX(updated_at) AS last_modified FROM posts
+		WHERE status = 'published' AND deleted_at IS NULL AND published_at IS NOT NULL
+		GROUP BY author_id`,
+	SitemapTags: `
+		SELECT t.id AS sort_key, t.name AS loc, MAX(p.updated_at) AS last_modified FROM tags t
+		JOIN post_tags pt ON pt.tag_id = t.id
+		JOIN posts p ON p.id = pt.post_id
+		WHERE p.status = 'published' AND p.deleted_at IS NULL AND p.published_at IS NOT NULL
+		GROUP BY t.id, t.name`,
+}
+
+// SitemapPage is one page of a sitemap section, numbered from 1
+type SitemapPage struct {
+	Page         int
+	LastModified time.Time
+}
+
+// SitemapEntry is one URL in a sitemap, identified by its section's Loc
+type SitemapEntry struct {
+	Loc          string
+	LastModified time.Time
+}
+
+// SitemapRepository defines the interface for sitemap data operations
+type SitemapRepository interface {
+	FindPages(section string, pageSize int) ([]SitemapPage, error)
+	FindEntries(section string, page, pageSize int) ([]SitemapEntry, error)
+}
+
+// sitemapRepository implements SitemapRepository
+type sitemapRepository struct {
+	db *gorm.DB
+}
+
+// NewSitemapRepository creates a new sitemap repository
+func NewSitemapRepository(db *gorm.DB) SitemapRepository {
+	return &sitemapRepository{db: db}
+}
+
+// FindPages splits a section into pages of pageSize entries and returns when each page
+// last changed. A section with no entries has no pages.
+func (r *sitemapRepository) FindPages(section string, pageSize int) ([]SitemapPage, error) {
+	source, ok := sitemapSources[section]
+	if !ok {
+		return nil, fmt.Errorf("unknown sitemap section %q", section)
+	}
+
+	var pages []SitemapPage
+	err := r.db.Raw(`
+		SELECT page, MAX(last_modified) AS last_modified FROM (
+			SELECT (ROW_NUMBER() OVER (ORDER BY sort_key) - 1) / @size + 1 AS page, last_modified
+			FROM (`+source+`) entries
+		) numbered
+		GROUP BY page
+		ORDER BY page`, map[string]interface{}{"size": pageSize}).
+		Scan(&pages).Error
+	return pages, err
+}
+
+// FindEntries retrieves one page of a section's entries
+func (r *sitemapRepository) FindEntries(section string, page, pageSize int) ([]SitemapEntry, error) {
+	source, ok := sitemapSources[section]
+	if !ok {
+		return nil, fmt.Errorf("unknown sitemap section %q", section)
+	}
+
+	var entries []SitemapEntry
+	err := r.db.Raw(`
+		SELECT loc, last_modified FROM (`+source+`) entries
+		ORDER BY sort_key
+		LIMIT @limit OFFSET @offset`, map[string]interface{}{
+		"limit":  pageSize,
+		"offset": (page - 1) * pageSize,
+	}).Scan(&entries).Error
+	return entries, err
+}
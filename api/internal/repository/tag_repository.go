@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"inkstack/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TagRepository defines the interface for tag data operations
+type TagRepository interface {
+	FindOrCreate(names []string) ([]models.Tag, error)
+	FindByName(name string) (*models.Tag, error)
+	FindByIDs(ids []uint) ([]models.Tag, error)
+	SetPostTags(postID uint, tagIDs []uint) error
+	FindNamesByPosts(postIDs []uint) ([]models.PostTagName, error)
+}
+
+// tagRepository implements TagRepository
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+// FindOrCreate returns the tags with the given names, creating any that don't exist yet
+func (r *tagRepository) FindOrCreate(names []string) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(names) == 0 {
+		return tags, nil
+	}
+
+	newTags := make([]models.Tag, len(names))
+	for i, name := range names {
+		newTags[i] = models.Tag{Name: name}
+	}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags).Error; err != nil {
+		return nil, err
+	}
+
+	err := r.db.Where("name IN ?", names).Find(&tags).Error
+	return tags, err
+}
+
+// FindByName finds a tag by name
+func (r *tagRepository) FindByName(name string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Where("name = ?", name).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindByIDs retrieves the tags with the given IDs
+func (r *tagRepository) FindByIDs(ids []uint) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&tags).Error
+	return tags, err
+}
+
+// SetPostTags replaces a post's tags
+func (r *tagRepository) SetPostTags(postID uint, tagIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ?", postID).Delete(&models.PostTag{}).Error; err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+
+		postTags := make([]models.PostTag, len(tagIDs))
+		for i, tagID := range tagIDs {
+			postTags[i] = models.PostTag{PostID: postID, TagID: tagID}
+		}
+		return tx.Create(&postTags).Error
+	})
+}
+
+// FindNamesByPosts returns the tag names on each of the given posts, in name order
+func (r *tagRepository) FindNamesByPosts(postIDs []uint) ([]models.PostTagName, error) {
+	var names []models.PostTagName
+	if len(postIDs) == 0 {
+		return names, nil
+	}
+	err := r.db.Table("post_tags").
+		Select("post_tags.post_id, tags.name").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Where("post_tags.post_id IN ?", postIDs).
+		Order("tags.name ASC").
+		Scan(&names).Error
+	return names, err
+}
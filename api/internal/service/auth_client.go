@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"inkstack/internal/config"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// PersonalAccessTokenPrefix marks a bearer token as a personal access token issued by the auth service
+	PersonalAccessTokenPrefix = "inkpat_"
+
+	// ScopeTokensValidate allows validating access tokens and personal access tokens with the auth service
+	ScopeTokensValidate = "tokens:validate"
+
+	// ScopeUsersRead allows looking up users' public profiles by ID with the auth service
+	ScopeUsersRead = "users:read"
+
+	// maxProfileLookup is the most users the auth service looks up in one request
+	maxProfileLookup = 500
+)
+
+// TokenValidation is the auth service's answer to a token validation request
+type TokenValidation struct {
+	Valid       bool     `json:"valid"`
+	Error       string   `json:"error"`
+	TokenType   string   `json:"token_type"`
+	UserID      uint     `json:"user_id"`
+	Email       string   `json:"email"`
+	Username    string   `json:"username"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+	Scopes      []string `json:"scopes"`
+}
+
+// UserProfile is a user's public profile as published by the auth service
+type UserProfile struct {
+	ID          uint      `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	AvatarURL   string    `json:"avatar_url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ErrUserNotFound is returned when the auth service has no public profile for a username
+var ErrUserNotFound = errors.New("user not found")
+
+// AuthClient calls the auth service over HTTP, identifying itself with a service token
+type AuthClient struct {
+	baseURL    string
+	httpClient *ServiceClient
+}
+
+// NewAuthClient creates a new auth service client
+func NewAuthClient(cfg *config.Config, jwtService *JWTService) *AuthClient {
+	return &AuthClient{
+		baseURL:    strings.TrimRight(cfg.Auth.ServiceURL, "/"),
+		httpClient: NewServiceClient(jwtService, cfg.Auth.RequestTimeout, ScopeTokensValidate, ScopeUsersRead),
+	}
+}
+
+// IsPersonalAccessToken reports whether a bearer token looks like a personal access token
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// ValidateToken asks the auth service to validate a token
+func (c *AuthClient) ValidateToken(ctx context.Context, token string) (*TokenValidation, error) {
+	body, err := json.Marshal(map[string]string{"token": token})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/auth/validate", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("auth service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("auth service returned status %d", resp.StatusCode)
+	}
+
+	var result TokenValidation
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode auth service response: %w", err)
+	}
+
+	if !result.Valid {
+		return nil, fmt.Errorf("invalid token: %s", result.Error)
+	}
+
+	return &result, nil
+}
+
+// GetUserProfile fetches a user's public profile from the auth service. It goes through
+// the service-authenticated lookup endpoint, so it doesn't share the per-IP limit of the
+// public profile route with every other request from this host.
+func (c *AuthClient) GetUserProfile(ctx context.Context, username string) (*UserProfile, error) {
+	profiles, err := c.lookupProfiles(ctx, map[string][]string{"usernames": {username}})
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	return &profiles[0], nil
+}
+
+// GetUserProfiles fetches the public profiles of the given users from the auth service,
+// keyed by user ID. Users without a public profile are left out.
+func (c *AuthClient) GetUserProfiles(ctx context.Context, ids []uint) (map[uint]UserProfile, error) {
+	profiles := make(map[uint]UserProfile, len(ids))
+	for start := 0; start < len(ids); start += maxProfileLookup {
+		end := min(start+maxProfileLookup, len(ids))
+		batch, err := c.lookupProfiles(ctx, map[string][]uint{"ids": ids[start:end]})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, profile := range batch {
+			profiles[profile.ID] = profile
+		}
+	}
+
+	return profiles, nil
+}
+
+// lookupProfiles calls the auth service's batch profile lookup with the given request body
+func (c *AuthClient) lookupProfiles(ctx context.Context, lookup any) ([]UserProfile, error) {
+	body, err := json.Marshal(lookup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/api/users/lookup", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("auth service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("auth service returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Users []UserProfile `json:"users"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode auth service response: %w", err)
+	}
+
+	return result.Users, nil
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"inkstack/internal/models"
+	"inkstack/internal/repository"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPostNotFound is returned when bookmarking a post that does not exist or is not published
+	ErrPostNotFound = errors.New("post not found")
+
+	// ErrBookmarkNotFound is returned when removing a bookmark that does not exist
+	ErrBookmarkNotFound = errors.New("bookmark not found")
+
+	// ErrBookmarkListNotFound is returned when a reading list does not exist or belongs to another user
+	ErrBookmarkListNotFound = errors.New("bookmark list not found")
+)
+
+// maxBookmarkListName matches the bookmark_lists.name column
+const maxBookmarkListName = 100
+
+// BookmarkService defines the interface for bookmarks and reading lists
+type BookmarkService interface {
+	AddBookmark(userID, postID uint, listID *uint) (*models.Bookmark, error)
+	RemoveBookmark(userID, postID uint) error
+	ListBookmarks(userID uint, listID *uint, cursor uint, limit int) ([]models.Bookmark, uint, error)
+	BookmarkedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error)
+	CreateList(userID uint, name string) (*models.BookmarkList, error)
+	RenameList(userID, listID uint, name string) (*models.BookmarkList, error)
+	DeleteList(userID, listID uint) error
+	ListLists(userID uint) ([]models.BookmarkList, error)
+}
+
+// bookmarkService implements BookmarkService
+type bookmarkService struct {
+	bookmarkRepo repository.BookmarkRepository
+	postRepo     repository.PostRepository
+}
+
+// NewBookmarkService creates a new bookmark service
+func NewBookmarkService(bookmarkRepo repository.BookmarkRepository, postRepo repository.PostRepository) BookmarkService {
+	return &bookmarkService{
+		bookmarkRepo: bookmarkRepo,
+		postRepo:     postRepo,
+	}
+}
+
+// AddBookmark saves a post for later, in one of the user's lists or unlisted if
+// listID is nil. Bookmarking a post again moves it to the given list. The returned
+// bookmark includes the post.
+func (s *bookmarkService) AddBookmark(userID, postID uint, listID *uint) (*models.Bookmark, error) {
+	if userID == 0 {
+		return nil, errors.New("user_id is required")
+	}
+
+	post, err := s.postRepo.FindByID(postID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPostNotFound
+		}
+		return nil, err
+	}
+	if !postReadable(post, userID) {
+		return nil, ErrPostNotFound
+	}
+
+	if listID != nil {
+		if _, err := s.findList(userID, *listID); err != nil {
+			return nil, err
+		}
+	}
+
+	bookmark := &models.Bookmark{
+		UserID: userID,
+		PostID: postID,
+		ListID: listID,
+	}
+	if err := s.bookmarkRepo.Save(bookmark); err != nil {
+		return nil, fmt.Errorf("failed to save bookmark: %w", err)
+	}
+
+	// Reload so a moved bookmark keeps its original ID and creation time
+	saved, err := s.bookmarkRepo.FindByUserAndPost(userID, postID)
+	if err != nil {
+		return nil, err
+	}
+	saved.Post = post
+	return saved, nil
+}
+
+// RemoveBookmark removes a user's bookmark of a post
+func (s *bookmarkService) RemoveBookmark(userID, postID uint) error {
+	removed, err := s.bookmarkRepo.Delete(userID, postID)
+	if err != nil {
+		return fmt.Errorf("failed to remove bookmark: %w", err)
+	}
+	if !removed {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// ListBookmarks retrieves a page of a user's bookmarks, newest first, optionally
+// only those in one list. cursor is 0 for the first page; the returned cursor is
+// 0 on the last page.
+//
+// Bookmarks of posts that have since been unpublished or deleted are kept, so
+// they come back if the post is republished, but their Post is nil. Authors can
+// still see their own unpublished posts.
+func (s *bookmarkService) ListBookmarks(userID uint, listID *uint, cursor uint, limit int) ([]models.Bookmark, uint, error) {
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	if listID != nil {
+		if _, err := s.findList(userID, *listID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// Fetch one extra row to learn whether there is another page
+	bookmarks, err := s.bookmarkRepo.FindByUser(userID, listID, cursor, limit+1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var next uint
+	if len(bookmarks) > limit {
+		bookmarks = bookmarks[:limit]
+		next = bookmarks[limit-1].ID
+	}
+
+	for i := range bookmarks {
+		if post := bookmarks[i].Post; post != nil && (post.DeletedAt.Valid || !postReadable(post, userID)) {
+			bookmarks[i].Post = nil
+		}
+	}
+
+	return bookmarks, next, nil
+}
+
+// BookmarkedPostIDs reports which of the given posts a user has bookmarked
+func (s *bookmarkService) BookmarkedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error) {
+	bookmarked := make(map[uint]bool)
+	if userID == 0 {
+		return bookmarked, nil
+	}
+
+	ids, err := s.bookmarkRepo.FindBookmarkedPostIDs(userID, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load bookmarks: %w", err)
+	}
+	for _, id := range ids {
+		bookmarked[id] = true
+	}
+	return bookmarked, nil
+}
+
+// CreateList creates a named reading list
+func (s *bookmarkService) CreateList(userID uint, name string) (*models.BookmarkList, error) {
+	name, err := s.validateListName(userID, name, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.BookmarkList{
+		UserID: userID,
+		Name:   name,
+	}
+	if err := s.bookmarkRepo.CreateList(list); err != nil {
+		return nil, fmt.Errorf("failed to create list: %w", err)
+	}
+	return list, nil
+}
+
+// RenameList renames one of a user's reading lists
+func (s *bookmarkService) RenameList(userID, listID uint, name string) (*models.BookmarkList, error) {
+	list, err := s.findList(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err = s.validateListName(userID, name, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Name = name
+	if err := s.bookmarkRepo.UpdateList(list); err != nil {
+		return nil, fmt.Errorf("failed to rename list: %w", err)
+	}
+	return list, nil
+}
+
+// DeleteList deletes one of a user's reading lists. Its bookmarks are kept, unlisted.
+func (s *bookmarkService) DeleteList(userID, listID uint) error {
+	removed, err := s.bookmarkRepo.DeleteList(userID, listID)
+	if err != nil {
+		return fmt.Errorf("failed to delete list: %w", err)
+	}
+	if !removed {
+		return ErrBookmarkListNotFound
+	}
+	return nil
+}
+
+// ListLists retrieves every reading list a user has made
+func (s *bookmarkService) ListLists(userID uint) ([]models.BookmarkList, error) {
+	return s.bookmarkRepo.FindListsByUser(userID)
+}
+
+// findList finds one of a user's reading lists, returning ErrBookmarkListNotFound
+// for lists that belong to someone else
+func (s *bookmarkService) findList(userID, listID uint) (*models.BookmarkList, error) {
+	list, err := s.bookmarkRepo.FindListByID(userID, listID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBookmarkListNotFound
+		}
+		return nil, err
+	}
+	return list, nil
+}
+
+// validateListName trims a list name and checks it is not empty, too long, or
+// used by another of the user's lists than listID
+func (s *bookmarkService) validateListName(userID uint, name string, listID uint) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+	if utf8.RuneCountInString(name) > maxBookmarkListName {
+		return "", fmt.Errorf("name exceeds maximum length of %d characters", maxBookmarkListName)
+	}
+
+	existing, err := s.bookmarkRepo.FindListByName(userID, name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", err
+	}
+	if existing != nil && existing.ID != listID {
+		return "", errors.New("a list with this name already exists")
+	}
+	return name, nil
+}
+
+// postReadable reports whether a user may read a post: published posts are
+// readable by everyone, others only by their author
+func postReadable(post *models.Post, userID uint) bool {
+	return post.Status == "published" || post.AuthorID == userID
+}
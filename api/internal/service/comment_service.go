@@ -13,7 +13,7 @@ import (
 type CommentService interface {
 	CreateComment(postID, userID uint, content string, parentID *uint) (*models.Comment, error)
 	GetComment(id uint) (*models.Comment, error)
-	ListCommentsByPost(postID uint) ([]models.Comment, error)
+	ListCommentsByPost(postID, viewerID uint) ([]models.Comment, error)
 	ListCommentsByUser(userID uint, page, pageSize int) ([]models.Comment, int64, error)
 	UpdateComment(id uint, content string) (*models.Comment, error)
 	DeleteComment(id uint) error
@@ -22,17 +22,24 @@ type CommentService interface {
 	MarkAsSpam(id uint) (*models.Comment, error)
 }
 
+// ErrBlocked is returned when a user tries to reply to someone who has blocked them
+var ErrBlocked = errors.New("you cannot reply to this user")
+
 // commentService implements CommentService
 type commentService struct {
-	commentRepo repository.CommentRepository
-	postRepo    repository.PostRepository
+	commentRepo      repository.CommentRepository
+	postRepo         repository.PostRepository
+	relationshipRepo repository.RelationshipRepository
+	notifications    NotificationService
 }
 
 // NewCommentService creates a new comment service
-func NewCommentService(commentRepo repository.CommentRepository, postRepo repository.PostRepository) CommentService {
+func NewCommentService(commentRepo repository.CommentRepository, postRepo repository.PostRepository, relationshipRepo repository.RelationshipRepository, notifications NotificationService) CommentService {
 	return &commentService{
-		commentRepo: commentRepo,
-		postRepo:    postRepo,
+		commentRepo:      commentRepo,
+		postRepo:         postRepo,
+		relationshipRepo: relationshipRepo,
+		notifications:    notifications,
 	}
 }
 
@@ -53,7 +60,7 @@ func (s *commentService) CreateComment(postID, userID uint, content string, pare
 	}
 
 	// Verify post exists
-	_, err := s.postRepo.FindByID(postID)
+	post, err := s.postRepo.FindByID(postID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("post not found")
@@ -61,9 +68,15 @@ func (s *commentService) CreateComment(postID, userID uint, content string, pare
 		return nil, err
 	}
 
+	// The post's author may have blocked the commenter
+	if err := s.checkNotBlocked(post.AuthorID, userID); err != nil {
+		return nil, err
+	}
+
 	// If replying to a comment, verify parent comment exists
+	var parentComment *models.Comment
 	if parentID != nil && *parentID > 0 {
-		parentComment, err := s.commentRepo.FindByID(*parentID)
+		parentComment, err = s.commentRepo.FindByID(*parentID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, errors.New("parent comment not found")
@@ -74,6 +87,10 @@ func (s *commentService) CreateComment(postID, userID uint, content string, pare
 		if parentComment.PostID != postID {
 			return nil, errors.New("parent comment does not belong to this post")
 		}
+		// So may the author of the comment being replied to
+		if err := s.checkNotBlocked(parentComment.UserID, userID); err != nil {
+			return nil, err
+		}
 	}
 
 	comment := &models.Comment{
@@ -88,9 +105,26 @@ func (s *commentService) CreateComment(postID, userID uint, content string, pare
 		return nil, fmt.Errorf("failed to create comment: %w", err)
 	}
 
+	s.notifications.NotifyCommentCreated(comment, post)
+
 	return comment, nil
 }
 
+// checkNotBlocked returns ErrBlocked if ownerID has blocked userID
+func (s *commentService) checkNotBlocked(ownerID, userID uint) error {
+	if ownerID == userID {
+		return nil
+	}
+	blocked, err := s.relationshipRepo.Exists(ownerID, userID, models.RelationshipBlock)
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	return nil
+}
+
 // GetComment retrieves a comment by ID
 func (s *commentService) GetComment(id uint) (*models.Comment, error) {
 	comment, err := s.commentRepo.FindByID(id)
@@ -103,8 +137,9 @@ func (s *commentService) GetComment(id uint) (*models.Comment, error) {
 	return comment, nil
 }
 
-// ListCommentsByPost retrieves all comments for a post
-func (s *commentService) ListCommentsByPost(postID uint) ([]models.Comment, error) {
+// ListCommentsByPost retrieves all comments for a post, leaving out comments by
+// users the viewer has muted. viewerID is 0 for anonymous viewers.
+func (s *commentService) ListCommentsByPost(postID, viewerID uint) ([]models.Comment, error) {
 	// Verify post exists
 	_, err := s.postRepo.FindByID(postID)
 	if err != nil {
@@ -119,7 +154,30 @@ func (s *commentService) ListCommentsByPost(postID uint) ([]models.Comment, erro
 		return nil, err
 	}
 
-	return comments, nil
+	if viewerID == 0 {
+		return comments, nil
+	}
+
+	mutedIDs, err := s.relationshipRepo.FindTargetIDs(viewerID, models.RelationshipMute)
+	if err != nil {
+		return nil, err
+	}
+	if len(mutedIDs) == 0 {
+		return comments, nil
+	}
+
+	muted := make(map[uint]bool, len(mutedIDs))
+	for _, id := range mutedIDs {
+		muted[id] = true
+	}
+	visible := comments[:0]
+	for _, comment := range comments {
+		if !muted[comment.UserID] {
+			visible = append(visible, comment)
+		}
+	}
+
+	return visible, nil
 }
 
 // ListCommentsByUser retrieves comments by user with pagination
@@ -193,11 +251,21 @@ func (s *commentService) ApproveComment(id uint) (*models.Comment, error) {
 		return nil, err
 	}
 
+	post, parent, err := s.findThread(comment)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := s.commentRepo.UpdateStatus(id, "approved"); err != nil {
 		return nil, fmt.Errorf("failed to approve comment: %w", err)
 	}
 
-	comment.Status = "approved"
+	// Moderating a comment again doesn't announce it again
+	if comment.Status != "approved" {
+		comment.Status = "approved"
+		s.notifications.NotifyCommentModerated(comment, post, parent)
+	}
+
 	return comment, nil
 }
 
@@ -211,14 +279,49 @@ func (s *commentService) RejectComment(id uint) (*models.Comment, error) {
 		return nil, err
 	}
 
+	post, parent, err := s.findThread(comment)
+	if err != nil {
+		return nil, err
+	}
+
 	if err := s.commentRepo.UpdateStatus(id, "rejected"); err != nil {
 		return nil, fmt.Errorf("failed to reject comment: %w", err)
 	}
 
-	comment.Status = "rejected"
+	// Moderating a comment again doesn't announce it again
+	if comment.Status != "rejected" {
+		comment.Status = "rejected"
+		s.notifications.NotifyCommentModerated(comment, post, parent)
+	}
+
 	return comment, nil
 }
 
+// findThread loads the post a comment is on and the comment it replies to, for
+// notifications. Either is nil if it no longer exists.
+func (s *commentService) findThread(comment *models.Comment) (*models.Post, *models.Comment, error) {
+	post, err := s.postRepo.FindByID(comment.PostID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		post, err = nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var parent *models.Comment
+	if comment.ParentID != nil && *comment.ParentID > 0 {
+		parent, err = s.commentRepo.FindByID(*comment.ParentID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			parent, err = nil, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return post, parent, nil
+}
+
 // MarkAsSpam marks a comment as spam
 func (s *commentService) MarkAsSpam(id uint) (*models.Comment, error) {
 	comment, err := s.commentRepo.FindByID(id)
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"inkstack/internal/models"
+	"inkstack/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrFollowNotFound is returned when unfollowing an author or tag the user does not follow
+	ErrFollowNotFound = errors.New("follow not found")
+
+	// ErrTagNotFound is returned when looking up a tag nobody has used or followed
+	ErrTagNotFound = errors.New("tag not found")
+
+	// ErrAuthorNotFound is returned when following a user without a public profile
+	ErrAuthorNotFound = errors.New("author not found")
+)
+
+// Following is an author or tag a user follows
+type Following struct {
+	Type      string
+	ID        uint   // The author's user ID or the tag ID
+	Name      string // Tag name; empty for authors
+	CreatedAt time.Time
+}
+
+// FollowCounts is how many users follow an author, and how many authors and tags they follow
+type FollowCounts struct {
+	Followers int64
+	Following int64
+}
+
+// FollowService defines the interface for following authors and tags, and the feed built from them
+type FollowService interface {
+	FollowAuthor(ctx context.Context, userID, authorID uint) (*Following, error)
+	UnfollowAuthor(userID, authorID uint) error
+	FollowTag(userID uint, name string) (*Following, error)
+	UnfollowTag(userID uint, name string) error
+	ListFollowing(userID uint, targetType string, page, pageSize int) ([]Following, int64, error)
+	IsFollowing(userID uint, targetType string, targetID uint) (bool, error)
+	AuthorCounts(authorID uint) (*FollowCounts, error)
+	GetTag(name string) (*models.Tag, int64, error)
+	Feed(userID uint, beforeAt time.Time, beforeID uint, limit int) ([]models.Post, bool, error)
+}
+
+// followService implements FollowService
+type followService struct {
+	followRepo repository.FollowRepository
+	tagRepo    repository.TagRepository
+	postRepo   repository.PostRepository
+	authClient *AuthClient
+}
+
+// NewFollowService creates a new follow service
+func NewFollowService(followRepo repository.FollowRepository, tagRepo repository.TagRepository, postRepo repository.PostRepository, authClient *AuthClient) FollowService {
+	return &followService{
+		followRepo: followRepo,
+		tagRepo:    tagRepo,
+		postRepo:   postRepo,
+		authClient: authClient,
+	}
+}
+
+// FollowAuthor adds an author's published posts to the user's feed. Following an
+// author again succeeds without change. The author must have a public profile in
+// the auth service.
+func (s *followService) FollowAuthor(ctx context.Context, userID, authorID uint) (*Following, error) {
+	if authorID == 0 {
+		return nil, errors.New("user_id is required")
+	}
+	if userID == authorID {
+		return nil, errors.New("you cannot follow yourself")
+	}
+
+	profiles, err := s.authClient.GetUserProfiles(ctx, []uint{authorID})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrAuthorsUnavailable, err)
+	}
+	if _, ok := profiles[authorID]; !ok {
+		return nil, ErrAuthorNotFound
+	}
+
+	follow := &models.Follow{
+		FollowerID: userID,
+		TargetType: models.FollowTargetAuthor,
+		TargetID:   authorID,
+	}
+	if err := s.followRepo.Create(follow); err != nil {
+		return nil, fmt.Errorf("failed to follow author: %w", err)
+	}
+
+	return &Following{
+		Type:      models.FollowTargetAuthor,
+		ID:        authorID,
+		CreatedAt: follow.CreatedAt,
+	}, nil
+}
+
+// UnfollowAuthor removes an author's posts from the user's feed
+func (s *followService) UnfollowAuthor(userID, authorID uint) error {
+	return s.unfollow(userID, models.FollowTargetAuthor, authorID)
+}
+
+// FollowTag adds published posts with a tag to the user's feed. Tags can be
+// followed before any post uses them. Following a tag again succeeds without change.
+func (s *followService) FollowTag(userID uint, name string) (*Following, error) {
+	name, err := validateTagName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.FindOrCreate([]string{name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	if len(tags) == 0 {
+		return nil, ErrTagNotFound
+	}
+
+	follow := &models.Follow{
+		FollowerID: userID,
+		TargetType: models.FollowTargetTag,
+		TargetID:   tags[0].ID,
+	}
+	if err := s.followRepo.Create(follow); err != nil {
+		return nil, fmt.Errorf("failed to follow tag: %w", err)
+	}
+
+	return &Following{
+		Type:      models.FollowTargetTag,
+		ID:        tags[0].ID,
+		Name:      tags[0].Name,
+		CreatedAt: follow.CreatedAt,
+	}, nil
+}
+
+// UnfollowTag removes posts with a tag from the user's feed
+func (s *followService) UnfollowTag(userID uint, name string) error {
+	tag, _, err := s.GetTag(name)
+	if err != nil {
+		if errors.Is(err, ErrTagNotFound) {
+			return ErrFollowNotFound
+		}
+		return err
+	}
+	return s.unfollow(userID, models.FollowTargetTag, tag.ID)
+}
+
+// ListFollowing retrieves the authors and tags a user follows with pagination, newest
+// first. targetType limits the list to authors or tags; empty includes both.
+func (s *followService) ListFollowing(userID uint, targetType string, page, pageSize int) ([]Following, int64, error) {
+	if targetType != "" && targetType != models.FollowTargetAuthor && targetType != models.FollowTargetTag {
+		return nil, 0, fmt.Errorf("invalid follow type: %s", targetType)
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	follows, err := s.followRepo.FindByFollower(userID, targetType, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.followRepo.CountByFollower(userID, targetType)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Look up the names of followed tags
+	var tagIDs []uint
+	for _, follow := range follows {
+		if follow.TargetType == models.FollowTargetTag {
+			tagIDs = append(tagIDs, follow.TargetID)
+		}
+	}
+	tags, err := s.tagRepo.FindByIDs(tagIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	tagNames := make(map[uint]string, len(tags))
+	for _, tag := range tags {
+		tagNames[tag.ID] = tag.Name
+	}
+
+	following := make([]Following, len(follows))
+	for i, follow := range follows {
+		following[i] = Following{
+			Type:      follow.TargetType,
+			ID:        follow.TargetID,
+			CreatedAt: follow.CreatedAt,
+		}
+		if follow.TargetType == models.FollowTargetTag {
+			following[i].Name = tagNames[follow.TargetID]
+		}
+	}
+
+	return following, total, nil
+}
+
+// IsFollowing checks whether a user follows an author or tag. userID is 0 for
+// anonymous viewers, who follow nothing.
+func (s *followService) IsFollowing(userID uint, targetType string, targetID uint) (bool, error) {
+	if userID == 0 {
+		return false, nil
+	}
+	return s.followRepo.Exists(userID, targetType, targetID)
+}
+
+// AuthorCounts returns how many users follow an author and how many authors and tags they follow
+func (s *followService) AuthorCounts(authorID uint) (*FollowCounts, error) {
+	followers, err := s.followRepo.CountFollowers(models.FollowTargetAuthor, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count followers: %w", err)
+	}
+
+	following, err := s.followRepo.CountByFollower(authorID, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to count follows: %w", err)
+	}
+
+	return &FollowCounts{
+		Followers: followers,
+		Following: following,
+	}, nil
+}
+
+// GetTag retrieves a tag by name together with its number of followers
+func (s *followService) GetTag(name string) (*models.Tag, int64, error) {
+	name, err := validateTagName(name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tag, err := s.tagRepo.FindByName(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, ErrTagNotFound
+		}
+		return nil, 0, err
+	}
+
+	followers, err := s.followRepo.CountFollowers(models.FollowTargetTag, tag.ID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count followers: %w", err)
+	}
+
+	return tag, followers, nil
+}
+
+// Feed retrieves a page of published posts by the authors, or with the tags, a user
+// follows, newest first, and reports whether there are more. beforeID is 0 for the
+// first page; later pages pass the publish time and ID of the last post so far.
+func (s *followService) Feed(userID uint, beforeAt time.Time, beforeID uint, limit int) ([]models.Post, bool, error) {
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	var before *repository.FeedPosition
+	if beforeID != 0 {
+		before = &repository.FeedPosition{PublishedAt: beforeAt, ID: beforeID}
+	}
+
+	// Fetch one extra row to learn whether there is another page
+	posts, err := s.postRepo.FindFeed(userID, before, limit+1)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load feed: %w", err)
+	}
+
+	if len(posts) > limit {
+		return posts[:limit], true, nil
+	}
+	return posts, false, nil
+}
+
+// unfollow removes a follow, returning ErrFollowNotFound if there was none
+func (s *followService) unfollow(userID uint, targetType string, targetID uint) error {
+	removed, err := s.followRepo.Delete(userID, targetType, targetID)
+	if err != nil {
+		return fmt.Errorf("failed to unfollow %s: %w", targetType, err)
+	}
+	if !removed {
+		return ErrFollowNotFound
+	}
+	return nil
+}
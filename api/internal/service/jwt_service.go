@@ -3,20 +3,51 @@ package service
 import (
 	"fmt"
 	"inkstack/internal/config"
+	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// ServiceAudience is the audience of tokens that services sign to call each other.
+// Tokens issued to users and OAuth clients never carry it.
+const ServiceAudience = "service"
+
+// TokenTypeAccess is the typ claim of access tokens issued by the auth service
+const TokenTypeAccess = "access"
+
+// serviceTokenExpiry is how long a service token is valid
+const serviceTokenExpiry = 5 * time.Minute
+
 // JWTClaims represents the claims in a JWT token
 type JWTClaims struct {
 	UserID   uint   `json:"user_id"`
 	Email    string `json:"email"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	ClientID string `json:"client_id,omitempty"` // Set for tokens issued to OAuth clients
+	Scope    string `json:"scope,omitempty"`     // Space-separated scopes granted to the OAuth client
+
+	// TokenType separates access tokens from refresh tokens, which must never be
+	// accepted as bearer tokens. Service tokens carry neither.
+	TokenType string `json:"typ,omitempty"`
+
+	// Permissions granted by the user's role when the token was issued
+	Permissions []string `json:"permissions,omitempty"`
+
+	// Actor is set when a staff member is acting as this user (RFC 8693 "act" claim)
+	Actor *ActorClaim `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// JWTService handles JWT token validation
+// ActorClaim identifies the staff member behind an impersonation token
+type ActorClaim struct {
+	Subject  string `json:"sub"`
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// JWTService handles JWT token validation and signs service tokens
 type JWTService struct {
 	config *config.Config
 }
@@ -28,6 +59,34 @@ func NewJWTService(cfg *config.Config) *JWTService {
 	}
 }
 
+// GenerateServiceToken generates a short-lived token that identifies this service to
+// another internal service. The token carries no user identity.
+func (s *JWTService) GenerateServiceToken(clientID string, scopes []string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(serviceTokenExpiry)
+
+	claims := JWTClaims{
+		ClientID: clientID,
+		Scope:    strings.Join(scopes, " "),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "inkstack-api",
+			Subject:   "service:" + clientID,
+			Audience:  jwt.ClaimStrings{ServiceAudience},
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(s.config.JWT.Secret))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign service token: %w", err)
+	}
+
+	return tokenString, expiresAt, nil
+}
+
 // ValidateToken validates a JWT token and returns the claims
 func (s *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
@@ -53,3 +112,18 @@ func (s *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
 
 	return claims, nil
 }
+
+// ValidateAccessToken validates a JWT and checks that it is an access token, not a
+// refresh or service token
+func (s *JWTService) ValidateAccessToken(tokenString string) (*JWTClaims, error) {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != TokenTypeAccess {
+		return nil, fmt.Errorf("unexpected token type %q", claims.TokenType)
+	}
+
+	return claims, nil
+}
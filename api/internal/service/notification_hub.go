@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"inkstack/internal/models"
+	"log"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NotificationsChannel is the Redis pub/sub channel api replicas share new notifications on
+const NotificationsChannel = "notifications:api"
+
+// subscriberBuffer is how many notifications may queue for a slow stream before
+// further ones are dropped. Dropped notifications are still in the inbox.
+const subscriberBuffer = 16
+
+// NotificationHub delivers new notifications to open notification streams. With Redis,
+// notifications are fanned out through pub/sub so streams on every api replica see them;
+// without it, only streams on the replica that created a notification do.
+type NotificationHub struct {
+	redis       *redis.Client
+	mu          sync.Mutex
+	subscribers map[uint]map[chan models.Notification]struct{}
+	closed      bool
+}
+
+// NewNotificationHub creates a new notification hub. redisClient may be nil.
+func NewNotificationHub(redisClient *redis.Client) *NotificationHub {
+	return &NotificationHub{
+		redis:       redisClient,
+		subscribers: make(map[uint]map[chan models.Notification]struct{}),
+	}
+}
+
+// Publish sends a notification to its recipient's open streams. If Redis is unavailable
+// the notification is only delivered on this replica.
+func (h *NotificationHub) Publish(ctx context.Context, notification models.Notification) {
+	if h.redis != nil {
+		payload, err := json.Marshal(notification)
+		if err == nil {
+			err = h.redis.Publish(ctx, NotificationsChannel, payload).Err()
+		}
+		if err == nil {
+			return
+		}
+		log.Printf("Failed to publish notification %d: %v", notification.ID, err)
+	}
+	h.deliver(notification)
+}
+
+// Run relays notifications published by any replica to streams on this one until ctx
+// is cancelled. It is only needed when the hub has a Redis client.
+func (h *NotificationHub) Run(ctx context.Context) {
+	pubsub := h.redis.Subscribe(ctx, NotificationsChannel)
+	defer pubsub.Close()
+
+	log.Printf("Relaying notifications from %s", NotificationsChannel)
+
+	// The channel survives reconnects; it is closed when pubsub is
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var notification models.Notification
+			if err := json.Unmarshal([]byte(message.Payload), &notification); err != nil {
+				log.Printf("Ignoring malformed notification message: %v", err)
+				continue
+			}
+			h.deliver(notification)
+		}
+	}
+}
+
+// Subscribe opens a stream of a user's new notifications. The returned function must
+// be called to close it. The channel is also closed when the hub shuts down.
+func (h *NotificationHub) Subscribe(userID uint) (<-chan models.Notification, func()) {
+	ch := make(chan models.Notification, subscriberBuffer)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan models.Notification]struct{})
+	}
+	h.subscribers[userID][ch] = struct{}{}
+
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		// Close may already have closed the channel
+		if _, ok := h.subscribers[userID][ch]; !ok {
+			return
+		}
+		delete(h.subscribers[userID], ch)
+		if len(h.subscribers[userID]) == 0 {
+			delete(h.subscribers, userID)
+		}
+		close(ch)
+	}
+}
+
+// Close ends every open stream so the server can shut down
+func (h *NotificationHub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for userID, channels := range h.subscribers {
+		for ch := range channels {
+			close(ch)
+		}
+		delete(h.subscribers, userID)
+	}
+}
+
+// deliver hands a notification to its recipient's streams on this replica without
+// blocking; streams that have fallen behind miss it
+func (h *NotificationHub) deliver(notification models.Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subscribers[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"inkstack/internal/models"
+	"inkstack/internal/repository"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrNotificationNotFound is returned when a notification does not exist or belongs to another user
+var ErrNotificationNotFound = errors.New("notification not found")
+
+// maxMentions caps how many users a single comment can notify by @mentioning them
+const maxMentions = 10
+
+// mentionPattern matches @username where the @ doesn't follow a word character, so
+// email addresses aren't mentions. Usernames are validated by length separately.
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_@-])@([A-Za-z0-9_-]+)`)
+
+// NotificationService defines the interface for the notification inbox
+type NotificationService interface {
+	NotifyCommentCreated(comment *models.Comment, post *models.Post)
+	NotifyCommentModerated(comment *models.Comment, post *models.Post, parent *models.Comment)
+	ListNotifications(userID uint, unreadOnly bool, cursor uint, limit int) ([]models.Notification, uint, error)
+	UnreadCount(userID uint) (int64, error)
+	MarkRead(userID, id uint) error
+	MarkAllRead(userID uint) (int64, error)
+	Subscribe(userID uint) (<-chan models.Notification, func())
+}
+
+// notificationService implements NotificationService
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	relationshipRepo repository.RelationshipRepository
+	hub              *NotificationHub
+	authClient       *AuthClient
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepo repository.NotificationRepository, relationshipRepo repository.RelationshipRepository, hub *NotificationHub, authClient *AuthClient) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		relationshipRepo: relationshipRepo,
+		hub:              hub,
+		authClient:       authClient,
+	}
+}
+
+// NotifyCommentCreated tells the post's author about a new comment awaiting moderation.
+// Nobody else hears of a comment until it is approved. Notifications are created in the
+// background, so a failure never fails the comment; failures are logged.
+func (s *notificationService) NotifyCommentCreated(comment *models.Comment, post *models.Post) {
+	actorID, postID, commentID := comment.UserID, comment.PostID, comment.ID
+	postAuthorID := post.AuthorID
+
+	go func() {
+		if postAuthorID != actorID {
+			s.create(postAuthorID, models.NotificationPostComment, &actorID, postID, commentID)
+		}
+	}()
+}
+
+// NotifyCommentModerated tells a comment's author that it was approved or rejected. An
+// approved comment is also announced to the author of the comment it replies to and to
+// anyone @mentioned in it, each at most once; the post's author already heard of it
+// when it was created. post is nil if the post is gone. Other statuses, such as spam,
+// are not announced. Like NotifyCommentCreated, this runs in the background.
+func (s *notificationService) NotifyCommentModerated(comment *models.Comment, post *models.Post, parent *models.Comment) {
+	var notificationType string
+	switch comment.Status {
+	case "approved":
+		notificationType = models.NotificationCommentApproved
+	case "rejected":
+		notificationType = models.NotificationCommentRejected
+	default:
+		return
+	}
+
+	// Copy what's needed so the caller may keep using its values
+	actorID, postID, commentID := comment.UserID, comment.PostID, comment.ID
+	content, approved := comment.Content, comment.Status == "approved"
+	var postAuthorID, parentAuthorID uint
+	postPublished := false
+	if post != nil {
+		postAuthorID, postPublished = post.AuthorID, post.Status == "published"
+	}
+	if parent != nil {
+		parentAuthorID = parent.UserID
+	}
+
+	go func() {
+		s.create(actorID, notificationType, nil, postID, commentID)
+		if !approved || post == nil {
+			return
+		}
+
+		notified := map[uint]bool{actorID: true, postAuthorID: true}
+		notify := func(recipientID uint, notificationType string) {
+			if recipientID == 0 || notified[recipientID] {
+				return
+			}
+			notified[recipientID] = true
+			s.create(recipientID, notificationType, &actorID, postID, commentID)
+		}
+
+		notify(parentAuthorID, models.NotificationCommentReply)
+
+		// Mentioned users can only follow the notification to a published post
+		if !postPublished {
+			return
+		}
+		for _, username := range parseMentions(content) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			profile, err := s.authClient.GetUserProfile(ctx, username)
+			cancel()
+			if err != nil {
+				if !errors.Is(err, ErrUserNotFound) {
+					log.Printf("Failed to resolve mention of %q in comment %d: %v", username, commentID, err)
+				}
+				continue
+			}
+			notify(profile.ID, models.NotificationMention)
+		}
+	}()
+}
+
+// create stores a notification and sends it to the recipient's open streams. Nothing is
+// created if the recipient has blocked or muted the actor.
+func (s *notificationService) create(recipientID uint, notificationType string, actorID *uint, postID, commentID uint) {
+	if actorID != nil {
+		for _, kind := range []string{models.RelationshipBlock, models.RelationshipMute} {
+			exists, err := s.relationshipRepo.Exists(recipientID, *actorID, kind)
+			if err != nil {
+				log.Printf("Failed to check relationships for %s notification to user %d: %v", notificationType, recipientID, err)
+				return
+			}
+			if exists {
+				return
+			}
+		}
+	}
+
+	notification := &models.Notification{
+		UserID:    recipientID,
+		Type:      notificationType,
+		ActorID:   actorID,
+		PostID:    &postID,
+		CommentID: &commentID,
+	}
+	if err := s.notificationRepo.Create(notification); err != nil {
+		log.Printf("Failed to create %s notification for user %d: %v", notificationType, recipientID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.hub.Publish(ctx, *notification)
+}
+
+// ListNotifications retrieves a page of a user's notifications, newest first. cursor is
+// the ID to continue below (0 for the first page); the returned cursor is 0 on the last page.
+func (s *notificationService) ListNotifications(userID uint, unreadOnly bool, cursor uint, limit int) ([]models.Notification, uint, error) {
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// Fetch one extra row to learn whether there is another page
+	notifications, err := s.notificationRepo.FindByUser(userID, unreadOnly, cursor, limit+1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var next uint
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+		next = notifications[limit-1].ID
+	}
+
+	return notifications, next, nil
+}
+
+// UnreadCount returns the number of a user's unread notifications
+func (s *notificationService) UnreadCount(userID uint) (int64, error) {
+	count, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks one of a user's notifications read
+func (s *notificationService) MarkRead(userID, id uint) error {
+	found, err := s.notificationRepo.MarkRead(userID, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	if !found {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// MarkAllRead marks all of a user's notifications read and returns how many were unread
+func (s *notificationService) MarkAllRead(userID uint) (int64, error) {
+	count, err := s.notificationRepo.MarkAllRead(userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return count, nil
+}
+
+// Subscribe opens a stream of a user's new notifications; see NotificationHub.Subscribe
+func (s *notificationService) Subscribe(userID uint) (<-chan models.Notification, func()) {
+	return s.hub.Subscribe(userID)
+}
+
+// parseMentions returns the distinct usernames @mentioned in content, up to maxMentions
+func parseMentions(content string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := match[1]
+		key := strings.ToLower(username)
+		if len(username) < 3 || len(username) > 30 || seen[key] {
+			continue
+		}
+		seen[key] = true
+		usernames = append(usernames, username)
+		if len(usernames) == maxMentions {
+			break
+		}
+	}
+	return usernames
+}
@@ -6,6 +6,7 @@ import (
 	"inkstack/internal/models"
 	"inkstack/internal/repository"
 	"inkstack/internal/util"
+	"slices"
 	"time"
 
 	"gorm.io/gorm"
@@ -13,31 +14,42 @@ import (
 
 // PostService defines the interface for post business logic
 type PostService interface {
-	CreatePost(title, content, excerpt, slug string, authorID uint) (*models.Post, error)
+	CreatePost(title, content, excerpt, slug string, authorID uint, tags []string) (*models.Post, error)
 	GetPost(id uint) (*models.Post, error)
+	FindPost(id uint) (*models.Post, error)
 	GetPostBySlug(slug string) (*models.Post, error)
 	ListPosts(page, pageSize int) ([]models.Post, int64, error)
 	ListPostsByAuthor(authorID uint, page, pageSize int) ([]models.Post, int64, error)
 	ListPublishedPosts(page, pageSize int) ([]models.Post, int64, error)
+	ListPublishedPostsByAuthor(authorID uint, page, pageSize int) ([]models.Post, int64, error)
 	UpdatePost(id uint, updates map[string]interface{}) (*models.Post, error)
 	DeletePost(id uint) error
 	PublishPost(id uint) (*models.Post, error)
 	UnpublishPost(id uint) (*models.Post, error)
 	GenerateSlug(title string) string
+	SetTags(postID uint, tags []string) error
+	TagsByPost(postIDs []uint) (map[uint][]string, error)
 }
 
+// maxPostTags is the most tags a post can have
+const maxPostTags = 10
+
 // postService implements PostService
 type postService struct {
-	repo repository.PostRepository
+	repo    repository.PostRepository
+	tagRepo repository.TagRepository
 }
 
 // NewPostService creates a new post service
-func NewPostService(repo repository.PostRepository) PostService {
-	return &postService{repo: repo}
+func NewPostService(repo repository.PostRepository, tagRepo repository.TagRepository) PostService {
+	return &postService{
+		repo:    repo,
+		tagRepo: tagRepo,
+	}
 }
 
 // CreatePost creates a new post
-func (s *postService) CreatePost(title, content, excerpt, slug string, authorID uint) (*models.Post, error) {
+func (s *postService) CreatePost(title, content, excerpt, slug string, authorID uint, tags []string) (*models.Post, error) {
 	// Validate inputs
 	if title == "" {
 		return nil, errors.New("title is required")
@@ -62,6 +74,11 @@ func (s *postService) CreatePost(title, content, excerpt, slug string, authorID
 		return nil, errors.New("slug already exists")
 	}
 
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
 	post := &models.Post{
 		Title:     title,
 		Slug:      slug,
@@ -76,6 +93,10 @@ func (s *postService) CreatePost(title, content, excerpt, slug string, authorID
 		return nil, fmt.Errorf("failed to create post: %w", err)
 	}
 
+	if err := s.SetTags(post.ID, tags); err != nil {
+		return nil, err
+	}
+
 	return post, nil
 }
 
@@ -95,6 +116,18 @@ func (s *postService) GetPost(id uint) (*models.Post, error) {
 	return post, nil
 }
 
+// FindPost retrieves a post by ID without counting a view, for checks before changing it
+func (s *postService) FindPost(id uint) (*models.Post, error) {
+	post, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("post not found")
+		}
+		return nil, err
+	}
+	return post, nil
+}
+
 // GetPostBySlug retrieves a post by slug and increments view count
 func (s *postService) GetPostBySlug(slug string) (*models.Post, error) {
 	post, err := s.repo.FindBySlug(slug)
@@ -182,6 +215,29 @@ func (s *postService) ListPublishedPosts(page, pageSize int) ([]models.Post, int
 	return posts, total, nil
 }
 
+// ListPublishedPostsByAuthor retrieves an author's published posts with pagination
+func (s *postService) ListPublishedPostsByAuthor(authorID uint, page, pageSize int) ([]models.Post, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	posts, err := s.repo.FindPublishedByAuthor(authorID, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.repo.CountPublishedByAuthor(authorID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return posts, total, nil
+}
+
 // UpdatePost updates a post
 func (s *postService) UpdatePost(id uint, updates map[string]interface{}) (*models.Post, error) {
 	post, err := s.repo.FindByID(id)
@@ -220,12 +276,29 @@ func (s *postService) UpdatePost(id uint, updates map[string]interface{}) (*mode
 			return nil, errors.New("invalid status")
 		}
 		post.Status = status
+		// Feeds order posts by publish time
+		if status == "published" && post.PublishedAt == nil {
+			now := time.Now()
+			post.PublishedAt = &now
+		}
+	}
+	tags, setTags := updates["tags"].([]string)
+	if setTags {
+		if tags, err = normalizeTags(tags); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.repo.Update(post); err != nil {
 		return nil, fmt.Errorf("failed to update post: %w", err)
 	}
 
+	if setTags {
+		if err := s.SetTags(id, tags); err != nil {
+			return nil, err
+		}
+	}
+
 	return post, nil
 }
 
@@ -291,3 +364,70 @@ func (s *postService) UnpublishPost(id uint) (*models.Post, error) {
 func (s *postService) GenerateSlug(title string) string {
 	return util.GenerateSlug(title)
 }
+
+// SetTags replaces a post's tags, creating tags nobody has used yet
+func (s *postService) SetTags(postID uint, tags []string) error {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return err
+	}
+
+	found, err := s.tagRepo.FindOrCreate(tags)
+	if err != nil {
+		return fmt.Errorf("failed to create tags: %w", err)
+	}
+	tagIDs := make([]uint, len(found))
+	for i, tag := range found {
+		tagIDs[i] = tag.ID
+	}
+
+	if err := s.tagRepo.SetPostTags(postID, tagIDs); err != nil {
+		return fmt.Errorf("failed to set tags: %w", err)
+	}
+	return nil
+}
+
+// TagsByPost returns the tag names on each of the given posts, in name order
+func (s *postService) TagsByPost(postIDs []uint) (map[uint][]string, error) {
+	names, err := s.tagRepo.FindNamesByPosts(postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+
+	tags := make(map[uint][]string)
+	for _, name := range names {
+		tags[name.PostID] = append(tags[name.PostID], name.Name)
+	}
+	return tags, nil
+}
+
+// normalizeTags normalizes and de-duplicates tags, and checks there are not too many
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name, err := validateTagName(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+
+	if len(normalized) > maxPostTags {
+		return nil, fmt.Errorf("a post can have at most %d tags", maxPostTags)
+	}
+	return normalized, nil
+}
+
+// validateTagName normalizes a tag name and checks it is usable
+func validateTagName(name string) (string, error) {
+	normalized := util.NormalizeTag(name)
+	if normalized == "" {
+		return "", fmt.Errorf("invalid tag: %q", name)
+	}
+	if len(normalized) > util.MaxTagLength {
+		return "", fmt.Errorf("tag exceeds maximum length of %d characters", util.MaxTagLength)
+	}
+	return normalized, nil
+}
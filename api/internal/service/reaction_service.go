@@ -0,0 +1,212 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"inkstack/internal/models"
+	"inkstack/internal/repository"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrReactionTargetNotFound is returned when reacting to a post or comment that does not exist
+	ErrReactionTargetNotFound = errors.New("reaction target not found")
+
+	// ErrReactionBlocked is returned when reacting to a post or comment by someone who has blocked the user
+	ErrReactionBlocked = errors.New("you cannot react to this user's posts or comments")
+)
+
+// ReactionSummary is the number of reactions of one type on a post or comment
+type ReactionSummary struct {
+	Type        string
+	Count       int64
+	ReactedByMe bool
+}
+
+// ReactionService defines the interface for reacting to posts and comments
+type ReactionService interface {
+	Types() []string
+	Toggle(targetType string, targetID, userID uint, reactionType string) (bool, error)
+	Summaries(targetType string, targetIDs []uint, viewerID uint) (map[uint][]ReactionSummary, error)
+	Summary(targetType string, targetID, viewerID uint) ([]ReactionSummary, error)
+}
+
+// reactionService implements ReactionService
+type reactionService struct {
+	reactionRepo     repository.ReactionRepository
+	postRepo         repository.PostRepository
+	commentRepo      repository.CommentRepository
+	relationshipRepo repository.RelationshipRepository
+	types            []string
+}
+
+// NewReactionService creates a new reaction service. Like is always available;
+// emojis adds further reaction types.
+func NewReactionService(reactionRepo repository.ReactionRepository, postRepo repository.PostRepository, commentRepo repository.CommentRepository, relationshipRepo repository.RelationshipRepository, emojis []string) ReactionService {
+	types := []string{models.ReactionLike}
+	for _, emoji := range emojis {
+		if emoji != "" && !slices.Contains(types, emoji) {
+			types = append(types, emoji)
+		}
+	}
+
+	return &reactionService{
+		reactionRepo:     reactionRepo,
+		postRepo:         postRepo,
+		commentRepo:      commentRepo,
+		relationshipRepo: relationshipRepo,
+		types:            types,
+	}
+}
+
+// Types returns the available reaction types in display order
+func (s *reactionService) Types() []string {
+	return slices.Clone(s.types)
+}
+
+// Toggle adds the user's reaction to a post or comment, or removes it if they
+// already left it, and reports whether the reaction is now present. Removing is
+// always allowed, so a reaction can be taken back after its target is hidden.
+func (s *reactionService) Toggle(targetType string, targetID, userID uint, reactionType string) (bool, error) {
+	if userID == 0 {
+		return false, errors.New("user_id is required")
+	}
+	if !slices.Contains(s.types, reactionType) {
+		return false, fmt.Errorf("invalid reaction type: %s", reactionType)
+	}
+
+	removed, err := s.reactionRepo.Delete(targetType, targetID, userID, reactionType)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove reaction: %w", err)
+	}
+	if removed {
+		return false, nil
+	}
+
+	if err := s.checkTarget(targetType, targetID, userID); err != nil {
+		return false, err
+	}
+
+	reaction := &models.Reaction{
+		TargetType: targetType,
+		TargetID:   targetID,
+		UserID:     userID,
+		Type:       reactionType,
+	}
+	if err := s.reactionRepo.Create(reaction); err != nil {
+		return false, fmt.Errorf("failed to add reaction: %w", err)
+	}
+	return true, nil
+}
+
+// Summaries returns the reaction counts on each of the given targets, keyed by
+// target ID and ordered by reaction type. Types nobody has used are left out.
+// viewerID is 0 for anonymous viewers, who never have ReactedByMe set.
+func (s *reactionService) Summaries(targetType string, targetIDs []uint, viewerID uint) (map[uint][]ReactionSummary, error) {
+	counts, err := s.reactionRepo.CountByTargets(targetType, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reactions: %w", err)
+	}
+
+	reactedByMe := make(map[uint][]string)
+	if viewerID != 0 {
+		own, err := s.reactionRepo.FindByUserAndTargets(viewerID, targetType, targetIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load reactions: %w", err)
+		}
+		for _, reaction := range own {
+			reactedByMe[reaction.TargetID] = append(reactedByMe[reaction.TargetID], reaction.Type)
+		}
+	}
+
+	summaries := make(map[uint][]ReactionSummary)
+	for _, count := range counts {
+		summaries[count.TargetID] = append(summaries[count.TargetID], ReactionSummary{
+			Type:        count.Type,
+			Count:       count.Count,
+			ReactedByMe: slices.Contains(reactedByMe[count.TargetID], count.Type),
+		})
+	}
+	for _, targetSummaries := range summaries {
+		slices.SortFunc(targetSummaries, func(a, b ReactionSummary) int {
+			return s.typeOrder(a.Type) - s.typeOrder(b.Type)
+		})
+	}
+
+	return summaries, nil
+}
+
+// Summary returns the reaction counts on a single post or comment
+func (s *reactionService) Summary(targetType string, targetID, viewerID uint) ([]ReactionSummary, error) {
+	summaries, err := s.Summaries(targetType, []uint{targetID}, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return summaries[targetID], nil
+}
+
+// checkTarget verifies a post or comment exists and the user may react to it: posts
+// must be published, comments must be on a post the user can read, and neither the
+// post's author nor the comment's author may have blocked the user
+func (s *reactionService) checkTarget(targetType string, targetID, userID uint) error {
+	var owners []uint
+	var post *models.Post
+	switch targetType {
+	case models.ReactionTargetPost:
+		var err error
+		if post, err = s.postRepo.FindByID(targetID); err != nil {
+			return targetError(err)
+		}
+		if post.Status != "published" {
+			return ErrReactionTargetNotFound
+		}
+	case models.ReactionTargetComment:
+		comment, err := s.commentRepo.FindByID(targetID)
+		if err != nil {
+			return targetError(err)
+		}
+		if post, err = s.postRepo.FindByID(comment.PostID); err != nil {
+			return targetError(err)
+		}
+		if !postReadable(post, userID) {
+			return ErrReactionTargetNotFound
+		}
+		owners = append(owners, comment.UserID)
+	default:
+		return fmt.Errorf("invalid reaction target: %s", targetType)
+	}
+	owners = append(owners, post.AuthorID)
+
+	for _, ownerID := range owners {
+		if ownerID == userID {
+			continue
+		}
+		blocked, err := s.relationshipRepo.Exists(ownerID, userID, models.RelationshipBlock)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return ErrReactionBlocked
+		}
+	}
+	return nil
+}
+
+// targetError reports a missing post or comment as ErrReactionTargetNotFound
+func targetError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrReactionTargetNotFound
+	}
+	return err
+}
+
+// typeOrder returns the position of a reaction type in display order. Types that
+// are no longer configured sort last.
+func (s *reactionService) typeOrder(reactionType string) int {
+	if i := slices.Index(s.types, reactionType); i >= 0 {
+		return i
+	}
+	return len(s.types)
+}
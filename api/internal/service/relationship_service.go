@@ -0,0 +1,102 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"inkstack/internal/models"
+	"inkstack/internal/repository"
+)
+
+// ErrRelationshipNotFound is returned when removing a block or mute that does not exist
+var ErrRelationshipNotFound = errors.New("relationship not found")
+
+// RelationshipService defines the interface for blocking and muting users
+type RelationshipService interface {
+	Add(userID, targetID uint, kind string) (*models.UserRelationship, error)
+	Remove(userID, targetID uint, kind string) error
+	List(userID uint, kind string, page, pageSize int) ([]models.UserRelationship, int64, error)
+}
+
+// relationshipService implements RelationshipService
+type relationshipService struct {
+	repo repository.RelationshipRepository
+}
+
+// NewRelationshipService creates a new relationship service
+func NewRelationshipService(repo repository.RelationshipRepository) RelationshipService {
+	return &relationshipService{repo: repo}
+}
+
+// Add blocks or mutes a user. Adding an existing relationship succeeds without change.
+func (s *relationshipService) Add(userID, targetID uint, kind string) (*models.UserRelationship, error) {
+	if err := validateRelationshipKind(kind); err != nil {
+		return nil, err
+	}
+	if targetID == 0 {
+		return nil, errors.New("user_id is required")
+	}
+	if userID == targetID {
+		return nil, fmt.Errorf("you cannot %s yourself", kind)
+	}
+
+	relationship := &models.UserRelationship{
+		UserID:   userID,
+		TargetID: targetID,
+		Kind:     kind,
+	}
+	if err := s.repo.Create(relationship); err != nil {
+		return nil, fmt.Errorf("failed to %s user: %w", kind, err)
+	}
+
+	return relationship, nil
+}
+
+// Remove unblocks or unmutes a user
+func (s *relationshipService) Remove(userID, targetID uint, kind string) error {
+	if err := validateRelationshipKind(kind); err != nil {
+		return err
+	}
+
+	removed, err := s.repo.Delete(userID, targetID, kind)
+	if err != nil {
+		return fmt.Errorf("failed to un%s user: %w", kind, err)
+	}
+	if !removed {
+		return ErrRelationshipNotFound
+	}
+	return nil
+}
+
+// List retrieves the users a user has blocked or muted with pagination
+func (s *relationshipService) List(userID uint, kind string, page, pageSize int) ([]models.UserRelationship, int64, error) {
+	if err := validateRelationshipKind(kind); err != nil {
+		return nil, 0, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	offset := (page - 1) * pageSize
+	relationships, err := s.repo.FindByUser(userID, kind, pageSize, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := s.repo.CountByUser(userID, kind)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return relationships, total, nil
+}
+
+// validateRelationshipKind checks kind is block or mute
+func validateRelationshipKind(kind string) error {
+	if kind != models.RelationshipBlock && kind != models.RelationshipMute {
+		return fmt.Errorf("invalid relationship kind: %s", kind)
+	}
+	return nil
+}
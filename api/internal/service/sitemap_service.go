@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"inkstack/internal/config"
+	"inkstack/internal/repository"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSitemapURLs is the most URLs one sitemap may list under the sitemaps.org protocol.
+// Larger sections are split into numbered pages.
+const MaxSitemapURLs = 50000
+
+// SitemapPages is the section for the site's fixed pages, which always has one page
+const SitemapPages = "pages"
+
+// sitemapSections lists the sections of the sitemap index in order
+var sitemapSections = []string{SitemapPages, repository.SitemapPosts, repository.SitemapAuthors, repository.SitemapTags}
+
+// ErrSitemapNotFound is returned for a sitemap section or page that doesn't exist
+var ErrSitemapNotFound = errors.New("sitemap not found")
+
+// ErrAuthorsUnavailable is returned when author profiles can't be loaded from the auth service
+var ErrAuthorsUnavailable = errors.New("author profiles unavailable")
+
+// SitemapURL is a page of the site listed in a sitemap, or a sitemap listed in the index.
+// LastModified is zero when unknown.
+type SitemapURL struct {
+	Loc          string
+	LastModified time.Time
+}
+
+// SitemapService defines the interface for sitemaps and robots.txt
+type SitemapService interface {
+	Index() ([]SitemapURL, error)
+	Sitemap(ctx context.Context, section string, page int) ([]SitemapURL, error)
+	Robots() string
+}
+
+// sitemapService implements SitemapService
+type sitemapService struct {
+	sitemapRepo repository.SitemapRepository
+	authClient  *AuthClient
+	site        config.SiteConfig
+	robots      config.RobotsConfig
+}
+
+// NewSitemapService creates a new sitemap service
+func NewSitemapService(cfg *config.Config, sitemapRepo repository.SitemapRepository, authClient *AuthClient) SitemapService {
+	return &sitemapService{
+		sitemapRepo: sitemapRepo,
+		authClient:  authClient,
+		site:        cfg.Site,
+		robots:      cfg.Robots,
+	}
+}
+
+// Index lists every sitemap page with when it last changed. Sections without
+// published content are left out.
+func (s *sitemapService) Index() ([]SitemapURL, error) {
+	postPages, err := s.pages(repository.SitemapPosts)
+	if err != nil {
+		return nil, err
+	}
+
+	var sitemaps []SitemapURL
+	for _, section := range sitemapSections {
+		var pages []repository.SitemapPage
+		switch section {
+		case SitemapPages:
+			pages = fixedPages(postPages)
+		case repository.SitemapPosts:
+			pages = postPages
+		default:
+			if pages, err = s.pages(section); err != nil {
+				return nil, err
+			}
+		}
+		for _, page := range pages {
+			sitemaps = append(sitemaps, SitemapURL{
+				Loc:          fmt.Sprintf("%s/sitemaps/%s-%d.xml", s.site.URL, section, page.Page),
+				LastModified: page.LastModified.UTC(),
+			})
+		}
+	}
+	return sitemaps, nil
+}
+
+// Sitemap lists the URLs on one page of a section. Authors without a public profile are
+// left out, so author pages may list fewer than MaxSitemapURLs URLs.
+func (s *sitemapService) Sitemap(ctx context.Context, section string, page int) ([]SitemapURL, error) {
+	if page < 1 {
+		return nil, ErrSitemapNotFound
+	}
+
+	if section == SitemapPages {
+		if page != 1 {
+			return nil, ErrSitemapNotFound
+		}
+		postPages, err := s.pages(repository.SitemapPosts)
+		if err != nil {
+			return nil, err
+		}
+		return []SitemapURL{{Loc: s.site.URL + "/", LastModified: fixedPages(postPages)[0].LastModified.UTC()}}, nil
+	}
+
+	entries, err := s.entries(section, page)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]SitemapURL, 0, len(entries))
+	switch section {
+	case repository.SitemapPosts:
+		for _, entry := range entries {
+			urls = append(urls, s.pageURL("/posts/", entry.Loc, entry.LastModified))
+		}
+	case repository.SitemapTags:
+		for _, entry := range entries {
+			urls = append(urls, s.pageURL("/tags/", entry.Loc, entry.LastModified))
+		}
+	case repository.SitemapAuthors:
+		ids := make([]uint, 0, len(entries))
+		for _, entry := range entries {
+			id, err := strconv.ParseUint(entry.Loc, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid author ID %q: %w", entry.Loc, err)
+			}
+			ids = append(ids, uint(id))
+		}
+		profiles, err := s.authClient.GetUserProfiles(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrAuthorsUnavailable, err)
+		}
+		for i, entry := range entries {
+			if profile, ok := profiles[ids[i]]; ok {
+				urls = append(urls, s.pageURL("/authors/", profile.Username, entry.LastModified))
+			}
+		}
+	}
+
+	return urls, nil
+}
+
+// Robots returns the robots.txt for the site, pointing crawlers at the sitemap index
+func (s *sitemapService) Robots() string {
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if len(s.robots.Disallow) == 0 {
+		// An empty Disallow allows everything; a group needs at least one rule
+		b.WriteString("Disallow:\n")
+	}
+	for _, path := range s.robots.Disallow {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("\nSitemap: " + s.site.URL + "/sitemap.xml\n")
+	return b.String()
+}
+
+// pages returns the pages of a posts, authors or tags section
+func (s *sitemapService) pages(section string) ([]repository.SitemapPage, error) {
+	pages, err := s.sitemapRepo.FindPages(section, MaxSitemapURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s sitemap pages: %w", section, err)
+	}
+	return pages, nil
+}
+
+// fixedPages returns the single page of the fixed pages section, which changes whenever
+// a post does
+func fixedPages(postPages []repository.SitemapPage) []repository.SitemapPage {
+	page := repository.SitemapPage{Page: 1}
+	for _, postPage := range postPages {
+		if postPage.LastModified.After(page.LastModified) {
+			page.LastModified = postPage.LastModified
+		}
+	}
+	return []repository.SitemapPage{page}
+}
+
+// entries loads one page of a section, which must exist
+func (s *sitemapService) entries(section string, page int) ([]repository.SitemapEntry, error) {
+	switch section {
+	case repository.SitemapPosts, repository.SitemapAuthors, repository.SitemapTags:
+	default:
+		return nil, ErrSitemapNotFound
+	}
+
+	entries, err := s.sitemapRepo.FindEntries(section, page, MaxSitemapURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s sitemap: %w", section, err)
+	}
+	if len(entries) == 0 {
+		return nil, ErrSitemapNotFound
+	}
+	return entries, nil
+}
+
+// pageURL builds the absolute URL of a site page under prefix
+func (s *sitemapService) pageURL(prefix, name string, lastModified time.Time) SitemapURL {
+	return SitemapURL{
+		Loc:          s.site.URL + prefix + url.PathEscape(name),
+		LastModified: lastModified.UTC(),
+	}
+}
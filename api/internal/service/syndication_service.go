@@ -0,0 +1,187 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"inkstack/internal/config"
+	"inkstack/internal/repository"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// maxSummaryLength is how long a summary generated for a post without an excerpt may be
+const maxSummaryLength = 300
+
+// SyndicationFeed is the content of an RSS, Atom or JSON feed, independent of format
+type SyndicationFeed struct {
+	Title       string
+	Description string
+	SiteURL     string // Base URL of the site, for the feed's own URL
+	HomeURL     string // Page the feed follows
+	AuthorName  string // The feed's author, or the site title for site and tag feeds
+	AuthorURL   string
+	FullContent bool // Whether feeds include full content by default
+	Items       []SyndicationItem
+}
+
+// SyndicationItem is one published post in a feed
+type SyndicationItem struct {
+	ID          string // Stable tag: URI, unaffected by slug changes
+	URL         string
+	Title       string
+	Summary     string // The post's excerpt, or the start of its content
+	Content     string // Plain text
+	Tags        []string
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+}
+
+// SyndicationService defines the interface for building syndication feeds
+type SyndicationService interface {
+	SiteFeed() (*SyndicationFeed, error)
+	AuthorFeed(author *UserProfile) (*SyndicationFeed, error)
+	TagFeed(tag string) (*SyndicationFeed, error)
+}
+
+// syndicationService implements SyndicationService
+type syndicationService struct {
+	postRepo repository.PostRepository
+	tagRepo  repository.TagRepository
+	site     config.SiteConfig
+	feeds    config.FeedConfig
+}
+
+// NewSyndicationService creates a new syndication service
+func NewSyndicationService(cfg *config.Config, postRepo repository.PostRepository, tagRepo repository.TagRepository) SyndicationService {
+	return &syndicationService{
+		postRepo: postRepo,
+		tagRepo:  tagRepo,
+		site:     cfg.Site,
+		feeds:    cfg.Feeds,
+	}
+}
+
+// SiteFeed builds the feed of the latest posts on the site
+func (s *syndicationService) SiteFeed() (*SyndicationFeed, error) {
+	return s.build(0, 0, &SyndicationFeed{
+		Title:       s.site.Title,
+		Description: s.site.Description,
+		HomeURL:     s.site.URL + "/",
+		AuthorName:  s.site.Title,
+		AuthorURL:   s.site.URL + "/",
+	})
+}
+
+// AuthorFeed builds the feed of an author's latest posts
+func (s *syndicationService) AuthorFeed(author *UserProfile) (*SyndicationFeed, error) {
+	name := author.DisplayName
+	if name == "" {
+		name = author.Username
+	}
+	authorURL := s.site.URL + "/authors/" + url.PathEscape(author.Username)
+
+	return s.build(author.ID, 0, &SyndicationFeed{
+		Title:       fmt.Sprintf("%s - %s", name, s.site.Title),
+		Description: fmt.Sprintf("Posts by %s on %s", name, s.site.Title),
+		HomeURL:     authorURL,
+		AuthorName:  name,
+		AuthorURL:   authorURL,
+	})
+}
+
+// TagFeed builds the feed of the latest posts with a tag. Returns ErrTagNotFound if no
+// post or follower has used the tag.
+func (s *syndicationService) TagFeed(tag string) (*SyndicationFeed, error) {
+	name, err := validateTagName(tag)
+	if err != nil {
+		return nil, ErrTagNotFound
+	}
+	found, err := s.tagRepo.FindByName(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+
+	return s.build(0, found.ID, &SyndicationFeed{
+		Title:       fmt.Sprintf("#%s - %s", found.Name, s.site.Title),
+		Description: fmt.Sprintf("Posts tagged %s on %s", found.Name, s.site.Title),
+		HomeURL:     s.site.URL + "/tags/" + url.PathEscape(found.Name),
+		AuthorName:  s.site.Title,
+		AuthorURL:   s.site.URL + "/",
+	})
+}
+
+// build fills in feed with the latest published posts matching authorID and tagID
+func (s *syndicationService) build(authorID, tagID uint, feed *SyndicationFeed) (*SyndicationFeed, error) {
+	posts, err := s.postRepo.FindLatestPublished(authorID, tagID, s.feeds.Size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load posts: %w", err)
+	}
+
+	postIDs := make([]uint, len(posts))
+	for i := range posts {
+		postIDs[i] = posts[i].ID
+	}
+	names, err := s.tagRepo.FindNamesByPosts(postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+	tags := make(map[uint][]string)
+	for _, name := range names {
+		tags[name.PostID] = append(tags[name.PostID], name.Name)
+	}
+
+	// Tag URIs (RFC 4151) need the host the site is served from
+	host := s.site.URL
+	if parsed, err := url.Parse(s.site.URL); err == nil {
+		host = parsed.Hostname()
+	}
+
+	feed.SiteURL = s.site.URL
+	feed.FullContent = s.feeds.FullContent
+	feed.Items = make([]SyndicationItem, len(posts))
+	for i, post := range posts {
+		summary := post.Excerpt
+		if summary == "" {
+			summary = truncateText(post.Content, maxSummaryLength)
+		}
+		feed.Items[i] = SyndicationItem{
+			ID:          fmt.Sprintf("tag:%s,%s:posts/%d", host, post.CreatedAt.UTC().Format("2006-01-02"), post.ID),
+			URL:         s.site.URL + "/posts/" + url.PathEscape(post.Slug),
+			Title:       post.Title,
+			Summary:     summary,
+			Content:     post.Content,
+			Tags:        tags[post.ID],
+			PublishedAt: post.PublishedAt.UTC(),
+			UpdatedAt:   post.UpdatedAt.UTC(),
+		}
+	}
+
+	return feed, nil
+}
+
+// truncateText collapses whitespace in text and shortens it to at most limit bytes plus
+// an ellipsis, cutting at a word boundary where there is one
+func truncateText(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= limit {
+		return text
+	}
+
+	cut := strings.LastIndexFunc(text[:limit], unicode.IsSpace)
+	if cut <= 0 {
+		// No space to cut at; back up to a rune boundary
+		cut = limit
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+	}
+	return strings.TrimRight(text[:cut], " .,;:") + "…"
+}
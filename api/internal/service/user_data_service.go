@@ -0,0 +1,145 @@
+package service
+
+import (
+	"fmt"
+	"inkstack/internal/models"
+	"inkstack/internal/repository"
+)
+
+// UserData is all content belonging to a user
+type UserData struct {
+	Posts         []models.Post
+	Comments      []models.Comment
+	Relationships []models.UserRelationship
+	Reactions     []models.Reaction
+	Bookmarks     []models.Bookmark
+	BookmarkLists []models.BookmarkList
+	Follows       []models.Follow
+	Notifications []models.Notification
+}
+
+// UserDataService defines the interface for managing all content belonging to a user
+type UserDataService interface {
+	ExportUserData(userID uint) (*UserData, error)
+	DeleteUserData(userID uint) error
+}
+
+// userDataService implements UserDataService
+type userDataService struct {
+	postRepo         repository.PostRepository
+	commentRepo      repository.CommentRepository
+	relationshipRepo repository.RelationshipRepository
+	reactionRepo     repository.ReactionRepository
+	bookmarkRepo     repository.BookmarkRepository
+	followRepo       repository.FollowRepository
+	notificationRepo repository.NotificationRepository
+}
+
+// NewUserDataService creates a new user data service
+func NewUserDataService(postRepo repository.PostRepository, commentRepo repository.CommentRepository, relationshipRepo repository.RelationshipRepository, reactionRepo repository.ReactionRepository, bookmarkRepo repository.BookmarkRepository, followRepo repository.FollowRepository, notificationRepo repository.NotificationRepository) UserDataService {
+	return &userDataService{
+		postRepo:         postRepo,
+		commentRepo:      commentRepo,
+		relationshipRepo: relationshipRepo,
+		reactionRepo:     reactionRepo,
+		bookmarkRepo:     bookmarkRepo,
+		followRepo:       followRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// ExportUserData retrieves every post and comment written by a user, every block
+// and mute they made, every reaction they left, their bookmarks and reading lists,
+// the authors and tags they follow, and the notifications they received
+func (s *userDataService) ExportUserData(userID uint) (*UserData, error) {
+	posts, err := s.postRepo.FindAllByAuthor(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export posts: %w", err)
+	}
+
+	comments, err := s.commentRepo.FindAllByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export comments: %w", err)
+	}
+
+	relationships, err := s.relationshipRepo.FindAllByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export relationships: %w", err)
+	}
+
+	reactions, err := s.reactionRepo.FindAllByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export reactions: %w", err)
+	}
+
+	bookmarks, err := s.bookmarkRepo.FindAllByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export bookmarks: %w", err)
+	}
+
+	bookmarkLists, err := s.bookmarkRepo.FindListsByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export bookmark lists: %w", err)
+	}
+
+	follows, err := s.followRepo.FindAllByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export follows: %w", err)
+	}
+
+	notifications, err := s.notificationRepo.FindAllByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export notifications: %w", err)
+	}
+
+	return &UserData{
+		Posts:         posts,
+		Comments:      comments,
+		Relationships: relationships,
+		Reactions:     reactions,
+		Bookmarks:     bookmarks,
+		BookmarkLists: bookmarkLists,
+		Follows:       follows,
+		Notifications: notifications,
+	}, nil
+}
+
+// DeleteUserData permanently deletes a user's posts, comments, reactions, bookmarks
+// and reading lists, every block, mute and follow made by or against them, and every
+// notification they received or caused.
+// It is safe to call more than once for the same user.
+func (s *userDataService) DeleteUserData(userID uint) error {
+	if userID == 0 {
+		return fmt.Errorf("user_id is required")
+	}
+
+	if err := s.postRepo.DeleteAllByAuthor(userID); err != nil {
+		return fmt.Errorf("failed to delete posts: %w", err)
+	}
+
+	if err := s.commentRepo.DeleteAllByUser(userID); err != nil {
+		return fmt.Errorf("failed to delete comments: %w", err)
+	}
+
+	if err := s.relationshipRepo.DeleteAllByUser(userID); err != nil {
+		return fmt.Errorf("failed to delete relationships: %w", err)
+	}
+
+	if err := s.reactionRepo.DeleteAllByUser(userID); err != nil {
+		return fmt.Errorf("failed to delete reactions: %w", err)
+	}
+
+	if err := s.bookmarkRepo.DeleteAllByUser(userID); err != nil {
+		return fmt.Errorf("failed to delete bookmarks: %w", err)
+	}
+
+	if err := s.followRepo.DeleteAllByUser(userID); err != nil {
+		return fmt.Errorf("failed to delete follows: %w", err)
+	}
+
+	if err := s.notificationRepo.DeleteAllByUser(userID); err != nil {
+		return fmt.Errorf("failed to delete notifications: %w", err)
+	}
+
+	return nil
+}
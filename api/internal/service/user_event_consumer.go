@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// UserEventsStream is the Redis stream the auth service publishes user lifecycle events to
+	UserEventsStream = "events:users"
+
+	// EventUserDeleted is published by the auth service when an account has been permanently deleted
+	EventUserDeleted = "user.deleted"
+
+	// UserEventsDeadLetterStream receives user events that failed maxUserEventDeliveries
+	// times, with the error, so one bad event can't hold up the others
+	UserEventsDeadLetterStream = "events:users:dead"
+
+	// userEventsGroup is the consumer group shared by all api replicas
+	userEventsGroup = "api-service"
+
+	// maxUserEventDeliveries is how often an event is attempted before it is dead-lettered
+	maxUserEventDeliveries = 5
+
+	// userEventRetryAfter is how long an event stays pending before any replica claims
+	// and retries it. This also recovers events left by replicas that went away.
+	userEventRetryAfter = 30 * time.Second
+)
+
+// UserEventConsumer applies user lifecycle events from the auth service to api data
+type UserEventConsumer struct {
+	redis           *redis.Client
+	userDataService UserDataService
+	consumer        string
+	claimCursor     string // Where the next XAUTOCLAIM scan of pending events starts
+}
+
+// NewUserEventConsumer creates a new user event consumer
+func NewUserEventConsumer(redisClient *redis.Client, userDataService UserDataService) *UserEventConsumer {
+	consumer, err := os.Hostname()
+	if err != nil {
+		consumer = "api-" + strconv.Itoa(os.Getpid())
+	}
+
+	return &UserEventConsumer{
+		redis:           redisClient,
+		userDataService: userDataService,
+		consumer:        consumer,
+		claimCursor:     "0-0",
+	}
+}
+
+// Run consumes events until ctx is cancelled. Events are acknowledged only after they
+// were handled. Failed events stay pending and are retried by whichever replica claims
+// them once idle, until they are dead-lettered.
+func (c *UserEventConsumer) Run(ctx context.Context) {
+	err := c.redis.XGroupCreateMkStream(ctx, UserEventsStream, userEventsGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		log.Printf("Failed to create user events consumer group: %v", err)
+		return
+	}
+
+	log.Printf("Consuming user events from %s as %s", UserEventsStream, c.consumer)
+
+	for ctx.Err() == nil {
+		// Retry idle pending events, from this or any other replica, then wait for new ones
+		messages, err := c.claim(ctx)
+		if err == nil && len(messages) == 0 {
+			messages, err = c.read(ctx, 5*time.Second)
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to read user events: %v", err)
+				sleepContext(ctx, 5*time.Second)
+			}
+			continue
+		}
+
+		for _, message := range messages {
+			c.process(ctx, message)
+		}
+	}
+}
+
+// claim takes over up to 10 events that have been pending for userEventRetryAfter
+func (c *UserEventConsumer) claim(ctx context.Context) ([]redis.XMessage, error) {
+	messages, next, err := c.redis.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   UserEventsStream,
+		Group:    userEventsGroup,
+		Consumer: c.consumer,
+		MinIdle:  userEventRetryAfter,
+		Start:    c.claimCursor,
+		Count:    10,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	c.claimCursor = next
+	return messages, nil
+}
+
+// read waits up to block for new events for this consumer, fetching up to 10
+func (c *UserEventConsumer) read(ctx context.Context, block time.Duration) ([]redis.XMessage, error) {
+	streams, err := c.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    userEventsGroup,
+		Consumer: c.consumer,
+		Streams:  []string{UserEventsStream, ">"},
+		Count:    10,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(streams) == 0 {
+		return nil, nil
+	}
+	return streams[0].Messages, nil
+}
+
+// process handles an event and acknowledges it. A failed event is left pending for a
+// retry, or moved to the dead-letter stream once it has used up its deliveries.
+func (c *UserEventConsumer) process(ctx context.Context, message redis.XMessage) {
+	handleErr := c.handle(message)
+	if handleErr != nil {
+		log.Printf("Failed to handle user event %s: %v", message.ID, handleErr)
+
+		pending, err := c.redis.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: UserEventsStream,
+			Group:  userEventsGroup,
+			Start:  message.ID,
+			End:    message.ID,
+			Count:  1,
+		}).Result()
+		if err != nil || len(pending) == 0 || pending[0].RetryCount < maxUserEventDeliveries {
+			return
+		}
+
+		if err := c.deadLetter(ctx, message, pending[0].RetryCount, handleErr); err != nil {
+			log.Printf("Failed to dead-letter user event %s: %v", message.ID, err)
+			return
+		}
+		log.Printf("Moved user event %s to %s after %d deliveries", message.ID, UserEventsDeadLetterStream, pending[0].RetryCount)
+	}
+
+	if err := c.redis.XAck(ctx, UserEventsStream, userEventsGroup, message.ID).Err(); err != nil {
+		log.Printf("Failed to acknowledge user event %s: %v", message.ID, err)
+	}
+}
+
+// deadLetter copies a failed event to the dead-letter stream with why it failed
+func (c *UserEventConsumer) deadLetter(ctx context.Context, message redis.XMessage, deliveries int64, handleErr error) error {
+	values := make(map[string]interface{}, len(message.Values)+3)
+	for key, value := range message.Values {
+		values[key] = value
+	}
+	values["original_id"] = message.ID
+	values["deliveries"] = deliveries
+	values["error"] = handleErr.Error()
+
+	return c.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: UserEventsDeadLetterStream,
+		Values: values,
+	}).Err()
+}
+
+// handle applies a single event. Unknown event types are ignored.
+func (c *UserEventConsumer) handle(message redis.XMessage) error {
+	eventType, _ := message.Values["type"].(string)
+	switch eventType {
+	case EventUserDeleted:
+		rawUserID, _ := message.Values["user_id"].(string)
+		userID, err := strconv.ParseUint(rawUserID, 10, 32)
+		if err != nil || userID == 0 {
+			// A malformed event can never succeed, so don't retry it forever
+			log.Printf("Ignoring %s event %s with invalid user_id %q", eventType, message.ID, rawUserID)
+			return nil
+		}
+		if err := c.userDataService.DeleteUserData(uint(userID)); err != nil {
+			return fmt.Errorf("failed to delete data for user %d: %w", userID, err)
+		}
+		log.Printf("Deleted content of user %d", userID)
+	}
+	return nil
+}
+
+// sleepContext waits for d or until ctx is cancelled
+func sleepContext(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
@@ -0,0 +1,68 @@
+package util
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor was not issued by EncodeCursor
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor returns an opaque cursor pointing after the row with the given ID.
+// Clients pass it back unchanged to fetch the next page.
+func EncodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+// DecodeCursor returns the row ID a cursor points after. An empty cursor decodes to 0,
+// meaning the first page.
+func DecodeCursor(cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(string(decoded), 10, 32)
+	if err != nil || id == 0 {
+		return 0, ErrInvalidCursor
+	}
+	return uint(id), nil
+}
+
+// EncodeTimeCursor returns an opaque cursor pointing after a row in a list ordered
+// by a timestamp and then ID, both descending
+func EncodeTimeCursor(t time.Time, id uint) string {
+	value := strconv.FormatInt(t.UnixNano(), 10) + "_" + strconv.FormatUint(uint64(id), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+// DecodeTimeCursor returns the timestamp and row ID a cursor points after. An empty
+// cursor decodes to an ID of 0, meaning the first page.
+func DecodeTimeCursor(cursor string) (time.Time, uint, error) {
+	if cursor == "" {
+		return time.Time{}, 0, nil
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	nanos, idValue, found := strings.Cut(string(decoded), "_")
+	if !found {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(idValue, 10, 32)
+	if err != nil || id == 0 {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+	// TIMESTAMP columns are read back as UTC, so decode in UTC to compare the same wall-clock time
+	return time.Unix(0, unixNano).UTC(), uint(id), nil
+}
@@ -33,3 +33,11 @@ func IsValidSlug(slug string) bool {
 	match, _ := regexp.MatchString("^[a-z0-9-]+$", slug)
 	return match && !strings.HasPrefix(slug, "-") && !strings.HasSuffix(slug, "-")
 }
+
+// MaxTagLength is the longest tag name allowed, matching the tags.name column
+const MaxTagLength = 50
+
+// NormalizeTag turns a tag as typed by a user into its stored form, e.g. "Go Lang" into "go-lang"
+func NormalizeTag(tag string) string {
+	return GenerateSlug(strings.TrimSpace(tag))
+}
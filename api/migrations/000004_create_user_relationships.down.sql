@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_user_relationships_target_id;
+
+-- Drop user_relationships table
+DROP TABLE IF EXISTS user_relationships;
@@ -0,0 +1,21 @@
+-- Create user_relationships table
+-- Note: user_id and target_id reference users in the separate auth service database
+CREATE TABLE IF NOT EXISTS user_relationships (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,  -- References auth_db.users.id (no FK constraint in microservices)
+    target_id INTEGER NOT NULL,  -- References auth_db.users.id (no FK constraint in microservices)
+    kind VARCHAR(10) NOT NULL,  -- block, mute
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_user_relationships UNIQUE (user_id, target_id, kind),
+    CONSTRAINT chk_user_relationships_kind CHECK (kind IN ('block', 'mute')),
+    CONSTRAINT chk_user_relationships_self CHECK (user_id <> target_id)
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_user_relationships_target_id ON user_relationships(target_id);
+
+-- Add table and column comments
+COMMENT ON TABLE user_relationships IS 'Users a user has blocked or muted';
+COMMENT ON COLUMN user_relationships.user_id IS 'User who blocked or muted (no FK constraint - microservices architecture)';
+COMMENT ON COLUMN user_relationships.target_id IS 'User who was blocked or muted (no FK constraint - microservices architecture)';
+COMMENT ON COLUMN user_relationships.kind IS 'block: cannot reply to the user''s posts or comments; mute: hidden from the user''s comment listings';
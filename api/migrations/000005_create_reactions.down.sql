@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_reactions_user_id;
+
+-- Drop reactions table
+DROP TABLE IF EXISTS reactions;
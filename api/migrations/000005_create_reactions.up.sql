@@ -0,0 +1,22 @@
+-- Create reactions table
+-- Note: user_id references users in the separate auth service database
+CREATE TABLE IF NOT EXISTS reactions (
+    id SERIAL PRIMARY KEY,
+    target_type VARCHAR(10) NOT NULL,  -- post, comment
+    target_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,  -- References auth_db.users.id (no FK constraint in microservices)
+    type VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_reactions UNIQUE (target_type, target_id, user_id, type),
+    CONSTRAINT chk_reactions_target_type CHECK (target_type IN ('post', 'comment'))
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_reactions_user_id ON reactions(user_id);
+
+-- Add table and column comments
+COMMENT ON TABLE reactions IS 'Reactions left by users on posts and comments';
+COMMENT ON COLUMN reactions.target_type IS 'Kind of content reacted to: post or comment';
+COMMENT ON COLUMN reactions.target_id IS 'ID of the post or comment reacted to (polymorphic, no FK constraint)';
+COMMENT ON COLUMN reactions.user_id IS 'User who reacted (no FK constraint - microservices architecture)';
+COMMENT ON COLUMN reactions.type IS 'Reaction type: like or one of the configured emoji (REACTION_EMOJIS)';
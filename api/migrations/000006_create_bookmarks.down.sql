@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_bookmarks_list_id;
+DROP INDEX IF EXISTS idx_bookmarks_post_id;
+
+-- Drop tables
+DROP TABLE IF EXISTS bookmarks;
+DROP TABLE IF EXISTS bookmark_lists;
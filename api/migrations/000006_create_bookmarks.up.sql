@@ -0,0 +1,33 @@
+-- Create bookmark_lists table
+-- Note: user_id references users in the separate auth service database
+CREATE TABLE IF NOT EXISTS bookmark_lists (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,  -- References auth_db.users.id (no FK constraint in microservices)
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_bookmark_lists_name UNIQUE (user_id, name)
+);
+
+-- Create bookmarks table
+CREATE TABLE IF NOT EXISTS bookmarks (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,  -- References auth_db.users.id (no FK constraint in microservices)
+    post_id INTEGER NOT NULL,
+    list_id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_bookmarks UNIQUE (user_id, post_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (list_id) REFERENCES bookmark_lists(id) ON DELETE SET NULL
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_bookmarks_post_id ON bookmarks(post_id);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_list_id ON bookmarks(list_id);
+
+-- Add table and column comments
+COMMENT ON TABLE bookmark_lists IS 'Named reading lists users sort their bookmarks into';
+COMMENT ON COLUMN bookmark_lists.user_id IS 'Owner of the list (no FK constraint - microservices architecture)';
+COMMENT ON TABLE bookmarks IS 'Posts users have saved to read later';
+COMMENT ON COLUMN bookmarks.user_id IS 'User who saved the post (no FK constraint - microservices architecture)';
+COMMENT ON COLUMN bookmarks.list_id IS 'Reading list the bookmark is in; NULL if unlisted. Deleting a list leaves its bookmarks unlisted';
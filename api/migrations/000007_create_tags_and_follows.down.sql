@@ -0,0 +1,9 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_follows_target;
+DROP INDEX IF EXISTS idx_post_tags_tag_id;
+DROP INDEX IF EXISTS idx_posts_feed;
+
+-- Drop tables
+DROP TABLE IF EXISTS follows;
+DROP TABLE IF EXISTS post_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,43 @@
+-- Create tags table
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create post_tags join table
+CREATE TABLE IF NOT EXISTS post_tags (
+    post_id INTEGER NOT NULL,
+    tag_id INTEGER NOT NULL,
+    PRIMARY KEY (post_id, tag_id),
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+-- Create follows table
+-- Note: follower_id, and target_id for authors, reference users in the separate auth service database
+CREATE TABLE IF NOT EXISTS follows (
+    id SERIAL PRIMARY KEY,
+    follower_id INTEGER NOT NULL,  -- References auth_db.users.id (no FK constraint in microservices)
+    target_type VARCHAR(10) NOT NULL,  -- author, tag
+    target_id INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_follows UNIQUE (follower_id, target_type, target_id),
+    CONSTRAINT chk_follows_target_type CHECK (target_type IN ('author', 'tag'))
+);
+
+-- Create indexes
+-- Feed: walk a followed author's or tag's published posts newest first
+CREATE INDEX IF NOT EXISTS idx_posts_feed ON posts(author_id, published_at DESC, id DESC)
+    WHERE status = 'published' AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_post_tags_tag_id ON post_tags(tag_id, post_id);
+-- Follower counts
+CREATE INDEX IF NOT EXISTS idx_follows_target ON follows(target_type, target_id);
+
+-- Add table and column comments
+COMMENT ON TABLE tags IS 'Topics posts are tagged with';
+COMMENT ON COLUMN tags.name IS 'Normalized tag name: lowercase letters, numbers and hyphens';
+COMMENT ON TABLE post_tags IS 'Tags on each post';
+COMMENT ON TABLE follows IS 'Authors and tags users follow for their feed';
+COMMENT ON COLUMN follows.follower_id IS 'User who follows (no FK constraint - microservices architecture)';
+COMMENT ON COLUMN follows.target_id IS 'Followed author''s user ID (no FK constraint) or tags.id (polymorphic, no FK constraint)';
@@ -0,0 +1,25 @@
+-- Create notifications table
+-- Note: user_id and actor_id reference users in the separate auth service database
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,  -- References auth_db.users.id (no FK constraint in microservices)
+    type VARCHAR(32) NOT NULL CHECK (type IN ('comment_reply', 'post_comment', 'mention', 'comment_approved', 'comment_rejected')),
+    actor_id INTEGER,  -- References auth_db.users.id (no FK constraint in microservices)
+    post_id INTEGER,
+    comment_id INTEGER,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE,
+    FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, id DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id, id DESC) WHERE read_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_notifications_actor_id ON notifications(actor_id);
+
+-- Add table and column comments
+COMMENT ON TABLE notifications IS 'In-app notifications about activity on a user''s posts and comments';
+COMMENT ON COLUMN notifications.user_id IS 'Recipient of the notification (no FK constraint - microservices architecture)';
+COMMENT ON COLUMN notifications.actor_id IS 'User whose action caused the notification; NULL for moderation outcomes';
+COMMENT ON COLUMN notifications.read_at IS 'When the recipient marked the notification read; NULL while unread';
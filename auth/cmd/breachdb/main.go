@@ -0,0 +1,118 @@
+// Command breachdb builds the breached password bloom filter used by the auth service.
+//
+// The input is either a Have I Been Pwned SHA-1 dump ("HASH:COUNT" per line, optionally
+// with -min-count to keep only frequently breached passwords) or a plain-text password
+// list with one password per line:
+//
+//	go run ./cmd/breachdb -in pwned-passwords-sha1.txt -out data/breached.bloom -min-count 10
+//	go run ./cmd/breachdb -in rockyou.txt -format plain -out data/breached.bloom
+//
+// Point PASSWORD_BREACH_FILTER at the output file to enable the check.
+package main
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"inkstack-auth/internal/util"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	in := flag.String("in", "", "input file (required)")
+	out := flag.String("out", "data/breached.bloom", "output bloom filter file")
+	format := flag.String("format", "sha1", "input format: sha1 (HASH:COUNT lines) or plain (one password per line)")
+	minCount := flag.Int("min-count", 1, "skip sha1 entries seen fewer times than this")
+	fpRate := flag.Float64("fp-rate", 0.001, "target false positive rate")
+	flag.Parse()
+
+	if *in == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *format != "sha1" && *format != "plain" {
+		log.Fatalf("Unknown format %q", *format)
+	}
+	if *fpRate <= 0 || *fpRate >= 1 {
+		log.Fatal("fp-rate must be between 0 and 1")
+	}
+
+	// First pass counts entries so the filter can be sized for the target rate
+	var entries uint64
+	if err := eachDigest(*in, *format, *minCount, func([]byte) { entries++ }); err != nil {
+		log.Fatal(err)
+	}
+	if entries == 0 {
+		log.Fatal("No entries found in input")
+	}
+
+	filter := util.NewBloomFilter(entries, *fpRate)
+	if err := eachDigest(*in, *format, *minCount, filter.Add); err != nil {
+		log.Fatal(err)
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		log.Fatalf("Failed to create output file: %v", err)
+	}
+	size, err := filter.WriteTo(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		log.Fatalf("Failed to write bloom filter: %v", err)
+	}
+
+	fmt.Printf("Wrote %d entries to %s (%.1f MiB, target false positive rate %g)\n",
+		entries, *out, float64(size)/(1<<20), *fpRate)
+}
+
+// eachDigest calls fn with the SHA-1 digest of every accepted entry in the input file
+func eachDigest(path, format string, minCount int, fn func([]byte)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open input: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		if format == "plain" {
+			digest := sha1.Sum([]byte(line))
+			fn(digest[:])
+			continue
+		}
+
+		hash, count, hasCount := strings.Cut(line, ":")
+		if hasCount && minCount > 1 {
+			n, err := strconv.Atoi(strings.TrimSpace(count))
+			if err != nil {
+				return fmt.Errorf("line %d: invalid count %q", lineNumber, count)
+			}
+			if n < minCount {
+				continue
+			}
+		}
+
+		digest, err := hex.DecodeString(strings.TrimSpace(hash))
+		if err != nil || len(digest) != sha1.Size {
+			return fmt.Errorf("line %d: invalid SHA-1 hash %q", lineNumber, hash)
+		}
+		fn(digest)
+	}
+
+	return scanner.Err()
+}
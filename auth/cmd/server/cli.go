@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/database"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/service"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+const cliUsage = `Usage: main <command> [flags] [arguments]
+
+Commands:
+  serve                                  Start the server (the default with no command)
+  user create -email E -username U [-role R | -admin] [-password-stdin]
+  user set-role <email|username> <role>
+  user activate <email|username>
+  user deactivate <email|username>       Also signs the user out everywhere
+  user reset-password [-password-stdin] <email|username>
+
+Passwords are read from the first line of standard input with -password-stdin.
+Otherwise a random password is generated and printed once.
+`
+
+// runCLI runs a management subcommand and returns the process exit code
+func runCLI(args []string) int {
+	if args[0] != "user" || len(args) < 2 {
+		fmt.Fprint(os.Stderr, cliUsage)
+		return 2
+	}
+
+	admin, err := newUserAdminService()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer database.Close()
+
+	command, args := args[1], args[2:]
+	switch command {
+	case "create":
+		err = createUser(admin, args)
+	case "set-role":
+		err = withUserArgs(args, 2, func(a []string) error {
+			user, err := admin.SetRole(a[0], a[1])
+			if err == nil {
+				fmt.Printf("User %s (id %d) now has role %s\n", user.Username, user.ID, user.Role)
+			}
+			return err
+		})
+	case "activate", "deactivate":
+		active := command == "activate"
+		err = withUserArgs(args, 1, func(a []string) error {
+			user, err := admin.SetActive(a[0], active)
+			if err == nil {
+				fmt.Printf("User %s (id %d) is %sd\n", user.Username, user.ID, command)
+			}
+			return err
+		})
+	case "reset-password":
+		err = resetPassword(admin, args)
+	default:
+		fmt.Fprint(os.Stderr, cliUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
+
+// newUserAdminService connects to the database, applies migrations so the command
+// also works before the server's first start, and builds the user admin service
+func newUserAdminService() (*service.UserAdminService, error) {
+	// Keep library logging out of the command's output
+	log.SetOutput(io.Discard)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := configurePasswordHashing(cfg); err != nil {
+		return nil, fmt.Errorf("invalid password hashing configuration: %w", err)
+	}
+	if err := database.Connect(cfg); err != nil {
+		return nil, err
+	}
+	if err := database.RunMigrations("./migrations"); err != nil {
+		return nil, fmt.Errorf("failed to run migrations (run from the auth/ directory): %w", err)
+	}
+
+	passwordPolicy, err := service.NewPasswordPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db := database.GetDB()
+	userRepo := repository.NewUserRepository(db)
+	auditService := service.NewAuditService(repository.NewAuthEventRepository(db), cfg)
+	return service.NewUserAdminService(
+		userRepo,
+		repository.NewTokenRepository(db),
+		repository.NewRoleRepository(db),
+		passwordPolicy,
+		auditService,
+	), nil
+}
+
+// createUser handles "user create"
+func createUser(admin *service.UserAdminService, args []string) error {
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	email := fs.String("email", "", "email address (required)")
+	username := fs.String("username", "", "username (required)")
+	role := fs.String("role", models.RoleAuthor, "role to assign")
+	isAdmin := fs.Bool("admin", false, "assign the admin role (same as -role admin)")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from standard input")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" || *username == "" {
+		return fmt.Errorf("-email and -username are required")
+	}
+	if *isAdmin {
+		*role = models.RoleAdmin
+	}
+
+	password, generated, err := readPassword(admin, *passwordStdin, *username, *email)
+	if err != nil {
+		return err
+	}
+
+	user, err := admin.CreateUser(service.CreateUserInput{
+		Email:    *email,
+		Username: *username,
+		Password: password,
+		Role:     *role,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created user %s (id %d) with role %s\n", user.Username, user.ID, user.Role)
+	if generated {
+		fmt.Printf("Password: %s\n", password)
+	}
+	return nil
+}
+
+// resetPassword handles "user reset-password"
+func resetPassword(admin *service.UserAdminService, args []string) error {
+	fs := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from standard input")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return withUserArgs(fs.Args(), 1, func(a []string) error {
+		password, generated, err := readPassword(admin, *passwordStdin, a[0])
+		if err != nil {
+			return err
+		}
+
+		user, err := admin.ResetPassword(a[0], password)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Reset the password of user %s (id %d) and signed them out everywhere\n", user.Username, user.ID)
+		if generated {
+			fmt.Printf("Password: %s\n", password)
+		}
+		return nil
+	})
+}
+
+// withUserArgs checks a command got exactly n positional arguments before running it
+func withUserArgs(args []string, n int, run func(args []string) error) error {
+	if len(args) != n {
+		return fmt.Errorf("expected %d argument(s), got %d\n\n%s", n, len(args), cliUsage)
+	}
+	return run(args)
+}
+
+// readPassword reads a password from standard input or generates one. userInputs are
+// the user's username and email, which a good password must not resemble.
+func readPassword(admin *service.UserAdminService, fromStdin bool, userInputs ...string) (string, bool, error) {
+	if !fromStdin {
+		password, err := admin.GeneratePassword(userInputs...)
+		return password, true, err
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", false, fmt.Errorf("failed to read password: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", false, fmt.Errorf("no password on standard input")
+	}
+	return password, false, nil
+}
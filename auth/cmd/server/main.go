@@ -7,9 +7,13 @@ import (
 	"inkstack-auth/internal/config"
 	"inkstack-auth/internal/database"
 	"inkstack-auth/internal/handler"
+	"inkstack-auth/internal/mailer"
 	"inkstack-auth/internal/middleware"
+	"inkstack-auth/internal/models"
 	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/scheduler"
 	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
 	"log"
 	"net/http"
 	"os"
@@ -44,6 +48,11 @@ import (
 // @description Type "Bearer" followed by a space and JWT token.
 
 func main() {
+	// Subcommands (e.g. "user create") manage accounts instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] != "serve" {
+		os.Exit(runCLI(os.Args[1:]))
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -52,6 +61,11 @@ func main() {
 
 	log.Printf("Starting Inkstack Auth Service in %s mode", cfg.App.Env)
 
+	// Configure password hashing
+	if err := configurePasswordHashing(cfg); err != nil {
+		log.Fatal("Invalid password hashing configuration:", err)
+	}
+
 	// Connect to PostgreSQL
 	if err := database.Connect(cfg); err != nil {
 		log.Fatal("Failed to connect to database:", err)
@@ -92,19 +106,109 @@ func main() {
 	// Initialize router
 	r := gin.Default()
 
+	// Client IPs come from X-Forwarded-For only when the request arrives through a trusted proxy
+	if err := r.SetTrustedProxies(cfg.App.TrustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
 	// Initialize dependencies
 	db := database.GetDB()
 
 	// Repositories
 	userRepo := repository.NewUserRepository(db)
 	tokenRepo := repository.NewTokenRepository(db)
+	patRepo := repository.NewPersonalAccessTokenRepository(db)
+	oauthClientRepo := repository.NewOAuthClientRepository(db)
+	oauthCodeRepo := repository.NewOAuthAuthorizationCodeRepository(db)
+	oauthConsentRepo := repository.NewOAuthConsentRepository(db)
+	dataExportRepo := repository.NewDataExportRepository(db)
+	emailChangeRepo := repository.NewEmailChangeRepository(db)
+	authEventRepo := repository.NewAuthEventRepository(db)
+	inviteRepo := repository.NewInviteRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	impersonationRepo := repository.NewImpersonationRepository(db)
+	knownDeviceRepo := repository.NewKnownDeviceRepository(db)
+
+	// Mailer
+	mail, err := mailer.New(cfg)
+	if err != nil {
+		log.Fatal("Failed to configure mailer:", err)
+	}
 
 	// Services
-	jwtService := service.NewJWTService(cfg)
-	authService := service.NewAuthService(userRepo, tokenRepo, jwtService)
+	auditService := service.NewAuditService(authEventRepo, cfg)
+	roleService := service.NewRoleService(roleRepo, userRepo, auditService)
+	if _, err := roleRepo.FindByName(cfg.Registration.DefaultRole); err != nil {
+		log.Printf("Warning: default role %q is not available: %v", cfg.Registration.DefaultRole, err)
+	}
+	jwtService := service.NewJWTService(cfg, roleService)
+	impersonationService := service.NewImpersonationService(userRepo, impersonationRepo, jwtService, roleService, auditService)
+	passwordPolicy, err := service.NewPasswordPolicy(cfg)
+	if err != nil {
+		log.Fatal("Failed to load password policy:", err)
+	}
+	registrationPolicy, err := service.NewRegistrationPolicy(cfg, inviteRepo)
+	if err != nil {
+		log.Fatal("Invalid registration policy:", err)
+	}
+	userAdminService := service.NewUserAdminService(userRepo, tokenRepo, roleRepo, passwordPolicy, auditService)
+	if cfg.Bootstrap.AdminEmail != "" {
+		admin, created, err := userAdminService.BootstrapAdmin(cfg.Bootstrap.AdminEmail, cfg.Bootstrap.AdminUsername, cfg.Bootstrap.AdminPassword)
+		if err != nil {
+			log.Fatal("Failed to bootstrap admin account:", err)
+		}
+		if created {
+			log.Printf("Created bootstrap admin account %s (id %d)", admin.Email, admin.ID)
+		}
+	}
+	deviceService, err := service.NewDeviceService(knownDeviceRepo, tokenRepo, auditService, mail, cfg)
+	if err != nil {
+		log.Fatal("Failed to load GeoIP database:", err)
+	}
+	authService := service.NewAuthService(userRepo, tokenRepo, jwtService, passwordPolicy, auditService, registrationPolicy, mail, deviceService, cfg)
+	inviteService := service.NewInviteService(inviteRepo)
+	userService := service.NewUserService(userRepo)
+	patService := service.NewPersonalAccessTokenService(patRepo, userRepo)
+	oauthService := service.NewOAuthService(oauthClientRepo, oauthCodeRepo, oauthConsentRepo, userRepo, tokenRepo, jwtService, cfg)
+	apiClient := service.NewAPIClient(cfg, jwtService)
+	reauthenticator := service.NewReauthenticator(mail)
+	accountService := service.NewAccountService(userRepo, tokenRepo, patRepo, oauthClientRepo, oauthConsentRepo, dataExportRepo, apiClient, reauthenticator, cfg)
+	emailChangeService := service.NewEmailChangeService(userRepo, tokenRepo, emailChangeRepo, mail, reauthenticator, cfg)
+	maintenanceService := service.NewMaintenanceService(tokenRepo, oauthCodeRepo, cfg)
+
+	// Background jobs
+	jobs := scheduler.New()
+	jobs.Register(scheduler.Job{Name: "token_cleanup", Interval: cfg.Scheduler.TokenCleanupInterval, Run: maintenanceService.CleanupTokens})
+	jobs.Register(scheduler.Job{Name: "login_attempt_repair", Interval: cfg.Scheduler.LoginKeyInterval, Run: maintenanceService.RepairLoginAttempts})
+	jobs.Register(scheduler.Job{Name: "audit_retention", Interval: cfg.Audit.CleanupInterval, Run: auditService.CleanupExpired})
+	jobs.Register(scheduler.Job{Name: "account_deletions", Interval: cfg.Account.JobInterval, Run: func(ctx context.Context) (int64, error) {
+		n, err := accountService.ProcessDueDeletions(ctx)
+		return int64(n), err
+	}})
+	jobs.Register(scheduler.Job{Name: "data_exports", Interval: cfg.Account.JobInterval, Timeout: 15 * time.Minute, Run: func(ctx context.Context) (int64, error) {
+		n, err := accountService.ProcessPendingExports(ctx)
+		return int64(n), err
+	}})
+	jobs.Register(scheduler.Job{Name: "data_export_cleanup", Interval: cfg.Account.JobInterval, Run: func(ctx context.Context) (int64, error) {
+		n, err := accountService.CleanupExpiredExports(ctx)
+		return int64(n), err
+	}})
 
 	// Handlers
-	authHandler := handler.NewAuthHandler(authService)
+	authHandler := handler.NewAuthHandler(authService, patService, roleService, cfg)
+	userHandler := handler.NewUserHandler(userService)
+	patHandler := handler.NewPersonalAccessTokenHandler(patService)
+	oauthHandler := handler.NewOAuthHandler(oauthService)
+	accountHandler := handler.NewAccountHandler(accountService)
+	emailChangeHandler := handler.NewEmailChangeHandler(emailChangeService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	magicLinkHandler := handler.NewMagicLinkHandler(authService, cfg)
+	inviteHandler := handler.NewInviteHandler(inviteService, registrationPolicy)
+	roleHandler := handler.NewRoleHandler(roleService)
+	jobHandler := handler.NewJobHandler(jobs)
+	impersonationHandler := handler.NewImpersonationHandler(impersonationService)
+	sessionHandler := handler.NewSessionHandler(authService, cfg)
+	deviceHandler := handler.NewDeviceHandler(deviceService)
 
 	// Health check endpoint
 	r.GET("/health", handler.HealthCheck)
@@ -112,24 +216,115 @@ func main() {
 	// Swagger documentation
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	// Rate limiters
+	authLimit := middleware.RateLimit("auth", cfg.RateLimit.Auth, middleware.KeyByIP)
+	oauthLimit := middleware.RateLimit("oauth", cfg.RateLimit.OAuth, middleware.KeyByIP)
+	accountLimit := middleware.RateLimit("account", cfg.RateLimit.Account, middleware.KeyByUser)
+	tokenLimit := middleware.RateLimit("token", cfg.RateLimit.Token, middleware.KeyByToken)
+	publicLimit := middleware.RateLimit("public", cfg.RateLimit.Public, middleware.KeyByIP)
+
 	// API routes
 	api := r.Group("/api")
 	{
 		// Auth routes (public)
 		auth := api.Group("/auth")
 		{
-			auth.POST("/register", authHandler.Register)
-			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken)
-			auth.POST("/validate", authHandler.ValidateToken) // For API service
+			auth.POST("/register", authLimit, authHandler.Register)
+			auth.GET("/registration", publicLimit, inviteHandler.RegistrationPolicy)
+			auth.POST("/login", authLimit, authHandler.Login)
+			auth.POST("/refresh", authLimit, authHandler.RefreshToken)
+			auth.POST("/password-strength", authLimit, authHandler.PasswordStrength)
+			auth.POST("/confirm-email", authLimit, emailChangeHandler.ConfirmEmail)
+			auth.POST("/magic-link", authLimit, magicLinkHandler.RequestLink)
+			auth.POST("/magic-link/confirm", authLimit, magicLinkHandler.ConfirmLink)
+			auth.POST("/login-alerts/report", authLimit, deviceHandler.ReportLogin)
+			auth.POST("/validate", middleware.ServiceAuthMiddleware(jwtService, middleware.ScopeTokensValidate),
+				authHandler.ValidateToken) // Internal services only
+
+			// Cookie sessions for browser clients; the refresh token is only sent to these paths
+			session := auth.Group("/session")
+			{
+				session.POST("/refresh", authLimit, middleware.RequireCSRF(), sessionHandler.Refresh)
+				session.POST("/logout", authLimit, middleware.RequireCSRF(), sessionHandler.Logout)
+			}
 
 			// Protected routes (require authentication)
 			protected := auth.Group("")
-			protected.Use(middleware.AuthMiddleware(jwtService))
+			protected.Use(middleware.AuthMiddleware(jwtService), accountLimit, tokenLimit)
 			{
+				// Staff acting as a user cannot take over or remove the account
+				notImpersonating := middleware.BlockImpersonation()
+
 				protected.GET("/me", authHandler.GetMe)
+				protected.PATCH("/me", userHandler.UpdateMe)
+				protected.GET("/me/activity", auditHandler.RecentActivity)
+
+				// Account deletion and personal data export
+				protected.POST("/me/deletion", notImpersonating, accountHandler.RequestDeletion)
+				protected.DELETE("/me/deletion", accountHandler.CancelDeletion)
+				protected.GET("/me/exports", accountHandler.ListExports)
+				protected.POST("/me/exports", notImpersonating, accountHandler.RequestExport)
+				protected.GET("/me/exports/:id", accountHandler.GetExport)
+				protected.GET("/me/exports/:id/download", notImpersonating, accountHandler.DownloadExport)
 				protected.POST("/logout", authHandler.Logout)
-				protected.POST("/change-password", authHandler.ChangePassword)
+				protected.POST("/change-password", notImpersonating, authHandler.ChangePassword)
+				protected.POST("/change-email", notImpersonating, emailChangeHandler.ChangeEmail)
+
+				// Personal access tokens can only be managed with a session token
+				protected.GET("/tokens", patHandler.List)
+				protected.POST("/tokens", notImpersonating, patHandler.Create)
+				protected.DELETE("/tokens/:id", patHandler.Revoke)
+			}
+		}
+
+		// Admin routes
+		admin := api.Group("/admin")
+		admin.Use(middleware.AuthMiddleware(jwtService), accountLimit, tokenLimit)
+		{
+			readAudit := middleware.RequirePermission(models.PermissionAuditRead)
+			admin.GET("/auth-events", readAudit, auditHandler.ListEvents)
+			admin.GET("/impersonations", readAudit, impersonationHandler.ListSessions)
+
+			manageInvites := middleware.RequirePermission(models.PermissionInvitesManage)
+			admin.GET("/invites", manageInvites, inviteHandler.List)
+			admin.POST("/invites", manageInvites, inviteHandler.Create)
+			admin.DELETE("/invites/:id", manageInvites, inviteHandler.Revoke)
+
+			manageRoles := middleware.RequirePermission(models.PermissionUsersManageRoles)
+			admin.GET("/roles", manageRoles, roleHandler.ListRoles)
+			admin.PUT("/users/:id/role", manageRoles, roleHandler.AssignRole)
+
+			admin.POST("/users/:id/impersonate", middleware.RequirePermission(models.PermissionUsersImpersonate), impersonationHandler.Impersonate)
+
+			admin.GET("/jobs", middleware.RequirePermission(models.PermissionJobsRead), jobHandler.ListJobs)
+		}
+
+		// Public user profiles
+		users := api.Group("/users")
+		{
+			users.GET("/:username", publicLimit, userHandler.GetProfile)
+			users.POST("/lookup", middleware.ServiceAuthMiddleware(jwtService, middleware.ScopeUsersRead),
+				userHandler.LookupProfiles) // Internal services only
+		}
+
+		// OAuth2 authorization server
+		oauth := api.Group("/oauth")
+		{
+			// Client-authenticated endpoints (HTTP Basic or form credentials)
+			oauth.POST("/token", oauthLimit, oauthHandler.Token)
+			oauth.POST("/introspect", oauthLimit, oauthHandler.Introspect)
+			oauth.POST("/revoke", oauthLimit, oauthHandler.Revoke)
+
+			// User-facing endpoints (require a first-party session)
+			protected := oauth.Group("")
+			protected.Use(middleware.AuthMiddleware(jwtService), accountLimit, tokenLimit)
+			{
+				notImpersonating := middleware.BlockImpersonation()
+				protected.GET("/authorize", oauthHandler.GetAuthorize)
+				protected.POST("/authorize", notImpersonating, oauthHandler.PostAuthorize)
+				protected.GET("/clients", oauthHandler.ListClients)
+				protected.POST("/clients", notImpersonating, oauthHandler.RegisterClient)
+				protected.DELETE("/clients/:client_id", oauthHandler.DeleteClient)
 			}
 		}
 	}
@@ -140,6 +335,13 @@ func main() {
 		Handler: r,
 	}
 
+	// Start background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if cfg.Scheduler.Enabled {
+		jobs.Start(jobsCtx)
+	}
+
 	// Start server in a goroutine
 	go func() {
 		log.Printf("Auth service starting on port %s", cfg.App.Port)
@@ -154,6 +356,8 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopJobs()
+	jobs.Wait(5 * time.Second)
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -165,3 +369,16 @@ func main() {
 
 	log.Println("Server exited")
 }
+
+// configurePasswordHashing applies the password hashing settings from the configuration
+func configurePasswordHashing(cfg *config.Config) error {
+	return util.ConfigurePasswordHashing(util.PasswordHashConfig{
+		Algorithm: cfg.Password.Algorithm,
+		Argon2: util.Argon2Params{
+			Memory:      uint32(cfg.Password.Argon2Memory),
+			Iterations:  uint32(cfg.Password.Argon2Iterations),
+			Parallelism: uint8(cfg.Password.Argon2Parallelism),
+		},
+		BcryptCost: cfg.Password.BcryptCost,
+	})
+}
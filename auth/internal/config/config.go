@@ -4,21 +4,37 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	App   AppConfig
-	DB    DBConfig
-	JWT   JWTConfig
-	Redis RedisConfig
+	App          AppConfig
+	DB           DBConfig
+	JWT          JWTConfig
+	Redis        RedisConfig
+	OAuth        OAuthConfig
+	RateLimit    RateLimitConfig
+	Account      AccountConfig
+	API          APIServiceConfig
+	Password     PasswordConfig
+	Mail         MailConfig
+	Audit        AuditConfig
+	Scheduler    SchedulerConfig
+	MagicLink    MagicLinkConfig
+	Registration RegistrationConfig
+	Session      SessionConfig
+	Devices      DeviceConfig
+	Bootstrap    BootstrapConfig
 }
 
 type AppConfig struct {
-	Env  string
-	Port string
+	Env            string
+	Port           string
+	FrontendURL    string   // Base URL for links in emails
+	TrustedProxies []string // Proxy CIDRs allowed to set X-Forwarded-For; empty trusts none
 }
 
 type DBConfig struct {
@@ -34,9 +50,114 @@ type DBConfig struct {
 }
 
 type JWTConfig struct {
-	Secret        string
-	AccessExpiry  time.Duration
-	RefreshExpiry time.Duration
+	Secret              string
+	AccessExpiry        time.Duration
+	RefreshExpiry       time.Duration
+	ImpersonationExpiry time.Duration // Lifetime of access tokens issued to staff acting as another user
+}
+
+type OAuthConfig struct {
+	CodeExpiry time.Duration
+}
+
+// RateLimitConfig holds per-route-group rate limits. A zero limit disables the rule.
+type RateLimitConfig struct {
+	Auth    RateLimitRule // Register, login and refresh, per IP
+	OAuth   RateLimitRule // OAuth token, introspection and revocation endpoints, per IP
+	Account RateLimitRule // Authenticated account endpoints, per user
+	Public  RateLimitRule // Public profile lookups, per IP
+	Token   RateLimitRule // Authenticated account endpoints, per access token or PAT
+}
+
+// RateLimitRule allows Limit requests per sliding Window
+type RateLimitRule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// AccountConfig holds account deletion and data export settings
+type AccountConfig struct {
+	DeletionGracePeriod time.Duration // Time between a deletion request and the actual deletion
+	ExportExpiry        time.Duration // How long an export archive can be downloaded
+	JobInterval         time.Duration // How often pending deletions and exports are processed
+	EmailChangeExpiry   time.Duration // How long an email change confirmation link is valid
+}
+
+// RegistrationConfig controls who may create an account
+type RegistrationConfig struct {
+	Mode           string   // open, invite, domain or closed
+	AllowedDomains []string // Email domains that may register in domain mode
+	DefaultRole    string   // Role given to new accounts
+}
+
+// MagicLinkConfig holds passwordless email login settings
+type MagicLinkConfig struct {
+	Enabled      bool
+	Expiry       time.Duration // How long an emailed login link is valid
+	AutoRegister bool          // Create an account when an unknown address confirms a link
+	SameBrowser  bool          // Only accept a link in the browser that requested it
+}
+
+// AuditConfig holds security audit log settings
+type AuditConfig struct {
+	Retention       time.Duration // How long authentication events are kept
+	CleanupInterval time.Duration // How often expired events are deleted
+}
+
+// SchedulerConfig holds background maintenance job settings
+type SchedulerConfig struct {
+	Enabled               bool          // Run background jobs in this instance
+	TokenCleanupInterval  time.Duration // How often expired and revoked tokens are deleted
+	RevokedTokenRetention time.Duration // How long revoked refresh tokens are kept for reuse detection
+	LoginKeyInterval      time.Duration // How often login attempt counters without an expiry are repaired
+}
+
+// SessionConfig holds cookie session settings for browser clients
+type SessionConfig struct {
+	CookiesEnabled bool   // Allow clients to receive tokens in httpOnly cookies instead of the response body
+	CookieDomain   string // Empty for host-only cookies
+	CookieSecure   bool
+	CookieSameSite string // lax, strict or none
+}
+
+// DeviceConfig holds new device login detection settings
+type DeviceConfig struct {
+	AlertsEnabled bool   // Email users when they sign in from a new device or network
+	GeoIPPath     string // IP-to-country CSV; empty disables country lookups
+}
+
+// BootstrapConfig describes an admin account created at startup if it does not exist
+type BootstrapConfig struct {
+	AdminEmail    string // Empty disables the bootstrap
+	AdminUsername string
+	AdminPassword string
+}
+
+// MailConfig holds outgoing email settings
+type MailConfig struct {
+	Driver       string // log (development) or smtp
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+// APIServiceConfig holds settings for calling the api service
+type APIServiceConfig struct {
+	ServiceURL     string
+	RequestTimeout time.Duration
+}
+
+// PasswordConfig holds password hashing and new password policy settings
+type PasswordConfig struct {
+	Algorithm         string // argon2id or bcrypt
+	BcryptCost        int
+	Argon2Memory      int // KiB
+	Argon2Iterations  int
+	Argon2Parallelism int
+	BreachFilterPath  string // Bloom filter built by cmd/breachdb; empty disables the check
+	MinStrength       int    // Minimum strength score (0-4) for new passwords
 }
 
 type RedisConfig struct {
@@ -55,8 +176,10 @@ func Load() (*Config, error) {
 
 	cfg := &Config{
 		App: AppConfig{
-			Env:  getEnv("APP_ENV", "dev"),
-			Port: getEnv("APP_PORT", "8082"),
+			Env:            getEnv("APP_ENV", "dev"),
+			Port:           getEnv("APP_PORT", "8082"),
+			FrontendURL:    getEnv("FRONTEND_URL", "http://localhost:3000"),
+			TrustedProxies: getEnvAsList("TRUSTED_PROXIES"),
 		},
 		DB: DBConfig{
 			Host:            getEnv("DB_HOST", "localhost"),
@@ -73,6 +196,8 @@ func Load() (*Config, error) {
 			Secret:        getEnv("JWT_SECRET", ""),
 			AccessExpiry:  getEnvAsDuration("JWT_ACCESS_EXPIRY", 15*time.Minute),
 			RefreshExpiry: getEnvAsDuration("JWT_REFRESH_EXPIRY", 168*time.Hour), // 7 days
+
+			ImpersonationExpiry: getEnvAsDuration("JWT_IMPERSONATION_EXPIRY", 15*time.Minute),
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -80,6 +205,84 @@ func Load() (*Config, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
+		OAuth: OAuthConfig{
+			CodeExpiry: getEnvAsDuration("OAUTH_CODE_EXPIRY", 10*time.Minute),
+		},
+		RateLimit: RateLimitConfig{
+			Auth:    getEnvAsRateLimit("RATE_LIMIT_AUTH", RateLimitRule{Limit: 20, Window: time.Minute}),
+			OAuth:   getEnvAsRateLimit("RATE_LIMIT_OAUTH", RateLimitRule{Limit: 60, Window: time.Minute}),
+			Account: getEnvAsRateLimit("RATE_LIMIT_ACCOUNT", RateLimitRule{Limit: 60, Window: time.Minute}),
+			Public:  getEnvAsRateLimit("RATE_LIMIT_PUBLIC", RateLimitRule{Limit: 120, Window: time.Minute}),
+			Token:   getEnvAsRateLimit("RATE_LIMIT_TOKEN", RateLimitRule{Limit: 30, Window: time.Minute}),
+		},
+
+		Account: AccountConfig{
+			DeletionGracePeriod: getEnvAsDuration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+			ExportExpiry:        getEnvAsDuration("DATA_EXPORT_EXPIRY", 7*24*time.Hour),
+			JobInterval:         getEnvAsDuration("ACCOUNT_JOB_INTERVAL", time.Minute),
+			EmailChangeExpiry:   getEnvAsDuration("EMAIL_CHANGE_EXPIRY", 24*time.Hour),
+		},
+		Password: PasswordConfig{
+			Algorithm:         getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:        getEnvAsInt("PASSWORD_BCRYPT_COST", 12),
+			Argon2Memory:      getEnvAsInt("PASSWORD_ARGON2_MEMORY", 64*1024),
+			Argon2Iterations:  getEnvAsInt("PASSWORD_ARGON2_ITERATIONS", 3),
+			Argon2Parallelism: getEnvAsInt("PASSWORD_ARGON2_PARALLELISM", 2),
+			BreachFilterPath:  getEnv("PASSWORD_BREACH_FILTER", ""),
+			MinStrength:       getEnvAsInt("PASSWORD_MIN_STRENGTH", 2),
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "Inkstack <no-reply@inkstack.io>"),
+			SMTPHost:     getEnv("SMTP_HOST", ""),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		},
+		MagicLink: MagicLinkConfig{
+			Enabled:      getEnvAsBool("MAGIC_LINK_ENABLED", true),
+			Expiry:       getEnvAsDuration("MAGIC_LINK_EXPIRY", 15*time.Minute),
+			AutoRegister: getEnvAsBool("MAGIC_LINK_AUTO_REGISTER", true),
+			SameBrowser:  getEnvAsBool("MAGIC_LINK_SAME_BROWSER", true),
+		},
+		Registration: RegistrationConfig{
+			Mode:           getEnv("REGISTRATION_MODE", "open"),
+			AllowedDomains: getEnvAsList("REGISTRATION_ALLOWED_DOMAINS"),
+			DefaultRole:    getEnv("REGISTRATION_DEFAULT_ROLE", "author"),
+		},
+		Audit: AuditConfig{
+			Retention:       getEnvAsDuration("AUDIT_RETENTION", 90*24*time.Hour),
+			CleanupInterval: getEnvAsDuration("AUDIT_CLEANUP_INTERVAL", time.Hour),
+		},
+		Scheduler: SchedulerConfig{
+			Enabled:               getEnvAsBool("SCHEDULER_ENABLED", true),
+			TokenCleanupInterval:  getEnvAsDuration("TOKEN_CLEANUP_INTERVAL", time.Hour),
+			RevokedTokenRetention: getEnvAsDuration("REVOKED_TOKEN_RETENTION", 168*time.Hour),
+			LoginKeyInterval:      getEnvAsDuration("LOGIN_KEY_CLEANUP_INTERVAL", time.Hour),
+		},
+		Session: SessionConfig{
+			CookiesEnabled: getEnvAsBool("SESSION_COOKIES_ENABLED", false),
+			CookieDomain:   getEnv("SESSION_COOKIE_DOMAIN", ""),
+			CookieSecure:   getEnvAsBool("SESSION_COOKIE_SECURE", true),
+			CookieSameSite: getEnv("SESSION_COOKIE_SAMESITE", "lax"),
+		},
+		Devices: DeviceConfig{
+			AlertsEnabled: getEnvAsBool("NEW_DEVICE_ALERTS_ENABLED", true),
+			GeoIPPath:     getEnv("GEOIP_DATABASE_PATH", ""),
+		},
+		Bootstrap: BootstrapConfig{
+			AdminEmail:    getEnv("BOOTSTRAP_ADMIN_EMAIL", ""),
+			AdminUsername: getEnv("BOOTSTRAP_ADMIN_USERNAME", "admin"),
+			AdminPassword: getEnv("BOOTSTRAP_ADMIN_PASSWORD", ""),
+		},
+		API: APIServiceConfig{
+			ServiceURL:     getEnv("API_SERVICE_URL", "http://localhost:8081"),
+			RequestTimeout: getEnvAsDuration("API_SERVICE_TIMEOUT", 30*time.Second),
+		},
+	}
+
+	if !getEnvAsBool("RATE_LIMIT_ENABLED", true) {
+		cfg.RateLimit = RateLimitConfig{}
 	}
 
 	// Validate critical configuration
@@ -89,6 +292,33 @@ func Load() (*Config, error) {
 	if len(cfg.JWT.Secret) < 32 {
 		return nil, fmt.Errorf("JWT_SECRET must be at least 32 characters long")
 	}
+	if cfg.Bootstrap.AdminEmail != "" && cfg.Bootstrap.AdminPassword == "" {
+		return nil, fmt.Errorf("BOOTSTRAP_ADMIN_PASSWORD is required when BOOTSTRAP_ADMIN_EMAIL is set")
+	}
+	switch cfg.Session.CookieSameSite {
+	case "lax", "strict":
+	case "none":
+		if !cfg.Session.CookieSecure {
+			return nil, fmt.Errorf("SESSION_COOKIE_SAMESITE=none requires SESSION_COOKIE_SECURE=true")
+		}
+	default:
+		return nil, fmt.Errorf("SESSION_COOKIE_SAMESITE must be lax, strict or none")
+	}
+
+	// Background job intervals drive tickers, which can't run at a zero or negative interval
+	for _, job := range []struct {
+		key      string
+		interval time.Duration
+	}{
+		{"TOKEN_CLEANUP_INTERVAL", cfg.Scheduler.TokenCleanupInterval},
+		{"LOGIN_KEY_CLEANUP_INTERVAL", cfg.Scheduler.LoginKeyInterval},
+		{"AUDIT_CLEANUP_INTERVAL", cfg.Audit.CleanupInterval},
+		{"ACCOUNT_JOB_INTERVAL", cfg.Account.JobInterval},
+	} {
+		if job.interval <= 0 {
+			return nil, fmt.Errorf("%s must be a positive duration, got %s", job.key, job.interval)
+		}
+	}
 
 	return cfg, nil
 }
@@ -124,3 +354,42 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsList parses a comma-separated list, ignoring empty entries
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, ""), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvAsRateLimit parses a rule in the form "<limit>/<window>", e.g. "100/1m"
+func getEnvAsRateLimit(key string, defaultValue RateLimitRule) RateLimitRule {
+	parts := strings.SplitN(getEnv(key, ""), "/", 2)
+	if len(parts) != 2 {
+		return defaultValue
+	}
+
+	limit, err := strconv.Atoi(parts[0])
+	if err != nil || limit < 0 {
+		return defaultValue
+	}
+
+	window, err := time.ParseDuration(parts[1])
+	if err != nil || window <= 0 {
+		return defaultValue
+	}
+
+	return RateLimitRule{Limit: limit, Window: window}
+}
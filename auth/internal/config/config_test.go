@@ -0,0 +1,34 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadRejectsNonPositiveJobIntervals(t *testing.T) {
+	for _, key := range []string{"TOKEN_CLEANUP_INTERVAL", "LOGIN_KEY_CLEANUP_INTERVAL", "AUDIT_CLEANUP_INTERVAL", "ACCOUNT_JOB_INTERVAL"} {
+		for _, value := range []string{"0s", "-1m"} {
+			t.Run(key+"="+value, func(t *testing.T) {
+				t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+				t.Setenv(key, value)
+
+				_, err := Load()
+				if err == nil || !strings.Contains(err.Error(), key) {
+					t.Fatalf("Load() error = %v, want an error naming %s", err, key)
+				}
+			})
+		}
+	}
+}
+
+func TestLoadAcceptsDefaultJobIntervals(t *testing.T) {
+	t.Setenv("JWT_SECRET", strings.Repeat("s", 32))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Scheduler.TokenCleanupInterval <= 0 || cfg.Account.JobInterval <= 0 {
+		t.Errorf("default intervals must be positive, got %+v and %s", cfg.Scheduler, cfg.Account.JobInterval)
+	}
+}
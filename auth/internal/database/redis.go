@@ -12,6 +12,64 @@ import (
 
 var redisClient *redis.Client
 
+// loginAttemptsWindow is how long failed login attempts are counted
+const loginAttemptsWindow = 15 * time.Minute
+
+// releaseLockScript deletes a lock only if it is still held by the given owner
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// expireLockScript changes a lock's expiry only if it is still held by the given owner
+var expireLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// slidingWindowScript atomically records a hit in a sliding window log and reports
+// whether it is within the limit. Redis server time is used so replicas agree.
+// Returns {allowed, count, ms until the oldest hit leaves the window}.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local member = ARGV[3]
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, now .. '-' .. member)
+	redis.call('PEXPIRE', key, window)
+	count = count + 1
+	allowed = 1
+end
+
+local reset = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, count, reset}
+`)
+
+// RateLimitResult describes the outcome of a rate limit check
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+}
+
 // ConnectRedis establishes a connection to Redis
 func ConnectRedis(cfg *config.Config) error {
 	addr := fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port)
@@ -74,7 +132,7 @@ func IncrementLoginAttempts(ctx context.Context, identifier string) (int64, erro
 
 	// Set expiry on first attempt
 	if count == 1 {
-		redisClient.Expire(ctx, key, 15*time.Minute)
+		redisClient.Expire(ctx, key, loginAttemptsWindow)
 	}
 
 	return count, nil
@@ -95,3 +153,141 @@ func GetLoginAttempts(ctx context.Context, identifier string) (int, error) {
 	}
 	return count, err
 }
+
+// RepairLoginAttempts gives login attempt counters without an expiry a fresh window.
+// A counter loses its expiry when the EXPIRE after its first increment fails, which
+// would otherwise lock the account or IP out forever. Returns how many were repaired.
+func RepairLoginAttempts(ctx context.Context) (int64, error) {
+	var repaired int64
+	iter := redisClient.Scan(ctx, 0, "login_attempts:*", 500).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		ttl, err := redisClient.TTL(ctx, key).Result()
+		if err != nil {
+			return repaired, err
+		}
+		// -1 means the key exists without an expiry
+		if ttl != -1 {
+			continue
+		}
+		if err := redisClient.Expire(ctx, key, loginAttemptsWindow).Err(); err != nil {
+			return repaired, err
+		}
+		repaired++
+	}
+	return repaired, iter.Err()
+}
+
+// AcquireLock takes a named lock shared by all replicas for ttl.
+// Returns false if another owner already holds it.
+func AcquireLock(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	if redisClient == nil {
+		return false, fmt.Errorf("redis not initialized")
+	}
+	key := fmt.Sprintf("lock:%s", name)
+	return redisClient.SetNX(ctx, key, owner, ttl).Result()
+}
+
+// ReleaseLock releases a named lock if owner still holds it
+func ReleaseLock(ctx context.Context, name, owner string) error {
+	key := fmt.Sprintf("lock:%s", name)
+	return releaseLockScript.Run(ctx, redisClient, []string{key}, owner).Err()
+}
+
+// ExpireLockAfter keeps a named lock held by owner for ttl from now
+func ExpireLockAfter(ctx context.Context, name, owner string, ttl time.Duration) error {
+	key := fmt.Sprintf("lock:%s", name)
+	return expireLockScript.Run(ctx, redisClient, []string{key}, owner, ttl.Milliseconds()).Err()
+}
+
+// StoreMagicLink stores a pending magic link login under its token hash until it expires
+func StoreMagicLink(ctx context.Context, tokenHash, payload string, ttl time.Duration) error {
+	key := fmt.Sprintf("magic_link:%s", tokenHash)
+	return redisClient.Set(ctx, key, payload, ttl).Err()
+}
+
+// ConsumeMagicLink atomically fetches and deletes a magic link so it can only be used once.
+// Returns an empty payload if the link does not exist or has expired.
+func ConsumeMagicLink(ctx context.Context, tokenHash string) (string, error) {
+	key := fmt.Sprintf("magic_link:%s", tokenHash)
+	payload, err := redisClient.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return payload, err
+}
+
+// StoreReauthCode stores the hash of the confirmation code emailed for a sensitive
+// action, replacing any earlier code for the same user and action
+func StoreReauthCode(ctx context.Context, userID uint, action, codeHash string, ttl time.Duration) error {
+	key := fmt.Sprintf("reauth:%s:%d", action, userID)
+	return redisClient.Set(ctx, key, codeHash, ttl).Err()
+}
+
+// ConsumeReauthCode atomically fetches and deletes the confirmation code hash for a
+// user and action, so each code allows a single attempt. Returns an empty hash if
+// there is none or it has expired.
+func ConsumeReauthCode(ctx context.Context, userID uint, action string) (string, error) {
+	key := fmt.Sprintf("reauth:%s:%d", action, userID)
+	codeHash, err := redisClient.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return codeHash, err
+}
+
+// StoreLoginReport stores the session behind a new device alert under the hash of the
+// "this wasn't me" token until the session could no longer be refreshed anyway
+func StoreLoginReport(ctx context.Context, tokenHash, payload string, ttl time.Duration) error {
+	key := fmt.Sprintf("login_report:%s", tokenHash)
+	return redisClient.Set(ctx, key, payload, ttl).Err()
+}
+
+// ConsumeLoginReport atomically fetches and deletes a login report token so it can only be used once.
+// Returns an empty payload if the token does not exist or has expired.
+func ConsumeLoginReport(ctx context.Context, tokenHash string) (string, error) {
+	key := fmt.Sprintf("login_report:%s", tokenHash)
+	payload, err := redisClient.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return payload, err
+}
+
+// AllowRequest records a request against a sliding window rate limit shared by all replicas
+func AllowRequest(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitResult, error) {
+	if redisClient == nil {
+		return nil, fmt.Errorf("redis not initialized")
+	}
+
+	member := fmt.Sprintf("%d", time.Now().UnixNano())
+	values, err := slidingWindowScript.Run(ctx, redisClient, []string{"ratelimit:" + key},
+		window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := limit - int(values[1])
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &RateLimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  remaining,
+		ResetAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
+
+// PublishEvent appends an event to a Redis stream for other services to consume
+func PublishEvent(ctx context.Context, stream string, values map[string]interface{}) error {
+	if redisClient == nil {
+		return fmt.Errorf("redis not initialized")
+	}
+
+	return redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		Values: values,
+	}).Err()
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler handles account deletion and data export HTTP requests
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// DeleteAccountRequest represents account deletion request body. Accounts without a
+// password send the emailed confirmation code instead.
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// RequestDeletion handles POST /api/auth/me/deletion
+// @Summary Schedule account deletion
+// @Description Schedule the authenticated user's account and all their content for permanent deletion after a grace period. Accounts without a password get 202 and an emailed code, then repeat the request with the code.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeleteAccountRequest true "Current password, or confirmation code"
+// @Success 200 {object} map[string]interface{}
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/auth/me/deletion [post]
+func (h *AccountHandler) RequestDeletion(c *gin.Context) {
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	user, err := h.accountService.RequestDeletion(c.Request.Context(), userID.(uint), req.Password, req.Code)
+	if err != nil {
+		if errors.Is(err, service.ErrConfirmationCodeSent) {
+			respondConfirmationCodeSent(c)
+			return
+		}
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	util.RespondSuccess(c, "Account deletion scheduled", gin.H{
+		"deletion_scheduled_at": user.DeletionScheduledAt,
+	})
+}
+
+// CancelDeletion handles DELETE /api/auth/me/deletion
+// @Summary Cancel account deletion
+// @Description Cancel a pending account deletion during the grace period
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/auth/me/deletion [delete]
+func (h *AccountHandler) CancelDeletion(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.accountService.CancelDeletion(c.Request.Context(), userID.(uint)); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	util.RespondSuccess(c, "Account deletion cancelled", nil)
+}
+
+// RequestExport handles POST /api/auth/me/exports
+// @Summary Request a data export
+// @Description Queue an archive of the authenticated user's account, sessions, posts and comments
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} models.DataExport
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/auth/me/exports [post]
+func (h *AccountHandler) RequestExport(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	export, err := h.accountService.RequestExport(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"export": export,
+	})
+}
+
+// ListExports handles GET /api/auth/me/exports
+// @Summary List data exports
+// @Description List the authenticated user's data exports
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /api/auth/me/exports [get]
+func (h *AccountHandler) ListExports(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	exports, err := h.accountService.ListExports(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.RespondInternalError(c, "Failed to list data exports")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"exports": exports,
+	})
+}
+
+// GetExport handles GET /api/auth/me/exports/:id
+// @Summary Get a data export
+// @Description Get the status of one of the authenticated user's data exports
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Export ID"
+// @Success 200 {object} models.DataExport
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/auth/me/exports/{id} [get]
+func (h *AccountHandler) GetExport(c *gin.Context) {
+	export, ok := h.findExport(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"export": export,
+	})
+}
+
+// DownloadExport handles GET /api/auth/me/exports/:id/download
+// @Summary Download a data export
+// @Description Download a ready data export archive
+// @Tags account
+// @Produce application/zip
+// @Security BearerAuth
+// @Param id path int true "Export ID"
+// @Success 200 {file} file
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/auth/me/exports/{id}/download [get]
+func (h *AccountHandler) DownloadExport(c *gin.Context) {
+	export, ok := h.findExport(c)
+	if !ok {
+		return
+	}
+
+	archive, err := h.accountService.GetExportArchive(c.Request.Context(), export)
+	if err != nil {
+		util.RespondNotFound(c, "Data export archive")
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="inkstack-export-%d.zip"`, export.ID))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// findExport loads the export named in the path for the authenticated user, responding on failure
+func (h *AccountHandler) findExport(c *gin.Context) (*models.DataExport, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "Invalid export ID")
+		return nil, false
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return nil, false
+	}
+
+	export, err := h.accountService.GetExport(c.Request.Context(), userID.(uint), uint(id))
+	if err != nil {
+		util.RespondNotFound(c, "Data export")
+		return nil, false
+	}
+
+	return export, true
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"fmt"
+	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler handles security audit log HTTP requests
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+// NewAuditHandler creates a new audit handler
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+// ListEvents handles GET /api/admin/auth-events
+// @Summary Query the audit log
+// @Description Search authentication events across all users (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int false "Filter by user ID"
+// @Param type query string false "Filter by event type (register, login_success, login_failure, token_refresh, logout, password_change, token_reuse, lockout, role_change, impersonation)"
+// @Param ip query string false "Filter by IP address"
+// @Param identifier query string false "Filter by submitted email or username"
+// @Param since query string false "Only events at or after this RFC 3339 time"
+// @Param until query string false "Only events before this RFC 3339 time"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/admin/auth-events [get]
+func (h *AuditHandler) ListEvents(c *gin.Context) {
+	page, pageSize := auditPage(c)
+	filter := repository.AuthEventFilter{
+		EventType:  c.Query("type"),
+		IPAddress:  c.Query("ip"),
+		Identifier: c.Query("identifier"),
+		Limit:      pageSize,
+		Offset:     (page - 1) * pageSize,
+	}
+
+	if value := c.Query("user_id"); value != "" {
+		userID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			util.RespondBadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = uint(userID)
+	}
+
+	var err error
+	if filter.Since, err = timeQuery(c, "since"); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+	if filter.Until, err = timeQuery(c, "until"); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	events, total, err := h.auditService.ListEvents(filter)
+	if err != nil {
+		util.RespondInternalError(c, "Failed to retrieve auth events")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"events":     events,
+		"pagination": util.CalculatePagination(page, pageSize, total),
+	})
+}
+
+// RecentActivity handles GET /api/auth/me/activity
+// @Summary Recent account activity
+// @Description List the authenticated user's recent sign-ins, sign-outs and other security events
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/auth/me/activity [get]
+func (h *AuditHandler) RecentActivity(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := auditPage(c)
+	events, total, err := h.auditService.RecentActivity(userID.(uint), pageSize, (page-1)*pageSize)
+	if err != nil {
+		util.RespondInternalError(c, "Failed to retrieve account activity")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"events":     events,
+		"pagination": util.CalculatePagination(page, pageSize, total),
+	})
+}
+
+// timeQuery parses an optional RFC 3339 query parameter
+func timeQuery(c *gin.Context, param string) (time.Time, error) {
+	value := c.Query(param)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s, expected an RFC 3339 time", param)
+	}
+	return t, nil
+}
+
+// auditPage reads the page and page_size query parameters, clamped to sane bounds
+func auditPage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 50
+	}
+	return page, pageSize
+}
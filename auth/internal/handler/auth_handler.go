@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"inkstack-auth/internal/config"
 	"inkstack-auth/internal/service"
 	"inkstack-auth/internal/util"
 
@@ -10,20 +11,27 @@ import (
 // AuthHandler handles authentication HTTP requests
 type AuthHandler struct {
 	authService *service.AuthService
+	patService  *service.PersonalAccessTokenService
+	roleService *service.RoleService
+	config      *config.Config
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+func NewAuthHandler(authService *service.AuthService, patService *service.PersonalAccessTokenService, roleService *service.RoleService, cfg *config.Config) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
+		patService:  patService,
+		roleService: roleService,
+		config:      cfg,
 	}
 }
 
 // RegisterRequest represents registration request body
 type RegisterRequest struct {
-	Email    string `json:"email" binding:"required"`
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Email      string `json:"email" binding:"required"`
+	Username   string `json:"username" binding:"required"`
+	Password   string `json:"password" binding:"required"`
+	InviteCode string `json:"invite_code"`
 }
 
 // LoginRequest represents login request body
@@ -44,10 +52,17 @@ type LogoutRequest struct {
 
 // ChangePasswordRequest represents change password request body
 type ChangePasswordRequest struct {
-	OldPassword string `json:"old_password" binding:"required"`
+	OldPassword string `json:"old_password"` // Empty for passwordless accounts setting their first password
 	NewPassword string `json:"new_password" binding:"required"`
 }
 
+// PasswordStrengthRequest represents password strength check request body
+type PasswordStrengthRequest struct {
+	Password string `json:"password" binding:"required"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
 // AuthResponse represents authentication response
 type AuthResponse struct {
 	User         interface{} `json:"user"`
@@ -62,6 +77,7 @@ type AuthResponse struct {
 // @Accept json
 // @Produce json
 // @Param request body RegisterRequest true "Registration details"
+// @Param X-Session-Mode header string false "Set to cookie to receive tokens as httpOnly cookies (CookieSessionResponse)"
 // @Success 201 {object} AuthResponse
 // @Failure 400 {object} map[string]interface{}
 // @Router /api/auth/register [post]
@@ -73,9 +89,12 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	user, tokens, err := h.authService.Register(c.Request.Context(), service.RegisterInput{
-		Email:    req.Email,
-		Username: req.Username,
-		Password: req.Password,
+		Email:      req.Email,
+		Username:   req.Username,
+		Password:   req.Password,
+		InviteCode: req.InviteCode,
+		IPAddress:  c.ClientIP(),
+		UserAgent:  c.Request.UserAgent(),
 	})
 
 	if err != nil {
@@ -83,11 +102,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	util.RespondCreated(c, AuthResponse{
-		User:         user.ToPublic(),
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-	})
+	response, err := sessionResponse(c, h.config, user, tokens)
+	if err != nil {
+		util.RespondInternalError(c, "Failed to start session")
+		return
+	}
+
+	util.RespondCreated(c, response)
 }
 
 // Login handles POST /api/auth/login
@@ -97,6 +118,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body LoginRequest true "Login credentials"
+// @Param X-Session-Mode header string false "Set to cookie to receive tokens as httpOnly cookies (CookieSessionResponse)"
 // @Success 200 {object} AuthResponse
 // @Failure 401 {object} map[string]interface{}
 // @Router /api/auth/login [post]
@@ -119,11 +141,13 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	util.RespondSuccess(c, "Login successful", AuthResponse{
-		User:         user.ToPublic(),
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-	})
+	response, err := sessionResponse(c, h.config, user, tokens)
+	if err != nil {
+		util.RespondInternalError(c, "Failed to start session")
+		return
+	}
+
+	util.RespondSuccess(c, "Login successful", response)
 }
 
 // RefreshToken handles POST /api/auth/refresh
@@ -143,7 +167,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	accessToken, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken)
+	accessToken, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken, clientInfo(c))
 	if err != nil {
 		util.RespondUnauthorized(c, err.Error())
 		return
@@ -172,13 +196,16 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	// Get access token from header
-	accessToken := c.GetHeader("Authorization")
-	if len(accessToken) > 7 && accessToken[:7] == "Bearer " {
-		accessToken = accessToken[7:]
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
 	}
 
-	if err := h.authService.Logout(c.Request.Context(), req.RefreshToken, accessToken); err != nil {
+	// Get access token from the header or session cookie (set by auth middleware)
+	accessToken := c.GetString("access_token")
+
+	if err := h.authService.Logout(c.Request.Context(), userID.(uint), req.RefreshToken, accessToken, clientInfo(c)); err != nil {
 		util.RespondBadRequest(c, err.Error())
 		return
 	}
@@ -204,20 +231,22 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	}
 
 	// Get full user info from token validation
-	accessToken := c.GetHeader("Authorization")
-	if len(accessToken) > 7 && accessToken[:7] == "Bearer " {
-		accessToken = accessToken[7:]
-	}
+	accessToken := c.GetString("access_token")
 
-	user, err := h.authService.ValidateToken(c.Request.Context(), accessToken)
+	user, claims, err := h.authService.ValidateToken(c.Request.Context(), accessToken)
 	if err != nil {
 		util.RespondUnauthorized(c, "Invalid token")
 		return
 	}
 
-	c.JSON(200, gin.H{
+	response := gin.H{
 		"user": user.ToPublic(),
-	})
+	}
+	// Lets clients show that a staff member is acting as this user
+	if claims.Actor != nil {
+		response["impersonator"] = claims.Actor
+	}
+	c.JSON(200, response)
 }
 
 // ChangePassword handles POST /api/auth/change-password
@@ -249,6 +278,7 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		userID.(uint),
 		req.OldPassword,
 		req.NewPassword,
+		clientInfo(c),
 	); err != nil {
 		util.RespondBadRequest(c, err.Error())
 		return
@@ -257,9 +287,29 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	util.RespondSuccess(c, "Password changed successfully", nil)
 }
 
+// PasswordStrength handles POST /api/auth/password-strength
+// @Summary Check password strength
+// @Description Estimate how guessable a candidate password is and whether it would be accepted
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body PasswordStrengthRequest true "Candidate password and the user's details"
+// @Success 200 {object} service.PasswordCheck
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/auth/password-strength [post]
+func (h *AuthHandler) PasswordStrength(c *gin.Context) {
+	var req PasswordStrengthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(200, h.authService.CheckPassword(req.Password, req.Username, req.Email))
+}
+
 // ValidateToken handles POST /api/auth/validate (for API service)
 // @Summary Validate JWT token
-// @Description Validate a JWT token and return user info (used by API service)
+// @Description Validate a JWT or personal access token and return user info (used by API service)
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -277,7 +327,31 @@ func (h *AuthHandler) ValidateToken(c *gin.Context) {
 		return
 	}
 
-	user, err := h.authService.ValidateToken(c.Request.Context(), req.Token)
+	// Personal access tokens are opaque, so they can only be checked here
+	if service.IsPersonalAccessToken(req.Token) {
+		identity, err := h.patService.Validate(c.Request.Context(), req.Token, c.ClientIP())
+		if err != nil {
+			c.JSON(200, gin.H{
+				"valid": false,
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(200, gin.H{
+			"valid":       true,
+			"token_type":  "personal_access_token",
+			"user_id":     identity.User.ID,
+			"email":       identity.User.Email,
+			"username":    identity.User.Username,
+			"role":        identity.User.Role,
+			"permissions": h.roleService.Permissions(identity.User.Role),
+			"scopes":      identity.Token.ScopeList(),
+		})
+		return
+	}
+
+	user, claims, err := h.authService.ValidateToken(c.Request.Context(), req.Token)
 	if err != nil {
 		c.JSON(200, gin.H{
 			"valid": false,
@@ -286,12 +360,21 @@ func (h *AuthHandler) ValidateToken(c *gin.Context) {
 		return
 	}
 
-	c.JSON(200, gin.H{
-		"valid":   true,
-		"user_id": user.ID,
-		"email":   user.Email,
-		"role":    user.Role,
-	})
+	response := gin.H{
+		"valid":       true,
+		"token_type":  "access_token",
+		"user_id":     user.ID,
+		"email":       user.Email,
+		"username":    user.Username,
+		"role":        user.Role,
+		"permissions": h.roleService.Permissions(user.Role),
+	}
+	if claims.Actor != nil {
+		response["impersonator_id"] = claims.Actor.UserID
+		response["impersonator_username"] = claims.Actor.Username
+		response["impersonation_session"] = claims.ID
+	}
+	c.JSON(200, response)
 }
 
 // HealthCheck handles GET /health
@@ -307,3 +390,11 @@ func HealthCheck(c *gin.Context) {
 		"service": "auth",
 	})
 }
+
+// clientInfo extracts the caller's IP address and user agent for audit logging
+func clientInfo(c *gin.Context) service.ClientInfo {
+	return service.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceHandler handles new device alert HTTP requests
+type DeviceHandler struct {
+	deviceService *service.DeviceService
+}
+
+// NewDeviceHandler creates a new device handler
+func NewDeviceHandler(deviceService *service.DeviceService) *DeviceHandler {
+	return &DeviceHandler{
+		deviceService: deviceService,
+	}
+}
+
+// ReportLoginRequest represents report login request body
+type ReportLoginRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ReportLogin handles POST /api/auth/login-alerts/report
+// @Summary Report a sign-in as not yours
+// @Description Sign out the session named in a new device alert email, using the token from its "this wasn't me" link. The link works once.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ReportLoginRequest true "Token from the alert email"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/auth/login-alerts/report [post]
+func (h *DeviceHandler) ReportLogin(c *gin.Context) {
+	var req ReportLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	if err := h.deviceService.ReportLogin(c.Request.Context(), req.Token, clientInfo(c)); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	util.RespondSuccess(c, "The session was signed out. Change your password to keep it from signing in again", nil)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailChangeHandler handles email address change HTTP requests
+type EmailChangeHandler struct {
+	emailChangeService *service.EmailChangeService
+}
+
+// NewEmailChangeHandler creates a new email change handler
+func NewEmailChangeHandler(emailChangeService *service.EmailChangeService) *EmailChangeHandler {
+	return &EmailChangeHandler{
+		emailChangeService: emailChangeService,
+	}
+}
+
+// ChangeEmailRequest represents change email request body. Accounts without a
+// password send the emailed confirmation code instead.
+type ChangeEmailRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+	NewEmail string `json:"new_email" binding:"required"`
+}
+
+// ConfirmEmailRequest represents confirm email request body
+type ConfirmEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ChangeEmail handles POST /api/auth/change-email
+// @Summary Request an email change
+// @Description Send a confirmation link to the new address and a notice to the current one. The email is only changed once the link is confirmed. Accounts without a password get 202 and an emailed code, then repeat the request with the code.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ChangeEmailRequest true "Current password or confirmation code, and new email"
+// @Success 200 {object} map[string]interface{}
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/auth/change-email [post]
+func (h *EmailChangeHandler) ChangeEmail(c *gin.Context) {
+	var req ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.emailChangeService.RequestChange(c.Request.Context(), userID.(uint), req.Password, req.Code, req.NewEmail); err != nil {
+		if errors.Is(err, service.ErrConfirmationCodeSent) {
+			respondConfirmationCodeSent(c)
+			return
+		}
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	util.RespondSuccess(c, "Confirmation email sent to the new address", nil)
+}
+
+// ConfirmEmail handles POST /api/auth/confirm-email
+// @Summary Confirm an email change
+// @Description Swap in the new email address using the token from the confirmation link. All sessions are signed out.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ConfirmEmailRequest true "Confirmation token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/auth/confirm-email [post]
+func (h *EmailChangeHandler) ConfirmEmail(c *gin.Context) {
+	var req ConfirmEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	user, err := h.emailChangeService.ConfirmChange(c.Request.Context(), req.Token)
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	util.RespondSuccess(c, "Email address changed", gin.H{
+		"user": user.ToPublic(),
+	})
+}
+
+// respondConfirmationCodeSent tells a client that a confirmation code was emailed and
+// the request should be repeated with it
+func respondConfirmationCodeSent(c *gin.Context) {
+	c.JSON(http.StatusAccepted, util.SuccessResponse{
+		Message: service.ErrConfirmationCodeSent.Error(),
+		Data: gin.H{
+			"confirmation_required": true,
+		},
+	})
+}
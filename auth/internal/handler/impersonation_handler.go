@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"fmt"
+	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationHandler handles impersonation HTTP requests
+type ImpersonationHandler struct {
+	impersonationService *service.ImpersonationService
+}
+
+// NewImpersonationHandler creates a new impersonation handler
+func NewImpersonationHandler(impersonationService *service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+	}
+}
+
+// ImpersonateRequest represents impersonate request body
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// Impersonate handles POST /api/admin/users/:id/impersonate
+// @Summary Impersonate a user
+// @Description Issue a short-lived access token to act as another user (admin only). The token carries an "act" claim naming the admin, cannot be refreshed, and cannot change the user's password or email or create tokens. Every session is recorded.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body ImpersonateRequest true "Reason, e.g. a support ticket reference"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/admin/users/{id}/impersonate [post]
+func (h *ImpersonationHandler) Impersonate(c *gin.Context) {
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid user ID")
+		return
+	}
+
+	var req ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	result, err := h.impersonationService.Start(c.Request.Context(), userID.(uint), uint(targetID), req.Reason, clientInfo(c))
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	c.JSON(201, gin.H{
+		"access_token": result.AccessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(time.Until(result.Session.ExpiresAt).Seconds()),
+		"user":         result.Target.ToPublic(),
+		"session":      result.Session,
+	})
+}
+
+// ListSessions handles GET /api/admin/impersonations
+// @Summary List impersonation sessions
+// @Description List every impersonation session, newest first (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param actor_id query int false "Filter by the admin who impersonated"
+// @Param target_id query int false "Filter by the impersonated user"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(50)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/admin/impersonations [get]
+func (h *ImpersonationHandler) ListSessions(c *gin.Context) {
+	page, pageSize := auditPage(c)
+	filter := repository.ImpersonationFilter{
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+	}
+
+	var err error
+	if filter.ActorID, err = idQuery(c, "actor_id"); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+	if filter.TargetID, err = idQuery(c, "target_id"); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	sessions, total, err := h.impersonationService.List(filter)
+	if err != nil {
+		util.RespondInternalError(c, "Failed to retrieve impersonation sessions")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"sessions":   sessions,
+		"pagination": util.CalculatePagination(page, pageSize, total),
+	})
+}
+
+// idQuery parses an optional numeric ID query parameter
+func idQuery(c *gin.Context, param string) (uint, error) {
+	value := c.Query(param)
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s", param)
+	}
+	return uint(id), nil
+}
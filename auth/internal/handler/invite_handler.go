@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InviteHandler handles registration policy and invite HTTP requests
+type InviteHandler struct {
+	inviteService *service.InviteService
+	registration  *service.RegistrationPolicy
+}
+
+// NewInviteHandler creates a new invite handler
+func NewInviteHandler(inviteService *service.InviteService, registration *service.RegistrationPolicy) *InviteHandler {
+	return &InviteHandler{
+		inviteService: inviteService,
+		registration:  registration,
+	}
+}
+
+// CreateInviteRequest represents create invite request body
+type CreateInviteRequest struct {
+	Email         string `json:"email"`
+	MaxUses       int    `json:"max_uses" binding:"omitempty,min=1,max=1000"`
+	ExpiresInDays int    `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+// CreateInviteResponse includes the plaintext invite code, shown only once
+type CreateInviteResponse struct {
+	Invite *models.Invite `json:"invite"`
+	Code   string         `json:"code"`
+}
+
+// RegistrationPolicy handles GET /api/auth/registration
+// @Summary Get the registration mode
+// @Description Tells clients whether signups are open, need an invite, are limited to certain email domains or are closed
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/auth/registration [get]
+func (h *InviteHandler) RegistrationPolicy(c *gin.Context) {
+	c.JSON(200, gin.H{
+		"mode": h.registration.Mode(),
+	})
+}
+
+// Create handles POST /api/admin/invites
+// @Summary Create an invite
+// @Description Issue an invite code (admin only). The code is only returned once.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateInviteRequest true "Invite details"
+// @Success 201 {object} CreateInviteResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/admin/invites [post]
+func (h *InviteHandler) Create(c *gin.Context) {
+	var req CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	invite, code, err := h.inviteService.Create(c.Request.Context(), userID.(uint), service.CreateInviteInput{
+		Email:     req.Email,
+		MaxUses:   req.MaxUses,
+		ExpiresIn: time.Duration(req.ExpiresInDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	util.RespondCreated(c, CreateInviteResponse{
+		Invite: invite,
+		Code:   code,
+	})
+}
+
+// List handles GET /api/admin/invites
+// @Summary List invites
+// @Description List invite codes, newest first (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Router /api/admin/invites [get]
+func (h *InviteHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	invites, total, err := h.inviteService.List(c.Request.Context(), page, pageSize)
+	if err != nil {
+		util.RespondInternalError(c, "failed to list invites")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"invites":    invites,
+		"pagination": util.CalculatePagination(page, pageSize, total),
+	})
+}
+
+// Revoke handles DELETE /api/admin/invites/:id
+// @Summary Revoke an invite
+// @Description Stop an invite code from being used (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invite ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/admin/invites/{id} [delete]
+func (h *InviteHandler) Revoke(c *gin.Context) {
+	inviteID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid invite ID")
+		return
+	}
+
+	if err := h.inviteService.Revoke(c.Request.Context(), uint(inviteID)); err != nil {
+		util.RespondNotFound(c, "Invite")
+		return
+	}
+
+	util.RespondSuccess(c, "Invite revoked successfully", nil)
+}
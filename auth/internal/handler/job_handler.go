@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"inkstack-auth/internal/scheduler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobHandler handles background job status HTTP requests
+type JobHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(scheduler *scheduler.Scheduler) *JobHandler {
+	return &JobHandler{
+		scheduler: scheduler,
+	}
+}
+
+// ListJobs handles GET /api/admin/jobs
+// @Summary List background jobs
+// @Description Run counts, failures and rows purged for each maintenance job on the instance that serves the request (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/admin/jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	c.JSON(200, gin.H{
+		"jobs": h.scheduler.Stats(),
+	})
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// magicLinkCookie holds the browser token that binds a magic link to the browser that requested it
+	magicLinkCookie = "magic_link_browser"
+
+	// magicLinkCookiePath limits the cookie to the magic link endpoints
+	magicLinkCookiePath = "/api/auth/magic-link"
+)
+
+// MagicLinkHandler handles passwordless login HTTP requests
+type MagicLinkHandler struct {
+	authService *service.AuthService
+	config      *config.Config
+}
+
+// NewMagicLinkHandler creates a new magic link handler
+func NewMagicLinkHandler(authService *service.AuthService, cfg *config.Config) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+// MagicLinkRequest represents magic link request body
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// ConfirmMagicLinkRequest represents magic link confirmation request body.
+// BrowserToken is only needed by clients that cannot keep cookies.
+type ConfirmMagicLinkRequest struct {
+	Token        string `json:"token" binding:"required"`
+	BrowserToken string `json:"browser_token"`
+}
+
+// RequestLink handles POST /api/auth/magic-link
+// @Summary Request a login link
+// @Description Email a single-use login link. The response is the same whether or not the address has an account.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body MagicLinkRequest true "Email address"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/auth/magic-link [post]
+func (h *MagicLinkHandler) RequestLink(c *gin.Context) {
+	var req MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	browserToken, err := h.authService.RequestMagicLink(c.Request.Context(), service.MagicLinkInput{
+		Email:     req.Email,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(magicLinkCookie, browserToken, int(h.config.MagicLink.Expiry.Seconds()),
+		magicLinkCookiePath, "", h.config.IsProduction(), true)
+
+	util.RespondSuccess(c, "If this address can sign in, a login link has been sent", gin.H{
+		"browser_token": browserToken,
+		"expires_in":    int(h.config.MagicLink.Expiry.Seconds()),
+	})
+}
+
+// ConfirmLink handles POST /api/auth/magic-link/confirm
+// @Summary Sign in with a login link
+// @Description Exchange the token from a login link for access and refresh tokens. Must be called from the browser that requested the link.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ConfirmMagicLinkRequest true "Token from the login link"
+// @Param X-Session-Mode header string false "Set to cookie to receive tokens as httpOnly cookies (CookieSessionResponse)"
+// @Success 200 {object} AuthResponse
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/auth/magic-link/confirm [post]
+func (h *MagicLinkHandler) ConfirmLink(c *gin.Context) {
+	var req ConfirmMagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	browserToken := req.BrowserToken
+	if browserToken == "" {
+		browserToken, _ = c.Cookie(magicLinkCookie)
+	}
+
+	// The link is single-use, so the browser token is no longer needed either way
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(magicLinkCookie, "", -1, magicLinkCookiePath, "", h.config.IsProduction(), true)
+
+	user, tokens, err := h.authService.ConfirmMagicLink(c.Request.Context(), req.Token, browserToken, clientInfo(c))
+	if err != nil {
+		util.RespondUnauthorized(c, err.Error())
+		return
+	}
+
+	response, err := sessionResponse(c, h.config, user, tokens)
+	if err != nil {
+		util.RespondInternalError(c, "Failed to start session")
+		return
+	}
+
+	util.RespondSuccess(c, "Login successful", response)
+}
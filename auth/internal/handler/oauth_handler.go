@@ -0,0 +1,411 @@
+package handler
+
+import (
+	"errors"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthHandler handles OAuth2 authorization server HTTP requests
+type OAuthHandler struct {
+	oauthService *service.OAuthService
+}
+
+// NewOAuthHandler creates a new OAuth handler
+func NewOAuthHandler(oauthService *service.OAuthService) *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: oauthService,
+	}
+}
+
+// RegisterClientRequest represents client registration request body
+type RegisterClientRequest struct {
+	Name         string   `json:"name" binding:"required,max=100"`
+	RedirectURIs []string `json:"redirect_uris"`
+	GrantTypes   []string `json:"grant_types"`
+	Scopes       []string `json:"scopes" binding:"required,min=1"`
+	Confidential bool     `json:"confidential"`
+}
+
+// OAuthClientResponse represents an OAuth client without its secret
+type OAuthClientResponse struct {
+	ClientID       string    `json:"client_id"`
+	Name           string    `json:"name"`
+	RedirectURIs   []string  `json:"redirect_uris"`
+	GrantTypes     []string  `json:"grant_types"`
+	Scopes         []string  `json:"scopes"`
+	IsConfidential bool      `json:"is_confidential"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// RegisterClientResponse includes the client secret, shown only once
+type RegisterClientResponse struct {
+	OAuthClientResponse
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// AuthorizeDecisionRequest represents the user's consent decision
+type AuthorizeDecisionRequest struct {
+	ResponseType        string `json:"response_type" binding:"required"`
+	ClientID            string `json:"client_id" binding:"required"`
+	RedirectURI         string `json:"redirect_uri" binding:"required"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+	Approve             bool   `json:"approve"`
+}
+
+// ConsentResponse is the data needed to render the consent screen
+type ConsentResponse struct {
+	Client            OAuthClientResponse `json:"client"`
+	Scopes            []string            `json:"scopes"`
+	RedirectURI       string              `json:"redirect_uri"`
+	State             string              `json:"state,omitempty"`
+	PreviouslyGranted bool                `json:"previously_granted"`
+}
+
+// RegisterClient handles POST /api/oauth/clients
+// @Summary Register an OAuth client
+// @Description Register a third-party application. The client secret is only returned once.
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterClientRequest true "Client details"
+// @Success 201 {object} RegisterClientResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/oauth/clients [post]
+func (h *OAuthHandler) RegisterClient(c *gin.Context) {
+	var req RegisterClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	client, secret, err := h.oauthService.RegisterClient(c.Request.Context(), userID.(uint), service.RegisterClientInput{
+		Name:         req.Name,
+		RedirectURIs: req.RedirectURIs,
+		GrantTypes:   req.GrantTypes,
+		Scopes:       req.Scopes,
+		Confidential: req.Confidential,
+	})
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	util.RespondCreated(c, RegisterClientResponse{
+		OAuthClientResponse: toOAuthClientResponse(client),
+		ClientSecret:        secret,
+	})
+}
+
+// ListClients handles GET /api/oauth/clients
+// @Summary List OAuth clients
+// @Description List the OAuth clients registered by the authenticated user
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Router /api/oauth/clients [get]
+func (h *OAuthHandler) ListClients(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	clients, err := h.oauthService.ListClients(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.RespondInternalError(c, "failed to list clients")
+		return
+	}
+
+	responses := make([]OAuthClientResponse, len(clients))
+	for i := range clients {
+		responses[i] = toOAuthClientResponse(&clients[i])
+	}
+
+	c.JSON(200, gin.H{
+		"clients": responses,
+	})
+}
+
+// DeleteClient handles DELETE /api/oauth/clients/:client_id
+// @Summary Delete an OAuth client
+// @Description Delete one of the authenticated user's OAuth clients
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Param client_id path string true "Client ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/oauth/clients/{client_id} [delete]
+func (h *OAuthHandler) DeleteClient(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.oauthService.DeleteClient(c.Request.Context(), userID.(uint), c.Param("client_id")); err != nil {
+		util.RespondNotFound(c, "Client")
+		return
+	}
+
+	util.RespondSuccess(c, "Client deleted successfully", nil)
+}
+
+// GetAuthorize handles GET /api/oauth/authorize
+// @Summary Get consent screen data
+// @Description Validate an authorization request and return what the consent screen should display
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Param response_type query string true "Must be code"
+// @Param client_id query string true "Client ID"
+// @Param redirect_uri query string true "Registered redirect URI"
+// @Param scope query string false "Space-separated scopes"
+// @Param state query string false "Opaque client state"
+// @Param code_challenge query string false "PKCE code challenge"
+// @Param code_challenge_method query string false "PKCE method (S256)"
+// @Success 200 {object} ConsentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/oauth/authorize [get]
+func (h *OAuthHandler) GetAuthorize(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	details, err := h.oauthService.PrepareAuthorization(c.Request.Context(), userID.(uint), service.AuthorizeRequest{
+		ResponseType:        c.Query("response_type"),
+		ClientID:            c.Query("client_id"),
+		RedirectURI:         c.Query("redirect_uri"),
+		Scope:               c.Query("scope"),
+		State:               c.Query("state"),
+		CodeChallenge:       c.Query("code_challenge"),
+		CodeChallengeMethod: c.Query("code_challenge_method"),
+	})
+	if err != nil {
+		respondOAuthError(c, err)
+		return
+	}
+
+	c.JSON(200, ConsentResponse{
+		Client:            toOAuthClientResponse(details.Client),
+		Scopes:            details.Scopes,
+		RedirectURI:       details.RedirectURI,
+		State:             details.State,
+		PreviouslyGranted: details.PreviouslyGranted,
+	})
+}
+
+// PostAuthorize handles POST /api/oauth/authorize
+// @Summary Submit consent decision
+// @Description Approve or deny an authorization request and get the URL to redirect the user to
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AuthorizeDecisionRequest true "Authorization request and decision"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/oauth/authorize [post]
+func (h *OAuthHandler) PostAuthorize(c *gin.Context) {
+	var req AuthorizeDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	redirectTo, err := h.oauthService.Authorize(c.Request.Context(), userID.(uint), service.AuthorizeRequest{
+		ResponseType:        req.ResponseType,
+		ClientID:            req.ClientID,
+		RedirectURI:         req.RedirectURI,
+		Scope:               req.Scope,
+		State:               req.State,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+	}, req.Approve)
+	if err != nil {
+		respondOAuthError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"redirect_to": redirectTo,
+	})
+}
+
+// Token handles POST /api/oauth/token
+// @Summary OAuth2 token endpoint
+// @Description Exchange an authorization code, refresh token or client credentials for tokens (RFC 6749)
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "authorization_code, refresh_token or client_credentials"
+// @Param code formData string false "Authorization code"
+// @Param redirect_uri formData string false "Redirect URI used in the authorization request"
+// @Param code_verifier formData string false "PKCE code verifier"
+// @Param refresh_token formData string false "Refresh token"
+// @Param scope formData string false "Requested scopes"
+// @Param client_id formData string false "Client ID (if not using HTTP Basic)"
+// @Param client_secret formData string false "Client secret (if not using HTTP Basic)"
+// @Success 200 {object} service.OAuthTokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /api/oauth/token [post]
+func (h *OAuthHandler) Token(c *gin.Context) {
+	clientID, clientSecret := clientCredentials(c)
+
+	response, err := h.oauthService.Token(c.Request.Context(), service.TokenRequest{
+		GrantType:    c.PostForm("grant_type"),
+		Code:         c.PostForm("code"),
+		RedirectURI:  c.PostForm("redirect_uri"),
+		CodeVerifier: c.PostForm("code_verifier"),
+		RefreshToken: c.PostForm("refresh_token"),
+		Scope:        c.PostForm("scope"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	})
+	if err != nil {
+		respondOAuthError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Pragma", "no-cache")
+	c.JSON(200, response)
+}
+
+// Introspect handles POST /api/oauth/introspect
+// @Summary Token introspection
+// @Description Report whether a token is active and its metadata (RFC 7662). Requires client authentication.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Token to introspect"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Success 200 {object} service.IntrospectionResponse
+// @Failure 401 {object} map[string]string
+// @Router /api/oauth/introspect [post]
+func (h *OAuthHandler) Introspect(c *gin.Context) {
+	clientID, clientSecret := clientCredentials(c)
+	client, err := h.oauthService.AuthenticateClient(clientID, clientSecret)
+	if err != nil {
+		respondOAuthError(c, err)
+		return
+	}
+
+	response := h.oauthService.Introspect(c.Request.Context(), client, c.PostForm("token"), c.PostForm("token_type_hint"))
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(200, response)
+}
+
+// Revoke handles POST /api/oauth/revoke
+// @Summary Token revocation
+// @Description Revoke an access or refresh token issued to the client (RFC 7009)
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Token to revoke"
+// @Param token_type_hint formData string false "access_token or refresh_token"
+// @Success 200
+// @Failure 401 {object} map[string]string
+// @Router /api/oauth/revoke [post]
+func (h *OAuthHandler) Revoke(c *gin.Context) {
+	clientID, clientSecret := clientCredentials(c)
+	client, err := h.oauthService.AuthenticateClient(clientID, clientSecret)
+	if err != nil {
+		respondOAuthError(c, err)
+		return
+	}
+
+	if err := h.oauthService.Revoke(c.Request.Context(), client, c.PostForm("token"), c.PostForm("token_type_hint")); err != nil {
+		respondOAuthError(c, err)
+		return
+	}
+
+	c.Status(200)
+}
+
+// clientCredentials extracts client credentials from HTTP Basic auth or the request body
+func clientCredentials(c *gin.Context) (string, string) {
+	if username, password, ok := c.Request.BasicAuth(); ok {
+		// RFC 6749 section 2.3.1: credentials are form-encoded before Basic encoding
+		clientID, err := url.QueryUnescape(username)
+		if err != nil {
+			clientID = username
+		}
+		clientSecret, err := url.QueryUnescape(password)
+		if err != nil {
+			clientSecret = password
+		}
+		return clientID, clientSecret
+	}
+
+	return c.PostForm("client_id"), c.PostForm("client_secret")
+}
+
+// respondOAuthError writes an RFC 6749 error response
+func respondOAuthError(c *gin.Context, err error) {
+	var oauthErr *service.OAuthError
+	if !errors.As(err, &oauthErr) {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":             service.OAuthErrServerError,
+			"error_description": "internal server error",
+		})
+		return
+	}
+
+	status := http.StatusBadRequest
+	switch oauthErr.Code {
+	case service.OAuthErrInvalidClient:
+		status = http.StatusUnauthorized
+		if _, _, ok := c.Request.BasicAuth(); ok {
+			c.Header("WWW-Authenticate", `Basic realm="inkstack"`)
+		}
+	case service.OAuthErrServerError:
+		status = http.StatusInternalServerError
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, gin.H{
+		"error":             oauthErr.Code,
+		"error_description": oauthErr.Description,
+	})
+}
+
+func toOAuthClientResponse(client *models.OAuthClient) OAuthClientResponse {
+	return OAuthClientResponse{
+		ClientID:       client.ClientID,
+		Name:           client.Name,
+		RedirectURIs:   client.RedirectURIList(),
+		GrantTypes:     client.GrantTypeList(),
+		Scopes:         client.ScopeList(),
+		IsConfidential: client.IsConfidential,
+		CreatedAt:      client.CreatedAt,
+	}
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PersonalAccessTokenHandler handles personal access token HTTP requests
+type PersonalAccessTokenHandler struct {
+	patService *service.PersonalAccessTokenService
+}
+
+// NewPersonalAccessTokenHandler creates a new personal access token handler
+func NewPersonalAccessTokenHandler(patService *service.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{
+		patService: patService,
+	}
+}
+
+// CreatePersonalAccessTokenRequest represents create token request body
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=100"`
+	Scopes        []string `json:"scopes" binding:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1,max=3650"`
+}
+
+// PersonalAccessTokenResponse represents a personal access token without its secret
+type PersonalAccessTokenResponse struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scopes      []string   `json:"scopes"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  string     `json:"last_used_ip,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreatePersonalAccessTokenResponse includes the plaintext token, shown only once
+type CreatePersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
+	Token string `json:"token"`
+}
+
+// Create handles POST /api/auth/tokens
+// @Summary Create a personal access token
+// @Description Issue a scoped token for automation. The token value is only returned once.
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreatePersonalAccessTokenRequest true "Token details"
+// @Success 201 {object} CreatePersonalAccessTokenResponse
+// @Failure 400 {object} map[string]interface{}
+// @Router /api/auth/tokens [post]
+func (h *PersonalAccessTokenHandler) Create(c *gin.Context) {
+	var req CreatePersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	token, plaintext, err := h.patService.Create(c.Request.Context(), userID.(uint), service.CreatePersonalAccessTokenInput{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresIn: time.Duration(req.ExpiresInDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	util.RespondCreated(c, CreatePersonalAccessTokenResponse{
+		PersonalAccessTokenResponse: toPersonalAccessTokenResponse(token),
+		Token:                       plaintext,
+	})
+}
+
+// List handles GET /api/auth/tokens
+// @Summary List personal access tokens
+// @Description List the authenticated user's personal access tokens
+// @Tags tokens
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/auth/tokens [get]
+func (h *PersonalAccessTokenHandler) List(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	tokens, err := h.patService.List(c.Request.Context(), userID.(uint))
+	if err != nil {
+		util.RespondInternalError(c, "failed to list tokens")
+		return
+	}
+
+	responses := make([]PersonalAccessTokenResponse, len(tokens))
+	for i := range tokens {
+		responses[i] = toPersonalAccessTokenResponse(&tokens[i])
+	}
+
+	c.JSON(200, gin.H{
+		"tokens": responses,
+	})
+}
+
+// Revoke handles DELETE /api/auth/tokens/:id
+// @Summary Revoke a personal access token
+// @Description Revoke one of the authenticated user's personal access tokens
+// @Tags tokens
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Token ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/auth/tokens/{id} [delete]
+func (h *PersonalAccessTokenHandler) Revoke(c *gin.Context) {
+	tokenID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid token ID")
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	if err := h.patService.Revoke(c.Request.Context(), userID.(uint), uint(tokenID)); err != nil {
+		util.RespondNotFound(c, "Token")
+		return
+	}
+
+	util.RespondSuccess(c, "Token revoked successfully", nil)
+}
+
+func toPersonalAccessTokenResponse(token *models.PersonalAccessToken) PersonalAccessTokenResponse {
+	return PersonalAccessTokenResponse{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      token.ScopeList(),
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		LastUsedIP:  token.LastUsedIP,
+		RevokedAt:   token.RevokedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHandler handles role management HTTP requests
+type RoleHandler struct {
+	roleService *service.RoleService
+}
+
+// NewRoleHandler creates a new role handler
+func NewRoleHandler(roleService *service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+	}
+}
+
+// AssignRoleRequest represents assign role request body
+type AssignRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// ListRoles handles GET /api/admin/roles
+// @Summary List roles
+// @Description List every role and the permissions it grants (admin only)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/admin/roles [get]
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles()
+	if err != nil {
+		util.RespondInternalError(c, "failed to list roles")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"roles": roles,
+	})
+}
+
+// AssignRole handles PUT /api/admin/users/:id/role
+// @Summary Assign a role
+// @Description Change a user's role (admin only). Takes effect when the user's next access token is issued.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body AssignRoleRequest true "Role name"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/admin/users/{id}/role [put]
+func (h *RoleHandler) AssignRole(c *gin.Context) {
+	targetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		util.RespondBadRequest(c, "invalid user ID")
+		return
+	}
+
+	var req AssignRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	user, err := h.roleService.AssignRole(c.Request.Context(), userID.(uint), uint(targetID), req.Role, clientInfo(c))
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	util.RespondSuccess(c, "Role updated", gin.H{
+		"user":        user.ToPublic(),
+		"permissions": h.roleService.Permissions(user.Role),
+	})
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/middleware"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CookieSessionResponse is returned instead of AuthResponse when tokens are set as cookies
+type CookieSessionResponse struct {
+	User      interface{} `json:"user"`
+	CSRFToken string      `json:"csrf_token"`
+	ExpiresIn int         `json:"expires_in"` // Access token lifetime in seconds
+}
+
+// SessionHandler handles cookie session HTTP requests
+type SessionHandler struct {
+	authService *service.AuthService
+	config      *config.Config
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(authService *service.AuthService, cfg *config.Config) *SessionHandler {
+	return &SessionHandler{
+		authService: authService,
+		config:      cfg,
+	}
+}
+
+// Refresh handles POST /api/auth/session/refresh
+// @Summary Refresh a cookie session
+// @Description Issue a new access token cookie using the refresh token cookie. Requires the X-CSRF-Token header.
+// @Tags auth
+// @Produce json
+// @Param X-CSRF-Token header string true "Value of the inkstack_csrf cookie"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/auth/session/refresh [post]
+func (h *SessionHandler) Refresh(c *gin.Context) {
+	refreshToken, err := c.Cookie(middleware.RefreshTokenCookie)
+	if err != nil || refreshToken == "" {
+		util.RespondUnauthorized(c, "Refresh token cookie required")
+		return
+	}
+
+	accessToken, err := h.authService.RefreshToken(c.Request.Context(), refreshToken, clientInfo(c))
+	if err != nil {
+		clearSessionCookies(c, h.config)
+		util.RespondUnauthorized(c, err.Error())
+		return
+	}
+
+	setSessionCookie(c, h.config, middleware.AccessTokenCookie, accessToken, "/", int(h.config.JWT.AccessExpiry.Seconds()), true)
+
+	c.JSON(200, gin.H{
+		"expires_in": int(h.config.JWT.AccessExpiry.Seconds()),
+	})
+}
+
+// Logout handles POST /api/auth/session/logout
+// @Summary End a cookie session
+// @Description Revoke the session's refresh token, blacklist its access token if still valid and clear the session cookies. Works with only the refresh token cookie, so an expired access token doesn't prevent signing out. Requires the X-CSRF-Token header.
+// @Tags auth
+// @Produce json
+// @Param X-CSRF-Token header string true "Value of the inkstack_csrf cookie"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/auth/session/logout [post]
+func (h *SessionHandler) Logout(c *gin.Context) {
+	refreshToken, _ := c.Cookie(middleware.RefreshTokenCookie)
+	accessToken, _ := c.Cookie(middleware.AccessTokenCookie)
+
+	// The cookies are cleared even if revoking fails, so the browser is signed out either way
+	clearSessionCookies(c, h.config)
+
+	if err := h.authService.EndSession(c.Request.Context(), refreshToken, accessToken, clientInfo(c)); err != nil {
+		util.RespondInternalError(c, "Failed to end session")
+		return
+	}
+
+	util.RespondSuccess(c, "Logged out successfully", nil)
+}
+
+// sessionResponse returns the body for a successful sign-in. Clients that ask for a
+// cookie session get the tokens as httpOnly cookies and only a CSRF token in the body.
+func sessionResponse(c *gin.Context, cfg *config.Config, user *models.User, tokens *service.TokenPair) (interface{}, error) {
+	if !cfg.Session.CookiesEnabled || !strings.EqualFold(c.GetHeader(middleware.SessionModeHeader), "cookie") {
+		return AuthResponse{
+			User:         user.ToPublic(),
+			AccessToken:  tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+		}, nil
+	}
+
+	csrfToken, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshMaxAge := int(cfg.JWT.RefreshExpiry.Seconds())
+	setSessionCookie(c, cfg, middleware.AccessTokenCookie, tokens.AccessToken, "/", int(cfg.JWT.AccessExpiry.Seconds()), true)
+	setSessionCookie(c, cfg, middleware.RefreshTokenCookie, tokens.RefreshToken, middleware.RefreshTokenCookiePath, refreshMaxAge, true)
+	setSessionCookie(c, cfg, middleware.CSRFCookie, csrfToken, "/", refreshMaxAge, false)
+
+	return CookieSessionResponse{
+		User:      user.ToPublic(),
+		CSRFToken: csrfToken,
+		ExpiresIn: int(cfg.JWT.AccessExpiry.Seconds()),
+	}, nil
+}
+
+// clearSessionCookies removes every cookie session cookie
+func clearSessionCookies(c *gin.Context, cfg *config.Config) {
+	setSessionCookie(c, cfg, middleware.AccessTokenCookie, "", "/", -1, true)
+	setSessionCookie(c, cfg, middleware.RefreshTokenCookie, "", middleware.RefreshTokenCookiePath, -1, true)
+	setSessionCookie(c, cfg, middleware.CSRFCookie, "", "/", -1, false)
+}
+
+// setSessionCookie writes a cookie with the configured domain, Secure flag and SameSite mode
+func setSessionCookie(c *gin.Context, cfg *config.Config, name, value, path string, maxAge int, httpOnly bool) {
+	sameSite := http.SameSiteLaxMode
+	switch cfg.Session.CookieSameSite {
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   cfg.Session.CookieDomain,
+		MaxAge:   maxAge,
+		Secure:   cfg.Session.CookieSecure,
+		HttpOnly: httpOnly,
+		SameSite: sameSite,
+	})
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"fmt"
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler handles user profile HTTP requests
+type UserHandler struct {
+	userService *service.UserService
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(userService *service.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// UpdateProfileRequest represents update profile request body. Omitted fields are left unchanged.
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name"`
+	Bio         *string `json:"bio"`
+	AvatarURL   *string `json:"avatar_url"`
+}
+
+// UpdateMe handles PATCH /api/auth/me
+// @Summary Update current user profile
+// @Description Update the authenticated user's display name, bio and avatar URL
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body UpdateProfileRequest true "Profile fields to update"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/auth/me [patch]
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		util.RespondUnauthorized(c, "User not authenticated")
+		return
+	}
+
+	user, err := h.userService.UpdateProfile(c.Request.Context(), userID.(uint), service.UpdateProfileInput{
+		DisplayName: req.DisplayName,
+		Bio:         req.Bio,
+		AvatarURL:   req.AvatarURL,
+	})
+	if err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+
+	util.RespondSuccess(c, "Profile updated successfully", gin.H{
+		"user": user.ToPublic(),
+	})
+}
+
+// GetProfile handles GET /api/users/:username
+// @Summary Get public user profile
+// @Description Get a user's public profile by username. The email address is never included.
+// @Tags users
+// @Produce json
+// @Param username path string true "Username"
+// @Success 200 {object} models.PublicProfile
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/users/{username} [get]
+func (h *UserHandler) GetProfile(c *gin.Context) {
+	user, err := h.userService.GetProfile(c.Request.Context(), c.Param("username"))
+	if err != nil {
+		util.RespondNotFound(c, "User")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"user": user.ToProfile(),
+	})
+}
+
+// LookupProfilesRequest represents a batch profile lookup request body
+type LookupProfilesRequest struct {
+	IDs       []uint   `json:"ids"`
+	Usernames []string `json:"usernames"`
+}
+
+// LookupProfiles handles POST /api/users/lookup
+// Called by other Inkstack services to resolve user IDs and usernames to public profiles
+// in one request, without the per-IP limit of GET /api/users/:username.
+// Unknown and inactive users are left out of the response.
+func (h *UserHandler) LookupProfiles(c *gin.Context) {
+	var req LookupProfilesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.RespondBadRequest(c, err.Error())
+		return
+	}
+	if req.IDs == nil && req.Usernames == nil {
+		util.RespondBadRequest(c, "ids or usernames is required")
+		return
+	}
+	if len(req.IDs)+len(req.Usernames) > service.MaxProfileLookup {
+		util.RespondBadRequest(c, fmt.Sprintf("at most %d users can be looked up at once", service.MaxProfileLookup))
+		return
+	}
+
+	profiles, err := h.userService.LookupProfiles(c.Request.Context(), req.IDs, req.Usernames)
+	if err != nil {
+		util.RespondInternalError(c, "Failed to look up users")
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"users": profiles,
+	})
+}
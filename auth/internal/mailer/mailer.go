@@ -0,0 +1,94 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"inkstack-auth/internal/config"
+	"log"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New creates the mailer selected by MAIL_DRIVER
+func New(cfg *config.Config) (Mailer, error) {
+	switch cfg.Mail.Driver {
+	case "log", "":
+		return &logMailer{}, nil
+	case "smtp":
+		if cfg.Mail.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required for the smtp mail driver")
+		}
+		return &smtpMailer{config: cfg.Mail}, nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s", cfg.Mail.Driver)
+	}
+}
+
+// logMailer writes messages to the log instead of sending them. For development only.
+type logMailer struct{}
+
+// Send logs the message
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("Email to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// smtpMailer sends messages through an SMTP server
+type smtpMailer struct {
+	config config.MailConfig
+}
+
+// Send delivers the message over SMTP
+func (m *smtpMailer) Send(ctx context.Context, msg Message) error {
+	if strings.ContainsAny(msg.To+msg.Subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+
+	addr := net.JoinHostPort(m.config.SMTPHost, m.config.SMTPPort)
+
+	var auth smtp.Auth
+	if m.config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", m.config.SMTPUsername, m.config.SMTPPassword, m.config.SMTPHost)
+	}
+
+	body := strings.Join([]string{
+		"From: " + m.config.From,
+		"To: " + msg.To,
+		"Subject: " + msg.Subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		msg.Body,
+	}, "\r\n")
+
+	// net/smtp has no context support, so run it in the background and stop waiting on cancellation
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, m.config.From, []string{msg.To}, []byte(body))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -3,50 +3,52 @@ package middleware
 import (
 	"inkstack-auth/internal/service"
 	"inkstack-auth/internal/util"
-	"strings"
+	"log"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware creates middleware that validates JWT tokens
+// AuthMiddleware creates middleware that validates JWT tokens from the Authorization
+// header or, for cookie sessions, the access token cookie
 func AuthMiddleware(jwtService *service.JWTService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Extract token from Authorization header
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			util.RespondUnauthorized(c, "Authorization header required")
+		token, fromCookie, errMessage := requestToken(c)
+		if errMessage != "" {
+			util.RespondUnauthorized(c, errMessage)
 			c.Abort()
 			return
 		}
 
-		// Check Bearer prefix
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			util.RespondUnauthorized(c, "Invalid authorization header format")
+		// Browsers send cookies on cross-site requests too, so cookie sessions need a CSRF token
+		if fromCookie && !validCSRF(c) {
+			util.RespondForbidden(c, "Missing or invalid CSRF token")
 			c.Abort()
 			return
 		}
 
-		// Extract token
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == "" {
-			util.RespondUnauthorized(c, "Token is required")
+		// Validate token
+		claims, err := jwtService.ValidateAccessToken(token)
+		if err != nil {
+			util.RespondUnauthorized(c, "Invalid or expired token")
 			c.Abort()
 			return
 		}
 
-		// Validate token
-		claims, err := jwtService.ValidateToken(token)
-		if err != nil {
-			util.RespondUnauthorized(c, "Invalid or expired token")
+		// Tokens delegated to OAuth clients must not manage the account itself
+		if claims.ClientID != "" {
+			util.RespondForbidden(c, "OAuth client tokens cannot access account endpoints")
 			c.Abort()
 			return
 		}
 
 		// Store user info in context for handlers
+		c.Set("access_token", token)
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
 		c.Set("username", claims.Username)
 		c.Set("role", claims.Role)
+		c.Set("permissions", claims.Permissions)
+		setActorContext(c, claims)
 
 		c.Next()
 	}
@@ -72,26 +74,73 @@ func RequireRole(role string, jwtService *service.JWTService) gin.HandlerFunc {
 	}
 }
 
-// OptionalAuth is middleware that extracts user info if token exists, but doesn't require it
-func OptionalAuth(jwtService *service.JWTService) gin.HandlerFunc {
+// RequirePermission creates middleware that checks the user's role grants a permission
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions, exists := c.Get("permissions")
+		if !exists {
+			util.RespondForbidden(c, "Permission information not found")
+			c.Abort()
+			return
+		}
+
+		granted, _ := permissions.([]string)
+		for _, p := range granted {
+			if p == permission {
+				c.Next()
+				return
+			}
+		}
+
+		util.RespondForbidden(c, "Insufficient permissions")
+		c.Abort()
+	}
+}
+
+// BlockImpersonation rejects impersonation tokens on sensitive account actions,
+// such as changing credentials or creating tokens
+func BlockImpersonation() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.Next()
+		if _, impersonating := c.Get("impersonator_id"); impersonating {
+			util.RespondForbidden(c, "This action is not allowed while impersonating a user")
+			c.Abort()
 			return
 		}
 
-		if strings.HasPrefix(authHeader, "Bearer ") {
-			token := strings.TrimPrefix(authHeader, "Bearer ")
-			claims, err := jwtService.ValidateToken(token)
-			if err == nil {
+		c.Next()
+	}
+}
+
+// OptionalAuth is middleware that extracts user info if token exists, but doesn't require it
+func OptionalAuth(jwtService *service.JWTService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, fromCookie, errMessage := requestToken(c)
+		if errMessage == "" && (!fromCookie || validCSRF(c)) {
+			claims, err := jwtService.ValidateAccessToken(token)
+			if err == nil && claims.ClientID == "" {
 				c.Set("user_id", claims.UserID)
 				c.Set("email", claims.Email)
 				c.Set("username", claims.Username)
 				c.Set("role", claims.Role)
+				c.Set("permissions", claims.Permissions)
+				setActorContext(c, claims)
 			}
 		}
 
 		c.Next()
 	}
 }
+
+// setActorContext stores the staff member behind an impersonation token in the
+// request context and logs the request so every action taken is attributable
+func setActorContext(c *gin.Context, claims *service.JWTClaims) {
+	if claims.Actor == nil {
+		return
+	}
+
+	c.Set("impersonator_id", claims.Actor.UserID)
+	c.Set("impersonator_username", claims.Actor.Username)
+	c.Set("impersonation_session", claims.ID)
+	log.Printf("Impersonation: %s (user %d) acting as %s (user %d), session %s: %s %s",
+		claims.Actor.Username, claims.Actor.UserID, claims.Username, claims.UserID, claims.ID, c.Request.Method, c.Request.URL.Path)
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"inkstack-auth/internal/util"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cookie session names, shared with the API service
+const (
+	// SessionModeHeader asks the auth service to return tokens in cookies ("cookie")
+	SessionModeHeader = "X-Session-Mode"
+
+	// AccessTokenCookie holds the access token (httpOnly)
+	AccessTokenCookie = "inkstack_access"
+
+	// RefreshTokenCookie holds the refresh token (httpOnly, scoped to RefreshTokenCookiePath)
+	RefreshTokenCookie = "inkstack_refresh"
+
+	// RefreshTokenCookiePath limits the refresh token cookie to the cookie session endpoints
+	RefreshTokenCookiePath = "/api/auth/session"
+
+	// CSRFCookie holds the CSRF token. It is readable by scripts so the client can
+	// echo it in CSRFHeader; a cross-site page cannot read it.
+	CSRFCookie = "inkstack_csrf"
+
+	// CSRFHeader must match CSRFCookie on unsafe requests authenticated by cookie
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// RequireCSRF rejects unsafe requests whose CSRF header does not match the CSRF cookie
+func RequireCSRF() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !validCSRF(c) {
+			util.RespondForbidden(c, "Missing or invalid CSRF token")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// requestToken finds the access token in the Authorization header or, failing that,
+// the access token cookie. Returns an error message if neither holds a usable token.
+func requestToken(c *gin.Context) (token string, fromCookie bool, errMessage string) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		if cookie, err := c.Cookie(AccessTokenCookie); err == nil && cookie != "" {
+			return cookie, true, ""
+		}
+		return "", false, "Authorization header required"
+	}
+
+	// Check Bearer prefix
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return "", false, "Invalid authorization header format"
+	}
+
+	// Extract token
+	token = strings.TrimPrefix(authHeader, "Bearer ")
+	if token == "" {
+		return "", false, "Token is required"
+	}
+
+	return token, false, ""
+}
+
+// validCSRF checks the double-submit CSRF token. Safe methods never change state and are always allowed.
+func validCSRF(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	cookie, err := c.Cookie(CSRFCookie)
+	header := c.GetHeader(CSRFHeader)
+	if err != nil || cookie == "" || header == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
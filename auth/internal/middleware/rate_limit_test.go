@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitKey returns the key keyFunc derives for req on an engine trusting the given proxies
+func rateLimitKey(t *testing.T, trustedProxies []string, keyFunc RateLimitKeyFunc, req *http.Request) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		t.Fatalf("SetTrustedProxies: %v", err)
+	}
+	var key string
+	r.GET("/", func(c *gin.Context) {
+		key = keyFunc(c)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	return key
+}
+
+func TestKeyByIPIgnoresSpoofedForwardedFor(t *testing.T) {
+	for _, spoofed := range []string{"198.51.100.1", "198.51.100.2", "10.0.0.1, 198.51.100.3"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "203.0.113.7:41000"
+		req.Header.Set("X-Forwarded-For", spoofed)
+		req.Header.Set("X-Real-IP", spoofed)
+
+		if key := rateLimitKey(t, nil, KeyByIP, req); key != "ip:203.0.113.7" {
+			t.Errorf("X-Forwarded-For %q: key = %q, want ip:203.0.113.7", spoofed, key)
+		}
+	}
+}
+
+func TestKeyByIPUsesForwardedForFromTrustedProxy(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.5:41000"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	if key := rateLimitKey(t, []string{"10.0.0.0/8"}, KeyByIP, req); key != "ip:198.51.100.1" {
+		t.Errorf("key = %q, want ip:198.51.100.1", key)
+	}
+}
+
+func TestKeyByToken(t *testing.T) {
+	request := func(authorization, cookie string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "203.0.113.7:41000"
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: cookie})
+		}
+		return req
+	}
+
+	first := rateLimitKey(t, nil, KeyByToken, request("Bearer token-one", ""))
+	if !strings.HasPrefix(first, "token:") || strings.Contains(first, "token-one") {
+		t.Fatalf("key = %q, want a hashed token key", first)
+	}
+	if again := rateLimitKey(t, nil, KeyByToken, request("Bearer token-one", "")); again != first {
+		t.Errorf("same token: key = %q, want %q", again, first)
+	}
+	if other := rateLimitKey(t, nil, KeyByToken, request("Bearer token-two", "")); other == first {
+		t.Errorf("different tokens share key %q", other)
+	}
+	if cookie := rateLimitKey(t, nil, KeyByToken, request("", "token-one")); cookie != first {
+		t.Errorf("session cookie: key = %q, want %q", cookie, first)
+	}
+
+	for _, authorization := range []string{"", "Basic dXNlcjpwYXNz"} {
+		if key := rateLimitKey(t, nil, KeyByToken, request(authorization, "")); key != "ip:203.0.113.7" {
+			t.Errorf("Authorization %q: key = %q, want ip:203.0.113.7", authorization, key)
+		}
+	}
+}
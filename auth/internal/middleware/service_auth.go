@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"inkstack-auth/internal/service"
+	"inkstack-auth/internal/util"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Service token scopes for internal endpoints
+const (
+	ScopeTokensValidate = "tokens:validate"
+	ScopeUsersRead      = "users:read"
+)
+
+// APIServiceClientID identifies the api service in the service tokens it signs
+const APIServiceClientID = "inkstack-api"
+
+// internalServices lists the client IDs of services allowed to call internal endpoints.
+// Registered OAuth client IDs are random, so they can never take these values.
+var internalServices = []string{APIServiceClientID}
+
+// ServiceAuthMiddleware only admits service tokens signed by an internal service that
+// carry the given scope. These are never issued to users or OAuth clients.
+func ServiceAuthMiddleware(jwtService *service.JWTService, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			util.RespondUnauthorized(c, "Service token required")
+			c.Abort()
+			return
+		}
+
+		claims, err := jwtService.ValidateToken(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			util.RespondUnauthorized(c, "Invalid or expired token")
+			c.Abort()
+			return
+		}
+
+		if !slices.Contains(claims.Audience, service.ServiceAudience) ||
+			!slices.Contains(internalServices, claims.ClientID) || claims.UserID != 0 {
+			util.RespondForbidden(c, "Only internal services may call this endpoint")
+			c.Abort()
+			return
+		}
+
+		if !slices.Contains(strings.Fields(claims.Scope), scope) {
+			util.RespondForbidden(c, "Insufficient scope: "+scope)
+			c.Abort()
+			return
+		}
+
+		c.Set("service_client_id", claims.ClientID)
+		c.Next()
+	}
+}
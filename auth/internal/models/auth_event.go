@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+// Authentication event types recorded in the audit log
+const (
+	AuthEventRegister       = "register"
+	AuthEventLoginSuccess   = "login_success"
+	AuthEventLoginFailure   = "login_failure"
+	AuthEventRefresh        = "token_refresh"
+	AuthEventLogout         = "logout"
+	AuthEventPasswordChange = "password_change"
+	AuthEventTokenReuse     = "token_reuse"
+	AuthEventLockout        = "lockout"
+	AuthEventRoleChange     = "role_change"
+	AuthEventImpersonation  = "impersonation"
+	AuthEventNewDevice      = "new_device"
+	AuthEventLoginReported  = "login_reported"
+	AuthEventAccountStatus  = "account_status"
+)
+
+// AuthEvent is an entry in the security audit log. Events are append-only,
+// so there is no update or soft-delete timestamp.
+type AuthEvent struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	UserID     *uint     `gorm:"index" json:"user_id,omitempty"`
+	EventType  string    `gorm:"not null;size:50" json:"event_type"`
+	Identifier string    `gorm:"size:255" json:"identifier,omitempty"`
+	IPAddress  string    `gorm:"size:45" json:"ip_address"`
+	UserAgent  string    `gorm:"size:500" json:"user_agent"`
+	Details    string    `gorm:"size:255" json:"details,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for AuthEvent model
+func (AuthEvent) TableName() string {
+	return "auth_events"
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Data export statuses
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportReady      = "ready"
+	DataExportFailed     = "failed"
+)
+
+// DataExport represents a user's request for an archive of their personal data
+type DataExport struct {
+	BaseModel
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	Status      string     `gorm:"not null;size:20;default:'pending';index" json:"status"`
+	FileSize    int64      `gorm:"default:0" json:"file_size"`
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `gorm:"index" json:"expires_at"`
+}
+
+// TableName specifies the table name for DataExport model
+func (DataExport) TableName() string {
+	return "data_exports"
+}
+
+// DataExportArchive holds the zip archive of a ready data export. It lives in its own
+// table so every replica can serve it and listing exports doesn't load archives.
+type DataExportArchive struct {
+	ExportID  uint      `gorm:"primaryKey"`
+	Content   []byte    `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+// TableName specifies the table name for DataExportArchive model
+func (DataExportArchive) TableName() string {
+	return "data_export_archives"
+}
+
+// IsDownloadable checks if the archive is ready and has not expired
+func (e *DataExport) IsDownloadable() bool {
+	return e.Status == DataExportReady && e.ExpiresAt != nil && time.Now().Before(*e.ExpiresAt)
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// EmailChangeRequest is a pending change of a user's email address
+type EmailChangeRequest struct {
+	BaseModel
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	NewEmail    string     `gorm:"not null;size:255" json:"new_email"`
+	TokenHash   string     `gorm:"uniqueIndex;not null;size:64" json:"-"` // Never expose in JSON
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at,omitempty"`
+}
+
+// TableName specifies the table name for EmailChangeRequest model
+func (EmailChangeRequest) TableName() string {
+	return "email_change_requests"
+}
+
+// IsValid checks if the request can still be confirmed
+func (r *EmailChangeRequest) IsValid() bool {
+	return r.ConfirmedAt == nil && time.Now().Before(r.ExpiresAt)
+}
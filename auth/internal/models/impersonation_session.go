@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// ImpersonationSession records an access token issued to a staff member acting as another user
+type ImpersonationSession struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	SessionID string    `gorm:"uniqueIndex;not null;size:64" json:"session_id"`
+	ActorID   *uint     `gorm:"index" json:"actor_id"`
+	TargetID  uint      `gorm:"not null;index" json:"target_id"`
+	Reason    string    `gorm:"not null;size:500" json:"reason"`
+	IPAddress string    `gorm:"size:45" json:"ip_address"`
+	UserAgent string    `gorm:"size:500" json:"user_agent"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for ImpersonationSession model
+func (ImpersonationSession) TableName() string {
+	return "impersonation_sessions"
+}
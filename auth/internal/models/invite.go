@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+// Invite is a code that allows registration when signups are restricted
+type Invite struct {
+	BaseModel
+	CodePrefix string     `gorm:"not null;size:16" json:"code_prefix"`
+	CodeHash   string     `gorm:"uniqueIndex;not null;size:64" json:"-"` // Never expose in JSON
+	Email      string     `gorm:"size:255" json:"email,omitempty"`
+	InviterID  *uint      `gorm:"index" json:"inviter_id,omitempty"`
+	MaxUses    int        `gorm:"not null;default:1" json:"max_uses"`
+	UseCount   int        `gorm:"not null;default:0" json:"use_count"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TableName specifies the table name for Invite model
+func (Invite) TableName() string {
+	return "invites"
+}
+
+// IsUsable checks if the invite can still be redeemed
+func (i *Invite) IsUsable() bool {
+	return i.RevokedAt == nil && i.UseCount < i.MaxUses && time.Now().Before(i.ExpiresAt)
+}
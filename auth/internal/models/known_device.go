@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// KnownDevice records a device and network a user has signed in from
+type KnownDevice struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:uq_known_devices" json:"user_id"`
+	Fingerprint string    `gorm:"not null;size:64;uniqueIndex:uq_known_devices" json:"-"`
+	Description string    `gorm:"not null;size:100" json:"description"`
+	IPRange     string    `gorm:"not null;size:50;uniqueIndex:uq_known_devices" json:"ip_range"`
+	Country     string    `gorm:"size:2" json:"country,omitempty"`
+	FirstSeenAt time.Time `gorm:"not null" json:"first_seen_at"`
+	LastSeenAt  time.Time `gorm:"not null;index" json:"last_seen_at"`
+}
+
+// TableName specifies the table name for KnownDevice model
+func (KnownDevice) TableName() string {
+	return "known_devices"
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// OAuthAuthorizationCode represents a single-use authorization code
+type OAuthAuthorizationCode struct {
+	BaseModel
+	CodeHash            string     `gorm:"uniqueIndex;not null;size:64" json:"-"`
+	ClientID            string     `gorm:"not null;size:64" json:"client_id"`
+	UserID              uint       `gorm:"not null" json:"user_id"`
+	RedirectURI         string     `gorm:"not null;size:500" json:"redirect_uri"`
+	Scopes              string     `gorm:"not null;size:500" json:"scopes"`
+	CodeChallenge       string     `gorm:"size:128" json:"-"`
+	CodeChallengeMethod string     `gorm:"size:10" json:"-"`
+	ExpiresAt           time.Time  `gorm:"not null;index" json:"expires_at"`
+	UsedAt              *time.Time `json:"used_at,omitempty"`
+}
+
+// TableName specifies the table name for OAuthAuthorizationCode model
+func (OAuthAuthorizationCode) TableName() string {
+	return "oauth_authorization_codes"
+}
+
+// ScopeList returns the granted scopes
+func (c *OAuthAuthorizationCode) ScopeList() []string {
+	return strings.Fields(c.Scopes)
+}
+
+// IsExpired checks if the code can no longer be exchanged
+func (c *OAuthAuthorizationCode) IsExpired() bool {
+	return time.Now().After(c.ExpiresAt)
+}
@@ -0,0 +1,69 @@
+package models
+
+import "strings"
+
+// OAuth grant types
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// OAuthClient represents a third-party application registered with the authorization server
+type OAuthClient struct {
+	BaseModel
+	ClientID         string `gorm:"uniqueIndex;not null;size:64" json:"client_id"`
+	ClientSecretHash string `gorm:"size:64" json:"-"` // Never expose in JSON
+	Name             string `gorm:"not null;size:100" json:"name"`
+	OwnerID          uint   `gorm:"not null;index" json:"owner_id"`
+	RedirectURIs     string `gorm:"type:text;not null" json:"-"`
+	GrantTypes       string `gorm:"not null;size:255" json:"-"`
+	Scopes           string `gorm:"not null;size:500" json:"-"`
+	IsConfidential   bool   `gorm:"default:true" json:"is_confidential"`
+	IsActive         bool   `gorm:"default:true" json:"is_active"`
+}
+
+// TableName specifies the table name for OAuthClient model
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+// RedirectURIList returns the registered redirect URIs
+func (c *OAuthClient) RedirectURIList() []string {
+	return strings.Fields(c.RedirectURIs)
+}
+
+// GrantTypeList returns the allowed grant types
+func (c *OAuthClient) GrantTypeList() []string {
+	return strings.Fields(c.GrantTypes)
+}
+
+// ScopeList returns the scopes the client may request
+func (c *OAuthClient) ScopeList() []string {
+	return strings.Fields(c.Scopes)
+}
+
+// HasRedirectURI checks if a redirect URI exactly matches a registered one
+func (c *OAuthClient) HasRedirectURI(uri string) bool {
+	return containsField(c.RedirectURIs, uri)
+}
+
+// AllowsGrantType checks if the client may use a grant type
+func (c *OAuthClient) AllowsGrantType(grantType string) bool {
+	return containsField(c.GrantTypes, grantType)
+}
+
+// AllowsScope checks if the client may request a scope
+func (c *OAuthClient) AllowsScope(scope string) bool {
+	return containsField(c.Scopes, scope)
+}
+
+// containsField checks if a space-separated list contains a value
+func containsField(list, value string) bool {
+	for _, field := range strings.Fields(list) {
+		if field == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package models
+
+import "strings"
+
+// OAuthConsent records the scopes a user has granted to a client
+type OAuthConsent struct {
+	BaseModel
+	UserID   uint   `gorm:"not null;uniqueIndex:idx_oauth_consents_user_client" json:"user_id"`
+	ClientID string `gorm:"not null;size:64;uniqueIndex:idx_oauth_consents_user_client" json:"client_id"`
+	Scopes   string `gorm:"not null;size:500" json:"scopes"`
+}
+
+// TableName specifies the table name for OAuthConsent model
+func (OAuthConsent) TableName() string {
+	return "oauth_consents"
+}
+
+// Covers checks if every requested scope was already granted
+func (c *OAuthConsent) Covers(scopes []string) bool {
+	for _, scope := range scopes {
+		if !containsField(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+// ScopeList returns the granted scopes
+func (c *OAuthConsent) ScopeList() []string {
+	return strings.Fields(c.Scopes)
+}
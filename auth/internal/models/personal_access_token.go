@@ -0,0 +1,85 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Personal access token scopes
+const (
+	ScopePostsWrite         = "posts:write"
+	ScopeCommentsWrite      = "comments:write"
+	ScopeCommentsModerate   = "comments:moderate"
+	ScopeRelationshipsWrite = "relationships:write"
+	ScopeReactionsWrite     = "reactions:write"
+	ScopeBookmarksWrite     = "bookmarks:write"
+	ScopeFollowsWrite       = "follows:write"
+	ScopeNotificationsWrite = "notifications:write"
+)
+
+// ValidScopes lists every scope a personal access token may be granted
+var ValidScopes = []string{
+	ScopePostsWrite,
+	ScopeCommentsWrite,
+	ScopeCommentsModerate,
+	ScopeRelationshipsWrite,
+	ScopeReactionsWrite,
+	ScopeBookmarksWrite,
+	ScopeFollowsWrite,
+	ScopeNotificationsWrite,
+}
+
+// PersonalAccessToken represents a long-lived, scoped token used for automation
+type PersonalAccessToken struct {
+	BaseModel
+	UserID      uint       `gorm:"not null;index" json:"user_id"`
+	Name        string     `gorm:"not null;size:100" json:"name"`
+	TokenPrefix string     `gorm:"not null;size:16" json:"token_prefix"`
+	TokenHash   string     `gorm:"uniqueIndex;not null;size:64" json:"-"` // Never expose in JSON
+	Scopes      string     `gorm:"not null;size:500" json:"-"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP  string     `gorm:"size:45" json:"last_used_ip,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TableName specifies the table name for PersonalAccessToken model
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+// ScopeList returns the granted scopes as a slice
+func (t *PersonalAccessToken) ScopeList() []string {
+	if t.Scopes == "" {
+		return []string{}
+	}
+	return strings.Fields(t.Scopes)
+}
+
+// HasScope checks if the token was granted the given scope
+func (t *PersonalAccessToken) HasScope(scope string) bool {
+	for _, s := range t.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValid checks if the token is valid (not expired and not revoked)
+func (t *PersonalAccessToken) IsValid() bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || time.Now().Before(*t.ExpiresAt)
+}
+
+// IsValidScope checks if a scope name is known
+func IsValidScope(scope string) bool {
+	for _, s := range ValidScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -13,6 +13,8 @@ type RefreshToken struct {
 	IsRevoked bool      `gorm:"default:false" json:"is_revoked"`
 	IPAddress string    `gorm:"size:45" json:"ip_address"`
 	UserAgent string    `gorm:"size:500" json:"user_agent"`
+	ClientID  string    `gorm:"size:64;index" json:"client_id,omitempty"` // Empty for first-party sessions
+	Scopes    string    `gorm:"size:500" json:"scopes,omitempty"`
 }
 
 // TableName specifies the table name for RefreshToken model
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// Seeded role names
+const (
+	RoleReader    = "reader"
+	RoleAuthor    = "author"
+	RoleModerator = "moderator"
+	RoleEditor    = "editor"
+	RoleAdmin     = "admin"
+)
+
+// Permissions that can be granted to roles
+const (
+	PermissionCommentsWrite    = "comments:write"
+	PermissionCommentsModerate = "comments:moderate"
+	PermissionPostsWrite       = "posts:write"
+	PermissionPostsEditAny     = "posts:edit_any"
+	PermissionUsersManageRoles = "users:manage_roles"
+	PermissionInvitesManage    = "invites:manage"
+	PermissionAuditRead        = "audit:read"
+	PermissionJobsRead         = "jobs:read"
+	PermissionUsersImpersonate = "users:impersonate"
+)
+
+// Role is a named set of permissions assigned to users
+type Role struct {
+	ID          uint         `gorm:"primarykey" json:"id"`
+	Name        string       `gorm:"uniqueIndex;not null;size:20" json:"name"`
+	Description string       `gorm:"size:255" json:"description"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// TableName specifies the table name for Role model
+func (Role) TableName() string {
+	return "roles"
+}
+
+// PermissionNames returns the names of the role's permissions
+func (r *Role) PermissionNames() []string {
+	names := make([]string, len(r.Permissions))
+	for i, p := range r.Permissions {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// Permission is an action a role can grant
+type Permission struct {
+	ID          uint      `gorm:"primarykey" json:"-"`
+	Name        string    `gorm:"uniqueIndex;not null;size:50" json:"name"`
+	Description string    `gorm:"size:255" json:"description"`
+	CreatedAt   time.Time `json:"-"`
+}
+
+// TableName specifies the table name for Permission model
+func (Permission) TableName() string {
+	return "permissions"
+}
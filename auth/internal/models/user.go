@@ -15,8 +15,11 @@ type User struct {
 	AvatarURL     string     `gorm:"size:500" json:"avatar_url"`
 	EmailVerified bool       `gorm:"default:false" json:"email_verified"`
 	IsActive      bool       `gorm:"default:true" json:"is_active"`
-	Role          string     `gorm:"default:'user';size:20" json:"role"` // user, admin
+	Role          string     `gorm:"not null;size:20" json:"role"` // reader, author, moderator, editor or admin
 	LastLoginAt   *time.Time `json:"last_login_at,omitempty"`
+
+	// DeletionScheduledAt is set while a self-service account deletion is pending
+	DeletionScheduledAt *time.Time `gorm:"index" json:"deletion_scheduled_at,omitempty"`
 }
 
 // TableName specifies the table name for User model
@@ -33,6 +36,8 @@ type PublicUser struct {
 	Bio         string `json:"bio"`
 	AvatarURL   string `json:"avatar_url"`
 	Role        string `json:"role"`
+
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
 }
 
 // ToPublic converts User to PublicUser
@@ -45,5 +50,29 @@ func (u *User) ToPublic() PublicUser {
 		Bio:         u.Bio,
 		AvatarURL:   u.AvatarURL,
 		Role:        u.Role,
+
+		DeletionScheduledAt: u.DeletionScheduledAt,
+	}
+}
+
+// PublicProfile is the profile shown to other users; it never includes the email address
+type PublicProfile struct {
+	ID          uint      `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio"`
+	AvatarURL   string    `json:"avatar_url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ToProfile converts User to PublicProfile
+func (u *User) ToProfile() PublicProfile {
+	return PublicProfile{
+		ID:          u.ID,
+		Username:    u.Username,
+		DisplayName: u.DisplayName,
+		Bio:         u.Bio,
+		AvatarURL:   u.AvatarURL,
+		CreatedAt:   u.CreatedAt,
 	}
 }
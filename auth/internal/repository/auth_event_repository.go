@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuthEventFilter narrows an audit log query. Zero values are ignored.
+type AuthEventFilter struct {
+	UserID     uint
+	EventType  string
+	IPAddress  string
+	Identifier string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+	Offset     int
+}
+
+// AuthEventRepository defines the interface for audit log operations
+type AuthEventRepository interface {
+	Create(event *models.AuthEvent) error
+	List(filter AuthEventFilter) ([]models.AuthEvent, int64, error)
+	DeleteBefore(cutoff time.Time) (int64, error)
+}
+
+type authEventRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthEventRepository creates a new audit log repository
+func NewAuthEventRepository(db *gorm.DB) AuthEventRepository {
+	return &authEventRepository{db: db}
+}
+
+// Create appends an event to the audit log
+func (r *authEventRepository) Create(event *models.AuthEvent) error {
+	if err := r.db.Create(event).Error; err != nil {
+		return fmt.Errorf("failed to create auth event: %w", err)
+	}
+	return nil
+}
+
+// List finds events matching the filter, newest first, along with the total match count
+func (r *authEventRepository) List(filter AuthEventFilter) ([]models.AuthEvent, int64, error) {
+	query := r.db.Model(&models.AuthEvent{})
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.IPAddress != "" {
+		query = query.Where("ip_address = ?", filter.IPAddress)
+	}
+	if filter.Identifier != "" {
+		query = query.Where("LOWER(identifier) = LOWER(?)", filter.Identifier)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("created_at < ?", filter.Until)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count auth events: %w", err)
+	}
+
+	var events []models.AuthEvent
+	if err := query.Order("created_at DESC, id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&events).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to find auth events: %w", err)
+	}
+
+	return events, total, nil
+}
+
+// DeleteBefore removes events older than the cutoff and returns how many were removed
+func (r *authEventRepository) DeleteBefore(cutoff time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", cutoff).Delete(&models.AuthEvent{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete auth events: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DataExportRepository defines the interface for data export operations
+type DataExportRepository interface {
+	Create(export *models.DataExport) error
+	FindByID(id uint) (*models.DataExport, error)
+	FindByUserID(userID uint) ([]models.DataExport, error)
+	FindByStatus(status string, limit int) ([]models.DataExport, error)
+	FindExpired(before time.Time, limit int) ([]models.DataExport, error)
+	ClaimPending(id uint) (bool, error)
+	Update(export *models.DataExport) error
+	Delete(id uint) error
+	SaveArchive(archive *models.DataExportArchive) error
+	FindArchive(exportID uint) (*models.DataExportArchive, error)
+}
+
+type dataExportRepository struct {
+	db *gorm.DB
+}
+
+// NewDataExportRepository creates a new data export repository
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+// Create creates a new data export request
+func (r *dataExportRepository) Create(export *models.DataExport) error {
+	if err := r.db.Create(export).Error; err != nil {
+		return fmt.Errorf("failed to create data export: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds a data export by ID
+func (r *dataExportRepository) FindByID(id uint) (*models.DataExport, error) {
+	var export models.DataExport
+	if err := r.db.First(&export, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("data export not found")
+		}
+		return nil, fmt.Errorf("failed to find data export: %w", err)
+	}
+	return &export, nil
+}
+
+// FindByUserID finds all data exports for a user, newest first
+func (r *dataExportRepository) FindByUserID(userID uint) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	if err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&exports).Error; err != nil {
+		return nil, fmt.Errorf("failed to find data exports: %w", err)
+	}
+	return exports, nil
+}
+
+// FindByStatus finds data exports in a status, oldest first
+func (r *dataExportRepository) FindByStatus(status string, limit int) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	if err := r.db.Where("status = ?", status).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&exports).Error; err != nil {
+		return nil, fmt.Errorf("failed to find data exports: %w", err)
+	}
+	return exports, nil
+}
+
+// FindExpired finds data exports whose archive has expired
+func (r *dataExportRepository) FindExpired(before time.Time, limit int) ([]models.DataExport, error) {
+	var exports []models.DataExport
+	if err := r.db.Where("expires_at IS NOT NULL AND expires_at < ?", before).
+		Limit(limit).
+		Find(&exports).Error; err != nil {
+		return nil, fmt.Errorf("failed to find expired data exports: %w", err)
+	}
+	return exports, nil
+}
+
+// ClaimPending atomically moves a pending export to processing.
+// Returns false if another worker claimed it first.
+func (r *dataExportRepository) ClaimPending(id uint) (bool, error) {
+	result := r.db.Model(&models.DataExport{}).
+		Where("id = ? AND status = ?", id, models.DataExportPending).
+		Update("status", models.DataExportProcessing)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to claim data export: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Update updates a data export
+func (r *dataExportRepository) Update(export *models.DataExport) error {
+	if err := r.db.Save(export).Error; err != nil {
+		return fmt.Errorf("failed to update data export: %w", err)
+	}
+	return nil
+}
+
+// Delete permanently deletes a data export record
+func (r *dataExportRepository) Delete(id uint) error {
+	if err := r.db.Unscoped().Delete(&models.DataExport{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete data export: %w", err)
+	}
+	return nil
+}
+
+// SaveArchive stores a data export's archive, replacing any earlier one
+func (r *dataExportRepository) SaveArchive(archive *models.DataExportArchive) error {
+	if err := r.db.Save(archive).Error; err != nil {
+		return fmt.Errorf("failed to save data export archive: %w", err)
+	}
+	return nil
+}
+
+// FindArchive finds a data export's archive
+func (r *dataExportRepository) FindArchive(exportID uint) (*models.DataExportArchive, error) {
+	var archive models.DataExportArchive
+	if err := r.db.Where("export_id = ?", exportID).First(&archive).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("data export archive not found")
+		}
+		return nil, fmt.Errorf("failed to find data export archive: %w", err)
+	}
+	return &archive, nil
+}
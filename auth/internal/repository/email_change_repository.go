@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EmailChangeRepository defines the interface for email change request operations
+type EmailChangeRepository interface {
+	Create(request *models.EmailChangeRequest) error
+	FindByHash(tokenHash string) (*models.EmailChangeRequest, error)
+	MarkConfirmed(id uint) (bool, error)
+	DeleteByUserID(userID uint) error
+}
+
+type emailChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailChangeRepository creates a new email change repository
+func NewEmailChangeRepository(db *gorm.DB) EmailChangeRepository {
+	return &emailChangeRepository{db: db}
+}
+
+// Create creates a new email change request
+func (r *emailChangeRepository) Create(request *models.EmailChangeRequest) error {
+	if err := r.db.Create(request).Error; err != nil {
+		return fmt.Errorf("failed to create email change request: %w", err)
+	}
+	return nil
+}
+
+// FindByHash finds an email change request by token hash
+func (r *emailChangeRepository) FindByHash(tokenHash string) (*models.EmailChangeRequest, error) {
+	var request models.EmailChangeRequest
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&request).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("email change request not found")
+		}
+		return nil, fmt.Errorf("failed to find email change request: %w", err)
+	}
+	return &request, nil
+}
+
+// MarkConfirmed atomically marks a request as confirmed.
+// Returns false if it was already confirmed, so a token can only be used once.
+func (r *emailChangeRepository) MarkConfirmed(id uint) (bool, error) {
+	result := r.db.Model(&models.EmailChangeRequest{}).
+		Where("id = ? AND confirmed_at IS NULL", id).
+		Update("confirmed_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to confirm email change request: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteByUserID permanently deletes a user's unconfirmed email change requests
+func (r *emailChangeRepository) DeleteByUserID(userID uint) error {
+	if err := r.db.Unscoped().Where("user_id = ? AND confirmed_at IS NULL", userID).
+		Delete(&models.EmailChangeRequest{}).Error; err != nil {
+		return fmt.Errorf("failed to delete email change requests: %w", err)
+	}
+	return nil
+}
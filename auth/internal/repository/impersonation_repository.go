@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ImpersonationFilter narrows an impersonation session query. Zero values are ignored.
+type ImpersonationFilter struct {
+	ActorID  uint
+	TargetID uint
+	Limit    int
+	Offset   int
+}
+
+// ImpersonationRepository defines the interface for impersonation session operations
+type ImpersonationRepository interface {
+	Create(session *models.ImpersonationSession) error
+	List(filter ImpersonationFilter) ([]models.ImpersonationSession, int64, error)
+}
+
+type impersonationRepository struct {
+	db *gorm.DB
+}
+
+// NewImpersonationRepository creates a new impersonation session repository
+func NewImpersonationRepository(db *gorm.DB) ImpersonationRepository {
+	return &impersonationRepository{db: db}
+}
+
+// Create records a new impersonation session
+func (r *impersonationRepository) Create(session *models.ImpersonationSession) error {
+	if err := r.db.Create(session).Error; err != nil {
+		return fmt.Errorf("failed to create impersonation session: %w", err)
+	}
+	return nil
+}
+
+// List finds impersonation sessions matching the filter, newest first, along with the total count
+func (r *impersonationRepository) List(filter ImpersonationFilter) ([]models.ImpersonationSession, int64, error) {
+	query := r.db.Model(&models.ImpersonationSession{})
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count impersonation sessions: %w", err)
+	}
+
+	var sessions []models.ImpersonationSession
+	if err := query.Order("created_at DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&sessions).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list impersonation sessions: %w", err)
+	}
+	return sessions, total, nil
+}
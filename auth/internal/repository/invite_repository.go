@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InviteRepository defines the interface for invite operations
+type InviteRepository interface {
+	Create(invite *models.Invite) error
+	FindByID(id uint) (*models.Invite, error)
+	FindByCodeHash(codeHash string) (*models.Invite, error)
+	List(limit, offset int) ([]models.Invite, int64, error)
+	Redeem(id uint) (bool, error)
+	Release(id uint) error
+	Revoke(id uint) error
+}
+
+type inviteRepository struct {
+	db *gorm.DB
+}
+
+// NewInviteRepository creates a new invite repository
+func NewInviteRepository(db *gorm.DB) InviteRepository {
+	return &inviteRepository{db: db}
+}
+
+// Create creates a new invite
+func (r *inviteRepository) Create(invite *models.Invite) error {
+	if err := r.db.Create(invite).Error; err != nil {
+		return fmt.Errorf("failed to create invite: %w", err)
+	}
+	return nil
+}
+
+// FindByID finds an invite by ID
+func (r *inviteRepository) FindByID(id uint) (*models.Invite, error) {
+	var invite models.Invite
+	if err := r.db.First(&invite, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("invite not found")
+		}
+		return nil, fmt.Errorf("failed to find invite: %w", err)
+	}
+	return &invite, nil
+}
+
+// FindByCodeHash finds an invite by the hash of its code
+func (r *inviteRepository) FindByCodeHash(codeHash string) (*models.Invite, error) {
+	var invite models.Invite
+	if err := r.db.Where("code_hash = ?", codeHash).First(&invite).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("invite not found")
+		}
+		return nil, fmt.Errorf("failed to find invite: %w", err)
+	}
+	return &invite, nil
+}
+
+// List finds invites, newest first, along with the total count
+func (r *inviteRepository) List(limit, offset int) ([]models.Invite, int64, error) {
+	var total int64
+	if err := r.db.Model(&models.Invite{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count invites: %w", err)
+	}
+
+	var invites []models.Invite
+	if err := r.db.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&invites).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list invites: %w", err)
+	}
+	return invites, total, nil
+}
+
+// Redeem atomically uses up one of the invite's uses.
+// Returns false if the invite is revoked, expired or fully used.
+func (r *inviteRepository) Redeem(id uint) (bool, error) {
+	result := r.db.Model(&models.Invite{}).
+		Where("id = ? AND revoked_at IS NULL AND use_count < max_uses AND expires_at > ?", id, time.Now()).
+		Update("use_count", gorm.Expr("use_count + 1"))
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to redeem invite: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Release gives back a use taken by Redeem when registration fails afterwards
+func (r *inviteRepository) Release(id uint) error {
+	if err := r.db.Model(&models.Invite{}).
+		Where("id = ? AND use_count > 0", id).
+		Update("use_count", gorm.Expr("use_count - 1")).Error; err != nil {
+		return fmt.Errorf("failed to release invite: %w", err)
+	}
+	return nil
+}
+
+// Revoke marks an invite as revoked
+func (r *inviteRepository) Revoke(id uint) error {
+	if err := r.db.Model(&models.Invite{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to revoke invite: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// KnownDeviceRepository defines the interface for known device operations
+type KnownDeviceRepository interface {
+	Touch(device *models.KnownDevice) error
+	CountByUser(userID uint) (int64, error)
+	HasFingerprint(userID uint, fingerprint string) (bool, error)
+	HasIPRange(userID uint, ipRange string) (bool, error)
+	HasCountry(userID uint, country string) (bool, error)
+	Delete(userID uint, fingerprint, ipRange string) error
+}
+
+type knownDeviceRepository struct {
+	db *gorm.DB
+}
+
+// NewKnownDeviceRepository creates a new known device repository
+func NewKnownDeviceRepository(db *gorm.DB) KnownDeviceRepository {
+	return &knownDeviceRepository{db: db}
+}
+
+// Touch records a device, or updates its last seen time if it is already known
+func (r *knownDeviceRepository) Touch(device *models.KnownDevice) error {
+	now := time.Now()
+	device.FirstSeenAt = now
+	device.LastSeenAt = now
+
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "fingerprint"}, {Name: "ip_range"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_seen_at", "country"}),
+	}).Create(device).Error; err != nil {
+		return fmt.Errorf("failed to record known device: %w", err)
+	}
+	return nil
+}
+
+// CountByUser counts the devices a user has signed in from
+func (r *knownDeviceRepository) CountByUser(userID uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.KnownDevice{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count known devices: %w", err)
+	}
+	return count, nil
+}
+
+// HasFingerprint checks if the user has signed in from the device before, on any network
+func (r *knownDeviceRepository) HasFingerprint(userID uint, fingerprint string) (bool, error) {
+	return r.exists("user_id = ? AND fingerprint = ?", userID, fingerprint)
+}
+
+// HasIPRange checks if the user has signed in from the network before, on any device
+func (r *knownDeviceRepository) HasIPRange(userID uint, ipRange string) (bool, error) {
+	return r.exists("user_id = ? AND ip_range = ?", userID, ipRange)
+}
+
+// HasCountry checks if the user has signed in from the country before
+func (r *knownDeviceRepository) HasCountry(userID uint, country string) (bool, error) {
+	return r.exists("user_id = ? AND country = ?", userID, country)
+}
+
+// Delete forgets a device on one network
+func (r *knownDeviceRepository) Delete(userID uint, fingerprint, ipRange string) error {
+	if err := r.db.Where("user_id = ? AND fingerprint = ? AND ip_range = ?", userID, fingerprint, ipRange).
+		Delete(&models.KnownDevice{}).Error; err != nil {
+		return fmt.Errorf("failed to delete known device: %w", err)
+	}
+	return nil
+}
+
+// exists checks if any known device matches the condition
+func (r *knownDeviceRepository) exists(query string, args ...interface{}) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.KnownDevice{}).Where(query, args...).Limit(1).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check known devices: %w", err)
+	}
+	return count > 0, nil
+}
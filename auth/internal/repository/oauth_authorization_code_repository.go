@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OAuthAuthorizationCodeRepository defines the interface for authorization code operations
+type OAuthAuthorizationCodeRepository interface {
+	Create(code *models.OAuthAuthorizationCode) error
+	FindByHash(codeHash string) (*models.OAuthAuthorizationCode, error)
+	MarkUsed(id uint) (bool, error)
+	DeleteExpired() (int64, error)
+}
+
+type oauthAuthorizationCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthAuthorizationCodeRepository creates a new authorization code repository
+func NewOAuthAuthorizationCodeRepository(db *gorm.DB) OAuthAuthorizationCodeRepository {
+	return &oauthAuthorizationCodeRepository{db: db}
+}
+
+// Create creates a new authorization code
+func (r *oauthAuthorizationCodeRepository) Create(code *models.OAuthAuthorizationCode) error {
+	if err := r.db.Create(code).Error; err != nil {
+		return fmt.Errorf("failed to create authorization code: %w", err)
+	}
+	return nil
+}
+
+// FindByHash finds an authorization code by its hash
+func (r *oauthAuthorizationCodeRepository) FindByHash(codeHash string) (*models.OAuthAuthorizationCode, error) {
+	var code models.OAuthAuthorizationCode
+	if err := r.db.Where("code_hash = ?", codeHash).First(&code).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("authorization code not found")
+		}
+		return nil, fmt.Errorf("failed to find authorization code: %w", err)
+	}
+	return &code, nil
+}
+
+// MarkUsed atomically marks a code as used. Returns false if it was already used.
+func (r *oauthAuthorizationCodeRepository) MarkUsed(id uint) (bool, error) {
+	result := r.db.Model(&models.OAuthAuthorizationCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark authorization code as used: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteExpired deletes all expired authorization codes and returns how many were deleted
+func (r *oauthAuthorizationCodeRepository) DeleteExpired() (int64, error) {
+	result := r.db.Where("expires_at < ?", time.Now()).Delete(&models.OAuthAuthorizationCode{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired authorization codes: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
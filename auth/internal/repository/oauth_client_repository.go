@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// OAuthClientRepository defines the interface for OAuth client operations
+type OAuthClientRepository interface {
+	Create(client *models.OAuthClient) error
+	FindByClientID(clientID string) (*models.OAuthClient, error)
+	FindByOwnerID(ownerID uint) ([]models.OAuthClient, error)
+	Update(client *models.OAuthClient) error
+	Delete(id uint) error
+}
+
+type oauthClientRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthClientRepository creates a new OAuth client repository
+func NewOAuthClientRepository(db *gorm.DB) OAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+// Create creates a new OAuth client
+func (r *oauthClientRepository) Create(client *models.OAuthClient) error {
+	if err := r.db.Create(client).Error; err != nil {
+		return fmt.Errorf("failed to create oauth client: %w", err)
+	}
+	return nil
+}
+
+// FindByClientID finds an OAuth client by its public client ID
+func (r *oauthClientRepository) FindByClientID(clientID string) (*models.OAuthClient, error) {
+	var client models.OAuthClient
+	if err := r.db.Where("client_id = ?", clientID).First(&client).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("oauth client not found")
+		}
+		return nil, fmt.Errorf("failed to find oauth client: %w", err)
+	}
+	return &client, nil
+}
+
+// FindByOwnerID finds all OAuth clients registered by a user
+func (r *oauthClientRepository) FindByOwnerID(ownerID uint) ([]models.OAuthClient, error) {
+	var clients []models.OAuthClient
+	if err := r.db.Where("owner_id = ?", ownerID).
+		Order("created_at DESC").
+		Find(&clients).Error; err != nil {
+		return nil, fmt.Errorf("failed to find oauth clients: %w", err)
+	}
+	return clients, nil
+}
+
+// Update updates an OAuth client
+func (r *oauthClientRepository) Update(client *models.OAuthClient) error {
+	if err := r.db.Save(client).Error; err != nil {
+		return fmt.Errorf("failed to update oauth client: %w", err)
+	}
+	return nil
+}
+
+// Delete soft deletes an OAuth client
+func (r *oauthClientRepository) Delete(id uint) error {
+	if err := r.db.Delete(&models.OAuthClient{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete oauth client: %w", err)
+	}
+	return nil
+}
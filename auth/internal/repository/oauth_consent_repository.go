@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// OAuthConsentRepository defines the interface for OAuth consent operations
+type OAuthConsentRepository interface {
+	Find(userID uint, clientID string) (*models.OAuthConsent, error)
+	Save(consent *models.OAuthConsent) error
+	Delete(userID uint, clientID string) error
+	FindByUserID(userID uint) ([]models.OAuthConsent, error)
+}
+
+type oauthConsentRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthConsentRepository creates a new OAuth consent repository
+func NewOAuthConsentRepository(db *gorm.DB) OAuthConsentRepository {
+	return &oauthConsentRepository{db: db}
+}
+
+// Find finds the consent a user has given to a client
+func (r *oauthConsentRepository) Find(userID uint, clientID string) (*models.OAuthConsent, error) {
+	var consent models.OAuthConsent
+	if err := r.db.Where("user_id = ? AND client_id = ?", userID, clientID).First(&consent).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("consent not found")
+		}
+		return nil, fmt.Errorf("failed to find consent: %w", err)
+	}
+	return &consent, nil
+}
+
+// Save creates or updates a consent
+func (r *oauthConsentRepository) Save(consent *models.OAuthConsent) error {
+	if err := r.db.Save(consent).Error; err != nil {
+		return fmt.Errorf("failed to save consent: %w", err)
+	}
+	return nil
+}
+
+// Delete permanently removes a consent so the user is asked again
+func (r *oauthConsentRepository) Delete(userID uint, clientID string) error {
+	if err := r.db.Unscoped().Where("user_id = ? AND client_id = ?", userID, clientID).
+		Delete(&models.OAuthConsent{}).Error; err != nil {
+		return fmt.Errorf("failed to delete consent: %w", err)
+	}
+	return nil
+}
+
+// FindByUserID finds all consents a user has given
+func (r *oauthConsentRepository) FindByUserID(userID uint) ([]models.OAuthConsent, error) {
+	var consents []models.OAuthConsent
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&consents).Error; err != nil {
+		return nil, fmt.Errorf("failed to find consents: %w", err)
+	}
+	return consents, nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PersonalAccessTokenRepository defines the interface for personal access token operations
+type PersonalAccessTokenRepository interface {
+	Create(token *models.PersonalAccessToken) error
+	FindByHash(tokenHash string) (*models.PersonalAccessToken, error)
+	FindByID(id uint) (*models.PersonalAccessToken, error)
+	FindByUserID(userID uint) ([]models.PersonalAccessToken, error)
+	Revoke(id uint) error
+	RevokeAllUserTokens(userID uint) error
+	UpdateLastUsed(id uint, ipAddress string) error
+}
+
+type personalAccessTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalAccessTokenRepository creates a new personal access token repository
+func NewPersonalAccessTokenRepository(db *gorm.DB) PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db: db}
+}
+
+// Create creates a new personal access token
+func (r *personalAccessTokenRepository) Create(token *models.PersonalAccessToken) error {
+	if err := r.db.Create(token).Error; err != nil {
+		return fmt.Errorf("failed to create personal access token: %w", err)
+	}
+	return nil
+}
+
+// FindByHash finds a personal access token by its hash
+func (r *personalAccessTokenRepository) FindByHash(tokenHash string) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("personal access token not found")
+		}
+		return nil, fmt.Errorf("failed to find personal access token: %w", err)
+	}
+	return &token, nil
+}
+
+// FindByID finds a personal access token by ID
+func (r *personalAccessTokenRepository) FindByID(id uint) (*models.PersonalAccessToken, error) {
+	var token models.PersonalAccessToken
+	if err := r.db.First(&token, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("personal access token not found")
+		}
+		return nil, fmt.Errorf("failed to find personal access token: %w", err)
+	}
+	return &token, nil
+}
+
+// FindByUserID finds all personal access tokens for a user, including revoked ones
+func (r *personalAccessTokenRepository) FindByUserID(userID uint) ([]models.PersonalAccessToken, error) {
+	var tokens []models.PersonalAccessToken
+	if err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&tokens).Error; err != nil {
+		return nil, fmt.Errorf("failed to find personal access tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Revoke revokes a personal access token
+func (r *personalAccessTokenRepository) Revoke(id uint) error {
+	if err := r.db.Model(&models.PersonalAccessToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to revoke personal access token: %w", err)
+	}
+	return nil
+}
+
+// RevokeAllUserTokens revokes all personal access tokens for a user
+func (r *personalAccessTokenRepository) RevokeAllUserTokens(userID uint) error {
+	if err := r.db.Model(&models.PersonalAccessToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("failed to revoke personal access tokens: %w", err)
+	}
+	return nil
+}
+
+// UpdateLastUsed records when and from where a token was last used
+func (r *personalAccessTokenRepository) UpdateLastUsed(id uint, ipAddress string) error {
+	if err := r.db.Model(&models.PersonalAccessToken{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"last_used_at": time.Now(),
+			"last_used_ip": ipAddress,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update token usage: %w", err)
+	}
+	return nil
+}
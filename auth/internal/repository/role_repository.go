@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RoleRepository defines the interface for role operations
+type RoleRepository interface {
+	FindAll() ([]models.Role, error)
+	FindByName(name string) (*models.Role, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository creates a new role repository
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+// FindAll finds every role along with its permissions
+func (r *roleRepository) FindAll() ([]models.Role, error) {
+	var roles []models.Role
+	if err := r.db.Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("permissions.name")
+	}).Order("id").Find(&roles).Error; err != nil {
+		return nil, fmt.Errorf("failed to find roles: %w", err)
+	}
+	return roles, nil
+}
+
+// FindByName finds a role and its permissions by name
+func (r *roleRepository) FindByName(name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Preload("Permissions").Where("name = ?", name).First(&role).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fmt.Errorf("role not found")
+		}
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+	return &role, nil
+}
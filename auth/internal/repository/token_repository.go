@@ -14,9 +14,11 @@ type TokenRepository interface {
 	FindByToken(token string) (*models.RefreshToken, error)
 	FindByUserID(userID uint) ([]models.RefreshToken, error)
 	RevokeToken(token string) error
+	RevokeTokenByID(userID, id uint) error
 	RevokeAllUserTokens(userID uint) error
-	DeleteExpired() error
-	CleanupRevokedTokens(olderThan time.Duration) error
+	RevokeClientTokens(userID uint, clientID string) error
+	DeleteExpired() (int64, error)
+	CleanupRevokedTokens(olderThan time.Duration) (int64, error)
 }
 
 type tokenRepository struct {
@@ -69,6 +71,16 @@ func (r *tokenRepository) RevokeToken(token string) error {
 	return nil
 }
 
+// RevokeTokenByID revokes one of a user's refresh tokens by its ID
+func (r *tokenRepository) RevokeTokenByID(userID, id uint) error {
+	if err := r.db.Model(&models.RefreshToken{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("is_revoked", true).Error; err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
 // RevokeAllUserTokens revokes all refresh tokens for a user
 func (r *tokenRepository) RevokeAllUserTokens(userID uint) error {
 	if err := r.db.Model(&models.RefreshToken{}).
@@ -79,21 +91,31 @@ func (r *tokenRepository) RevokeAllUserTokens(userID uint) error {
 	return nil
 }
 
-// DeleteExpired deletes all expired tokens
-func (r *tokenRepository) DeleteExpired() error {
-	if err := r.db.Where("expires_at < ?", time.Now()).
-		Delete(&models.RefreshToken{}).Error; err != nil {
-		return fmt.Errorf("failed to delete expired tokens: %w", err)
+// RevokeClientTokens revokes all refresh tokens a user granted to an OAuth client
+func (r *tokenRepository) RevokeClientTokens(userID uint, clientID string) error {
+	if err := r.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND client_id = ? AND is_revoked = false", userID, clientID).
+		Update("is_revoked", true).Error; err != nil {
+		return fmt.Errorf("failed to revoke client tokens: %w", err)
 	}
 	return nil
 }
 
-// CleanupRevokedTokens deletes revoked tokens older than specified duration
-func (r *tokenRepository) CleanupRevokedTokens(olderThan time.Duration) error {
+// DeleteExpired deletes all expired tokens and returns how many were deleted
+func (r *tokenRepository) DeleteExpired() (int64, error) {
+	result := r.db.Where("expires_at < ?", time.Now()).Delete(&models.RefreshToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired tokens: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// CleanupRevokedTokens deletes revoked tokens older than specified duration and returns how many were deleted
+func (r *tokenRepository) CleanupRevokedTokens(olderThan time.Duration) (int64, error) {
 	cutoffTime := time.Now().Add(-olderThan)
-	if err := r.db.Where("is_revoked = true AND updated_at < ?", cutoffTime).
-		Delete(&models.RefreshToken{}).Error; err != nil {
-		return fmt.Errorf("failed to cleanup revoked tokens: %w", err)
+	result := r.db.Where("is_revoked = true AND updated_at < ?", cutoffTime).Delete(&models.RefreshToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to cleanup revoked tokens: %w", result.Error)
 	}
-	return nil
+	return result.RowsAffected, nil
 }
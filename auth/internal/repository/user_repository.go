@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"inkstack-auth/internal/models"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -15,12 +16,16 @@ type UserRepository interface {
 	FindByEmail(email string) (*models.User, error)
 	FindByUsername(username string) (*models.User, error)
 	FindByEmailOrUsername(identifier string) (*models.User, error)
+	FindActiveByIDs(ids []uint) ([]models.User, error)
+	FindActiveByUsernames(usernames []string) ([]models.User, error)
 	Update(user *models.User) error
 	Delete(id uint) error
 	List(limit, offset int) ([]models.User, error)
 	Count() (int64, error)
 	ExistsByEmail(email string) (bool, error)
 	ExistsByUsername(username string) (bool, error)
+	FindDueForDeletion(before time.Time, limit int) ([]models.User, error)
+	HardDelete(id uint) error
 }
 
 type userRepository struct {
@@ -141,3 +146,52 @@ func (r *userRepository) ExistsByUsername(username string) (bool, error) {
 	}
 	return count > 0, nil
 }
+
+// FindActiveByIDs finds the active users among the given IDs, in ID order
+func (r *userRepository) FindActiveByIDs(ids []uint) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := r.db.Where("id IN ? AND is_active = ?", ids, true).Order("id ASC").Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+	return users, nil
+}
+
+// FindActiveByUsernames finds the active users among the given usernames (case-insensitive), in ID order
+func (r *userRepository) FindActiveByUsernames(usernames []string) ([]models.User, error) {
+	var users []models.User
+	if len(usernames) == 0 {
+		return users, nil
+	}
+	lowered := make([]string, len(usernames))
+	for i, username := range usernames {
+		lowered[i] = strings.ToLower(username)
+	}
+	if err := r.db.Where("LOWER(username) IN ? AND is_active = ?", lowered, true).Order("id ASC").Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find users: %w", err)
+	}
+	return users, nil
+}
+
+// FindDueForDeletion finds users whose scheduled deletion time has passed
+func (r *userRepository) FindDueForDeletion(before time.Time, limit int) ([]models.User, error) {
+	var users []models.User
+	if err := r.db.Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", before).
+		Order("deletion_scheduled_at ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to find users due for deletion: %w", err)
+	}
+	return users, nil
+}
+
+// HardDelete permanently deletes a user. Tokens, consents, clients and exports
+// are removed by ON DELETE CASCADE.
+func (r *userRepository) HardDelete(id uint) error {
+	if err := r.db.Unscoped().Delete(&models.User{}, id).Error; err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"inkstack-auth/internal/database"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// JobFunc performs one run of a job and returns how many rows or keys it purged
+type JobFunc func(ctx context.Context) (int64, error)
+
+// Job is a maintenance task run every Interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Timeout  time.Duration // Longest a run may take; defaults to Interval
+	Run      JobFunc
+}
+
+// JobStats describes a job's runs on this instance since it started
+type JobStats struct {
+	Name         string     `json:"name"`
+	Interval     string     `json:"interval"`
+	Running      bool       `json:"running"`
+	Runs         int64      `json:"runs"`
+	Failures     int64      `json:"failures"`
+	Skipped      int64      `json:"skipped"` // Ticks where another replica held the lock
+	Purged       int64      `json:"purged"`  // Total rows or keys purged
+	LastPurged   int64      `json:"last_purged"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+}
+
+// Scheduler runs registered jobs on their intervals. Each run takes a Redis lock
+// that is held until one interval after the run started, so a job runs at most once
+// per interval across all replicas and runs never overlap. A failed run releases the
+// lock so another replica can retry sooner.
+type Scheduler struct {
+	owner string
+	jobs  []Job
+	stats map[string]*JobStats
+	mu    sync.Mutex
+	wg    sync.WaitGroup
+}
+
+// New creates a scheduler with no jobs
+func New() *Scheduler {
+	return &Scheduler{
+		owner: instanceID(),
+		stats: make(map[string]*JobStats),
+	}
+}
+
+// Register adds a job. Jobs must be registered before Start.
+func (s *Scheduler) Register(job Job) {
+	if job.Timeout < job.Interval {
+		job.Timeout = job.Interval
+	}
+	s.jobs = append(s.jobs, job)
+	s.stats[job.Name] = &JobStats{Name: job.Name, Interval: job.Interval.String()}
+}
+
+// Start runs every registered job in its own goroutine until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+	log.Printf("Scheduler started with %d jobs", len(s.jobs))
+}
+
+// Wait blocks until every job loop has returned, or the timeout passes
+func (s *Scheduler) Wait(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Println("Scheduler: timed out waiting for jobs to stop")
+	}
+}
+
+// Stats returns a snapshot of every job's stats, sorted by name
+func (s *Scheduler) Stats() []JobStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]JobStats, 0, len(s.stats))
+	for _, st := range s.stats {
+		stats = append(stats, *st)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// loop runs a job immediately and then on every tick
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, job)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a job if no replica has run it within the last interval
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	lockName := "job:" + job.Name
+	acquired, err := database.AcquireLock(ctx, lockName, s.owner, job.Timeout)
+	if err != nil {
+		log.Printf("Scheduler: failed to lock job %s: %v", job.Name, err)
+		return
+	}
+	if !acquired {
+		s.update(job.Name, func(st *JobStats) { st.Skipped++ })
+		return
+	}
+
+	// A run may not outlast its lock, or another replica could start it concurrently
+	runCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+	defer cancel()
+
+	s.update(job.Name, func(st *JobStats) { st.Running = true })
+	started := time.Now()
+	purged, err := job.Run(runCtx)
+	duration := time.Since(started)
+
+	s.update(job.Name, func(st *JobStats) {
+		st.Running = false
+		st.Runs++
+		st.Purged += purged
+		st.LastPurged = purged
+		st.LastRunAt = &started
+		st.LastDuration = duration.String()
+		st.LastError = ""
+		if err != nil {
+			st.Failures++
+			st.LastError = err.Error()
+		}
+	})
+
+	if err != nil {
+		log.Printf("Scheduler: job %s failed after %s: %v", job.Name, duration, err)
+	} else if purged > 0 {
+		log.Printf("Scheduler: job %s purged %d in %s", job.Name, purged, duration)
+	}
+
+	// Use a fresh context so the lock is still updated during shutdown
+	lockCtx, cancelLock := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelLock()
+
+	remaining := job.Interval - duration
+	if err != nil || remaining <= 0 {
+		err = database.ReleaseLock(lockCtx, lockName, s.owner)
+	} else {
+		err = database.ExpireLockAfter(lockCtx, lockName, s.owner, remaining)
+	}
+	if err != nil {
+		log.Printf("Scheduler: failed to update lock for job %s: %v", job.Name, err)
+	}
+}
+
+// update applies a change to a job's stats under the lock
+func (s *Scheduler) update(name string, fn func(st *JobStats)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.stats[name])
+}
+
+// instanceID identifies this replica as a lock owner
+func instanceID() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 8)
+	rand.Read(b)
+	return fmt.Sprintf("%s-%s", host, hex.EncodeToString(b))
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/database"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"log"
+	"time"
+)
+
+const (
+	// UserEventsStream is the Redis stream carrying user lifecycle events to other services
+	UserEventsStream = "events:users"
+
+	// EventUserDeleted is published when an account has been permanently deleted
+	EventUserDeleted = "user.deleted"
+
+	// accountJobBatchSize limits how many deletions or exports one job run handles
+	accountJobBatchSize = 20
+
+	// staleExportAfter is how long an export may stay in processing before it is considered abandoned
+	staleExportAfter = time.Hour
+)
+
+// AccountService handles account deletion and personal data export
+type AccountService struct {
+	userRepo    repository.UserRepository
+	tokenRepo   repository.TokenRepository
+	patRepo     repository.PersonalAccessTokenRepository
+	clientRepo  repository.OAuthClientRepository
+	consentRepo repository.OAuthConsentRepository
+	exportRepo  repository.DataExportRepository
+	apiClient   *APIClient
+	reauth      *Reauthenticator
+	config      *config.Config
+}
+
+// NewAccountService creates a new account service
+func NewAccountService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	patRepo repository.PersonalAccessTokenRepository,
+	clientRepo repository.OAuthClientRepository,
+	consentRepo repository.OAuthConsentRepository,
+	exportRepo repository.DataExportRepository,
+	apiClient *APIClient,
+	reauth *Reauthenticator,
+	cfg *config.Config,
+) *AccountService {
+	return &AccountService{
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		patRepo:     patRepo,
+		clientRepo:  clientRepo,
+		consentRepo: consentRepo,
+		exportRepo:  exportRepo,
+		apiClient:   apiClient,
+		reauth:      reauth,
+		config:      cfg,
+	}
+}
+
+// RequestDeletion schedules the user's account for deletion after the grace period.
+// The user can keep signing in and cancel until then. Accounts without a password
+// confirm with an emailed code; see Reauthenticator.
+func (s *AccountService) RequestDeletion(ctx context.Context, userID uint, password, code string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if user.DeletionScheduledAt != nil {
+		return nil, fmt.Errorf("account deletion is already scheduled")
+	}
+
+	if err := s.reauth.Verify(ctx, user, ReauthDeleteAccount, password, code); err != nil {
+		return nil, err
+	}
+
+	scheduledAt := time.Now().Add(s.config.Account.DeletionGracePeriod)
+	user.DeletionScheduledAt = &scheduledAt
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to schedule deletion: %w", err)
+	}
+
+	return user, nil
+}
+
+// CancelDeletion cancels a pending account deletion
+func (s *AccountService) CancelDeletion(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	if user.DeletionScheduledAt == nil {
+		return fmt.Errorf("no account deletion is scheduled")
+	}
+
+	user.DeletionScheduledAt = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return fmt.Errorf("failed to cancel deletion: %w", err)
+	}
+
+	return nil
+}
+
+// ProcessDueDeletions permanently deletes accounts whose grace period has ended.
+// The deletion event is published first so other services always learn about it;
+// if the local delete then fails, the next run publishes again and consumers must be idempotent.
+func (s *AccountService) ProcessDueDeletions(ctx context.Context) (int, error) {
+	users, err := s.userRepo.FindDueForDeletion(time.Now(), accountJobBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, user := range users {
+		if err := database.PublishEvent(ctx, UserEventsStream, map[string]interface{}{
+			"type":    EventUserDeleted,
+			"user_id": user.ID,
+		}); err != nil {
+			return deleted, fmt.Errorf("failed to publish deletion event for user %d: %w", user.ID, err)
+		}
+
+		// Exports and their archives cascade with the user
+		if err := s.userRepo.HardDelete(user.ID); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// RequestExport queues a personal data export for the user
+func (s *AccountService) RequestExport(ctx context.Context, userID uint) (*models.DataExport, error) {
+	exports, err := s.exportRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, export := range exports {
+		inProgress := export.Status == models.DataExportPending ||
+			// An export stuck in processing (e.g. the worker crashed) stops blocking new requests
+			(export.Status == models.DataExportProcessing && time.Since(export.UpdatedAt) < staleExportAfter)
+		if inProgress {
+			return nil, fmt.Errorf("a data export is already in progress")
+		}
+	}
+
+	export := &models.DataExport{
+		UserID: userID,
+		Status: models.DataExportPending,
+	}
+	if err := s.exportRepo.Create(export); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+// ListExports returns the user's data exports
+func (s *AccountService) ListExports(ctx context.Context, userID uint) ([]models.DataExport, error) {
+	return s.exportRepo.FindByUserID(userID)
+}
+
+// GetExportArchive returns the zip archive of a downloadable data export
+func (s *AccountService) GetExportArchive(ctx context.Context, export *models.DataExport) ([]byte, error) {
+	if !export.IsDownloadable() {
+		return nil, fmt.Errorf("data export archive not found")
+	}
+
+	archive, err := s.exportRepo.FindArchive(export.ID)
+	if err != nil {
+		return nil, err
+	}
+	return archive.Content, nil
+}
+
+// GetExport returns one of the user's data exports
+func (s *AccountService) GetExport(ctx context.Context, userID, exportID uint) (*models.DataExport, error) {
+	export, err := s.exportRepo.FindByID(exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Other users' exports are reported as missing so their IDs can't be probed
+	if export.UserID != userID {
+		return nil, fmt.Errorf("data export not found")
+	}
+
+	return export, nil
+}
+
+// ProcessPendingExports builds archives for queued data exports
+func (s *AccountService) ProcessPendingExports(ctx context.Context) (int, error) {
+	exports, err := s.exportRepo.FindByStatus(models.DataExportPending, accountJobBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for i := range exports {
+		export := &exports[i]
+
+		claimed, err := s.exportRepo.ClaimPending(export.ID)
+		if err != nil {
+			return processed, err
+		}
+		if !claimed {
+			continue
+		}
+
+		now := time.Now()
+		export.CompletedAt = &now
+		if err := s.buildExport(ctx, export); err != nil {
+			log.Printf("Data export %d failed: %v", export.ID, err)
+			export.Status = models.DataExportFailed
+			export.Error = "failed to build export archive"
+		} else {
+			expiresAt := now.Add(s.config.Account.ExportExpiry)
+			export.Status = models.DataExportReady
+			export.ExpiresAt = &expiresAt
+		}
+
+		if err := s.exportRepo.Update(export); err != nil {
+			return processed, err
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// CleanupExpiredExports deletes archives that can no longer be downloaded
+func (s *AccountService) CleanupExpiredExports(ctx context.Context) (int, error) {
+	exports, err := s.exportRepo.FindExpired(time.Now(), accountJobBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// Archives are deleted with their export
+	for _, export := range exports {
+		if err := s.exportRepo.Delete(export.ID); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(exports), nil
+}
+
+// exportedSession is a refresh token without its secret value
+type exportedSession struct {
+	ID        uint      `json:"id"`
+	ClientID  string    `json:"client_id,omitempty"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// exportedClient is an OAuth client without its secret hash
+type exportedClient struct {
+	ClientID     string    `json:"client_id"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Scopes       []string  `json:"scopes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// buildExport collects the user's data from both services and stores it as a zip archive
+func (s *AccountService) buildExport(ctx context.Context, export *models.DataExport) error {
+	user, err := s.userRepo.FindByID(export.UserID)
+	if err != nil {
+		return err
+	}
+
+	refreshTokens, err := s.tokenRepo.FindByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+	sessions := make([]exportedSession, 0, len(refreshTokens))
+	for _, token := range refreshTokens {
+		sessions = append(sessions, exportedSession{
+			ID:        token.ID,
+			ClientID:  token.ClientID,
+			IPAddress: token.IPAddress,
+			UserAgent: token.UserAgent,
+			CreatedAt: token.CreatedAt,
+			ExpiresAt: token.ExpiresAt,
+		})
+	}
+
+	pats, err := s.patRepo.FindByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+
+	consents, err := s.consentRepo.FindByUserID(user.ID)
+	if err != nil {
+		return err
+	}
+
+	oauthClients, err := s.clientRepo.FindByOwnerID(user.ID)
+	if err != nil {
+		return err
+	}
+	clients := make([]exportedClient, 0, len(oauthClients))
+	for _, client := range oauthClients {
+		clients = append(clients, exportedClient{
+			ClientID:     client.ClientID,
+			Name:         client.Name,
+			RedirectURIs: client.RedirectURIList(),
+			Scopes:       client.ScopeList(),
+			CreatedAt:    client.CreatedAt,
+		})
+	}
+
+	content, err := s.apiClient.ExportUserContent(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"account.json", user},
+		{"sessions.json", sessions},
+		{"personal_access_tokens.json", pats},
+		{"oauth_consents.json", consents},
+		{"oauth_clients.json", clients},
+		{"posts.json", content.Posts},
+		{"comments.json", content.Comments},
+		{"relationships.json", content.Relationships},
+		{"reactions.json", content.Reactions},
+		{"bookmarks.json", content.Bookmarks},
+		{"bookmark_lists.json", content.BookmarkLists},
+		{"follows.json", content.Follows},
+		{"notifications.json", content.Notifications},
+	}
+	for _, f := range files {
+		if err = writeJSONToZip(archive, f.name, f.data); err != nil {
+			break
+		}
+	}
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write export archive: %w", err)
+	}
+
+	if err := s.exportRepo.SaveArchive(&models.DataExportArchive{ExportID: export.ID, Content: buf.Bytes()}); err != nil {
+		return err
+	}
+
+	export.FileSize = int64(buf.Len())
+	return nil
+}
+
+// writeJSONToZip adds an indented JSON document to a zip archive
+func writeJSONToZip(archive *zip.Writer, name string, data interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"inkstack-auth/internal/config"
+	"net/http"
+	"strings"
+)
+
+const (
+	// InternalClientID identifies the auth service in service tokens it sends to other services.
+	// Registered OAuth client IDs are random, so they can never take this value.
+	InternalClientID = "inkstack-auth"
+
+	// ScopeUsersExport allows reading all of a user's content for a data export
+	ScopeUsersExport = "users:export"
+)
+
+// UserContentExport is the api service's part of a personal data export
+type UserContentExport struct {
+	Posts         json.RawMessage `json:"posts"`
+	Comments      json.RawMessage `json:"comments"`
+	Relationships json.RawMessage `json:"relationships"`  // Users the account has blocked or muted
+	Reactions     json.RawMessage `json:"reactions"`      // Reactions left on posts and comments
+	Bookmarks     json.RawMessage `json:"bookmarks"`      // Saved posts
+	BookmarkLists json.RawMessage `json:"bookmark_lists"` // Reading lists bookmarks are sorted into
+	Follows       json.RawMessage `json:"follows"`        // Authors and tags the account follows
+	Notifications json.RawMessage `json:"notifications"`  // Notifications the account received
+}
+
+// APIClient calls the api service over HTTP
+type APIClient struct {
+	baseURL    string
+	httpClient *http.Client
+	jwtService *JWTService
+}
+
+// NewAPIClient creates a new api service client
+func NewAPIClient(cfg *config.Config, jwtService *JWTService) *APIClient {
+	return &APIClient{
+		baseURL: strings.TrimRight(cfg.API.ServiceURL, "/"),
+		httpClient: &http.Client{
+			Timeout: cfg.API.RequestTimeout,
+		},
+		jwtService: jwtService,
+	}
+}
+
+// ExportUserContent fetches a user's posts, comments, blocks and mutes from the api service
+func (c *APIClient) ExportUserContent(ctx context.Context, userID uint) (*UserContentExport, error) {
+	token, _, err := c.jwtService.GenerateServiceToken(InternalClientID, []string{ScopeUsersExport})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/internal/users/%d/export", c.baseURL, userID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("api service unavailable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("api service returned status %d", resp.StatusCode)
+	}
+
+	var result UserContentExport
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode api service response: %w", err)
+	}
+
+	return &result, nil
+}
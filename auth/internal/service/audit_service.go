@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"log"
+	"time"
+)
+
+// maxAuditUserAgentLength matches the auth_events.user_agent column
+const maxAuditUserAgentLength = 500
+
+// ClientInfo describes where a request came from
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// AuditEvent is an authentication event to record
+type AuditEvent struct {
+	Type       string
+	UserID     uint   // 0 when the account is unknown
+	Identifier string // Login name as submitted, for failed logins
+	Client     ClientInfo
+	Details    string
+}
+
+// AuditService records and queries the security audit log
+type AuditService struct {
+	eventRepo repository.AuthEventRepository
+	config    *config.Config
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(eventRepo repository.AuthEventRepository, cfg *config.Config) *AuditService {
+	return &AuditService{
+		eventRepo: eventRepo,
+		config:    cfg,
+	}
+}
+
+// Record appends an event to the audit log. Failures are logged rather than
+// returned so that auditing never blocks authentication.
+func (s *AuditService) Record(event AuditEvent) {
+	userAgent := event.Client.UserAgent
+	if len(userAgent) > maxAuditUserAgentLength {
+		userAgent = userAgent[:maxAuditUserAgentLength]
+	}
+
+	record := &models.AuthEvent{
+		EventType:  event.Type,
+		Identifier: event.Identifier,
+		IPAddress:  event.Client.IPAddress,
+		UserAgent:  userAgent,
+		Details:    event.Details,
+	}
+	if event.UserID != 0 {
+		userID := event.UserID
+		record.UserID = &userID
+	}
+
+	if err := s.eventRepo.Create(record); err != nil {
+		log.Printf("Failed to record %s auth event: %v", event.Type, err)
+	}
+}
+
+// ListEvents finds events matching an admin query
+func (s *AuditService) ListEvents(filter repository.AuthEventFilter) ([]models.AuthEvent, int64, error) {
+	return s.eventRepo.List(filter)
+}
+
+// RecentActivity returns a page of the user's own events, newest first
+func (s *AuditService) RecentActivity(userID uint, limit, offset int) ([]models.AuthEvent, int64, error) {
+	return s.eventRepo.List(repository.AuthEventFilter{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
+
+// CleanupExpired deletes events older than the retention period
+func (s *AuditService) CleanupExpired(ctx context.Context) (int64, error) {
+	return s.eventRepo.DeleteBefore(time.Now().Add(-s.config.Audit.Retention))
+}
@@ -2,19 +2,44 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
+	"inkstack-auth/internal/config"
 	"inkstack-auth/internal/database"
+	"inkstack-auth/internal/mailer"
 	"inkstack-auth/internal/models"
 	"inkstack-auth/internal/repository"
 	"inkstack-auth/internal/util"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 )
 
+const (
+	// maxLoginAttemptsPerAccount is how many failed logins an account tolerates per 15 minutes
+	maxLoginAttemptsPerAccount = 5
+
+	// maxLoginAttemptsPerIP is how many failed logins one IP may make per 15 minutes,
+	// across all accounts, to stop username rotation and password spraying
+	maxLoginAttemptsPerIP = 20
+
+	// maxMagicLinksPerEmail is how many login links one address may be sent per 15 minutes
+	maxMagicLinksPerEmail = 3
+)
+
 // AuthService handles authentication operations
 type AuthService struct {
 	userRepo  repository.UserRepository
 	tokenRepo repository.TokenRepository
 	jwtService *JWTService
+	passwordPolicy *PasswordPolicy
+	auditService   *AuditService
+	registration   *RegistrationPolicy
+	mailer         mailer.Mailer
+	devices        *DeviceService
+	config         *config.Config
 }
 
 // NewAuthService creates a new auth service
@@ -22,19 +47,34 @@ func NewAuthService(
 	userRepo repository.UserRepository,
 	tokenRepo repository.TokenRepository,
 	jwtService *JWTService,
+	passwordPolicy *PasswordPolicy,
+	auditService *AuditService,
+	registration *RegistrationPolicy,
+	mailer mailer.Mailer,
+	devices *DeviceService,
+	cfg *config.Config,
 ) *AuthService {
 	return &AuthService{
-		userRepo:   userRepo,
-		tokenRepo:  tokenRepo,
-		jwtService: jwtService,
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		jwtService:     jwtService,
+		passwordPolicy: passwordPolicy,
+		auditService:   auditService,
+		registration:   registration,
+		mailer:         mailer,
+		devices:        devices,
+		config:         cfg,
 	}
 }
 
 // RegisterInput contains registration data
 type RegisterInput struct {
-	Email    string
-	Username string
-	Password string
+	Email      string
+	Username   string
+	Password   string
+	InviteCode string // Required unless the registration policy admits the address
+	IPAddress  string
+	UserAgent  string
 }
 
 // LoginInput contains login data
@@ -49,6 +89,7 @@ type LoginInput struct {
 type TokenPair struct {
 	AccessToken  string
 	RefreshToken string
+	sessionID    uint // ID of the stored refresh token
 }
 
 // Register registers a new user
@@ -62,7 +103,7 @@ func (s *AuthService) Register(ctx context.Context, input RegisterInput) (*model
 		return nil, nil, err
 	}
 
-	if err := util.ValidatePasswordStrength(input.Password); err != nil {
+	if err := s.passwordPolicy.Validate(input.Password, input.Username, input.Email); err != nil {
 		return nil, nil, err
 	}
 
@@ -90,33 +131,62 @@ func (s *AuthService) Register(ctx context.Context, input RegisterInput) (*model
 		return nil, nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
+	// Enforce the registration policy last so invite uses aren't spent on invalid requests
+	invite, err := s.registration.Admit(input.Email, input.InviteCode)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Create user
 	user := &models.User{
 		Email:        input.Email,
 		Username:     input.Username,
 		PasswordHash: passwordHash,
 		IsActive:     true,
-		Role:         "user",
+		Role:         s.config.Registration.DefaultRole,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
+		s.registration.Release(invite)
 		return nil, nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
 	// Generate tokens
-	tokens, err := s.generateTokenPair(ctx, user, "", "")
+	tokens, err := s.generateTokenPair(ctx, user, input.IPAddress, input.UserAgent)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
+	event := AuditEvent{
+		Type:   models.AuthEventRegister,
+		UserID: user.ID,
+		Client: ClientInfo{IPAddress: input.IPAddress, UserAgent: input.UserAgent},
+	}
+	if invite != nil {
+		event.Details = fmt.Sprintf("invite:%d", invite.ID)
+	}
+	s.auditService.Record(event)
+
+	// Remember the device so that signing in from a different one later is noticed
+	s.devices.CheckLogin(ctx, user, event.Client, tokens.sessionID)
+
 	return user, tokens, nil
 }
 
 // Login authenticates a user and returns tokens
 func (s *AuthService) Login(ctx context.Context, input LoginInput) (*models.User, *TokenPair, error) {
 	// Check rate limiting
+	if input.IPAddress != "" {
+		attempts, err := database.GetLoginAttempts(ctx, loginAttemptsIPKey(input.IPAddress))
+		if err == nil && attempts >= maxLoginAttemptsPerIP {
+			s.recordLoginFailure(input, 0, "ip_locked")
+			return nil, nil, fmt.Errorf("too many failed login attempts from this network, please try again in 15 minutes")
+		}
+	}
+
 	attempts, err := database.GetLoginAttempts(ctx, input.EmailOrUsername)
-	if err == nil && attempts >= 5 {
+	if err == nil && attempts >= maxLoginAttemptsPerAccount {
+		s.recordLoginFailure(input, 0, "account_locked")
 		return nil, nil, fmt.Errorf("too many failed login attempts, please try again in 15 minutes")
 	}
 
@@ -124,25 +194,37 @@ func (s *AuthService) Login(ctx context.Context, input LoginInput) (*models.User
 	user, err := s.userRepo.FindByEmailOrUsername(input.EmailOrUsername)
 	if err != nil {
 		// Increment failed attempts
-		database.IncrementLoginAttempts(ctx, input.EmailOrUsername)
+		s.recordFailedLogin(ctx, input, 0)
+		s.recordLoginFailure(input, 0, "unknown_account")
 		return nil, nil, fmt.Errorf("invalid credentials")
 	}
 
 	// Check if user is active
 	if !user.IsActive {
+		s.recordLoginFailure(input, user.ID, "account_inactive")
 		return nil, nil, fmt.Errorf("account is inactive")
 	}
 
 	// Verify password
 	if !util.ComparePassword(user.PasswordHash, input.Password) {
 		// Increment failed attempts
-		database.IncrementLoginAttempts(ctx, input.EmailOrUsername)
+		s.recordFailedLogin(ctx, input, user.ID)
+		s.recordLoginFailure(input, user.ID, "invalid_password")
 		return nil, nil, fmt.Errorf("invalid credentials")
 	}
 
-	// Reset login attempts on successful login
+	// Reset login attempts on successful login. The per-IP counter is left alone so
+	// an attacker cannot clear it by logging into their own account.
 	database.ResetLoginAttempts(ctx, input.EmailOrUsername)
 
+	// Upgrade the stored hash if it uses an older algorithm or weaker parameters.
+	// A failure here only delays the upgrade to the next login.
+	if util.PasswordNeedsRehash(user.PasswordHash) {
+		if passwordHash, err := util.HashPassword(input.Password); err == nil {
+			user.PasswordHash = passwordHash
+		}
+	}
+
 	// Update last login time
 	now := time.Now()
 	user.LastLoginAt = &now
@@ -154,13 +236,22 @@ func (s *AuthService) Login(ctx context.Context, input LoginInput) (*models.User
 		return nil, nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
+	client := ClientInfo{IPAddress: input.IPAddress, UserAgent: input.UserAgent}
+	s.auditService.Record(AuditEvent{
+		Type:       models.AuthEventLoginSuccess,
+		UserID:     user.ID,
+		Identifier: input.EmailOrUsername,
+		Client:     client,
+	})
+	s.devices.CheckLogin(ctx, user, client, tokens.sessionID)
+
 	return user, tokens, nil
 }
 
 // RefreshToken generates a new access token using a refresh token
-func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenString string) (string, error) {
+func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenString string, client ClientInfo) (string, error) {
 	// Validate refresh token JWT
-	claims, err := s.jwtService.ValidateToken(refreshTokenString)
+	claims, err := s.jwtService.ValidateRefreshToken(refreshTokenString)
 	if err != nil {
 		return "", fmt.Errorf("invalid refresh token")
 	}
@@ -172,9 +263,22 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenString strin
 	}
 
 	if !tokenRecord.IsValid() {
+		// A revoked token being presented again suggests it was stolen
+		if tokenRecord.IsRevoked {
+			s.auditService.Record(AuditEvent{
+				Type:   models.AuthEventTokenReuse,
+				UserID: tokenRecord.UserID,
+				Client: client,
+			})
+		}
 		return "", fmt.Errorf("refresh token is invalid or expired")
 	}
 
+	// Tokens issued to OAuth clients must be refreshed through the token endpoint
+	if tokenRecord.ClientID != "" || claims.ClientID != "" {
+		return "", fmt.Errorf("refresh token not found")
+	}
+
 	// Get user
 	user, err := s.userRepo.FindByID(claims.UserID)
 	if err != nil {
@@ -194,11 +298,17 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenString strin
 	// Optional: Implement token rotation (generate new refresh token)
 	// For simplicity, we're not rotating refresh tokens here
 
+	s.auditService.Record(AuditEvent{
+		Type:   models.AuthEventRefresh,
+		UserID: user.ID,
+		Client: client,
+	})
+
 	return accessToken, nil
 }
 
 // Logout revokes a refresh token
-func (s *AuthService) Logout(ctx context.Context, refreshTokenString, accessTokenString string) error {
+func (s *AuthService) Logout(ctx context.Context, userID uint, refreshTokenString, accessTokenString string, client ClientInfo) error {
 	// Revoke refresh token in database
 	if err := s.tokenRepo.RevokeToken(refreshTokenString); err != nil {
 		return fmt.Errorf("failed to revoke refresh token: %w", err)
@@ -214,6 +324,46 @@ func (s *AuthService) Logout(ctx context.Context, refreshTokenString, accessToke
 		}
 	}
 
+	s.auditService.Record(AuditEvent{
+		Type:   models.AuthEventLogout,
+		UserID: userID,
+		Client: client,
+	})
+
+	return nil
+}
+
+// EndSession ends a cookie session identified by its refresh token, which outlives the
+// access token, so browsers can sign out after the access cookie has expired. The access
+// token is blacklisted too if it is still valid and belongs to the same user. Sessions
+// that are already revoked or unknown are ended without error.
+func (s *AuthService) EndSession(ctx context.Context, refreshTokenString, accessTokenString string, client ClientInfo) error {
+	if refreshTokenString == "" {
+		return nil
+	}
+
+	// OAuth clients' refresh tokens are revoked through the OAuth revocation endpoint
+	tokenRecord, err := s.tokenRepo.FindByToken(refreshTokenString)
+	if err != nil || tokenRecord.IsRevoked || tokenRecord.ClientID != "" {
+		return nil
+	}
+
+	if err := s.tokenRepo.RevokeToken(refreshTokenString); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+
+	if claims, err := s.jwtService.ValidateAccessToken(accessTokenString); err == nil && claims.UserID == tokenRecord.UserID {
+		if ttl := time.Until(claims.ExpiresAt.Time); ttl > 0 {
+			database.BlacklistToken(ctx, accessTokenString, ttl)
+		}
+	}
+
+	s.auditService.Record(AuditEvent{
+		Type:   models.AuthEventLogout,
+		UserID: tokenRecord.UserID,
+		Client: client,
+	})
+
 	return nil
 }
 
@@ -223,20 +373,20 @@ func (s *AuthService) LogoutAll(ctx context.Context, userID uint) error {
 }
 
 // ChangePassword changes a user's password
-func (s *AuthService) ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string) error {
+func (s *AuthService) ChangePassword(ctx context.Context, userID uint, oldPassword, newPassword string, client ClientInfo) error {
 	// Get user
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return fmt.Errorf("user not found")
 	}
 
-	// Verify old password
-	if !util.ComparePassword(user.PasswordHash, oldPassword) {
+	// Verify old password. Accounts created by magic link have none and may set one.
+	if user.PasswordHash != "" && !util.ComparePassword(user.PasswordHash, oldPassword) {
 		return fmt.Errorf("invalid current password")
 	}
 
 	// Validate new password strength
-	if err := util.ValidatePasswordStrength(newPassword); err != nil {
+	if err := s.passwordPolicy.Validate(newPassword, user.Username, user.Email); err != nil {
 		return err
 	}
 
@@ -255,36 +405,322 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID uint, oldPasswo
 	// Revoke all existing refresh tokens (force re-login)
 	s.tokenRepo.RevokeAllUserTokens(userID)
 
+	s.auditService.Record(AuditEvent{
+		Type:   models.AuthEventPasswordChange,
+		UserID: userID,
+		Client: client,
+	})
+
 	return nil
 }
 
-// ValidateToken validates an access token and returns user info
-func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*models.User, error) {
+// ValidateToken validates an access token and returns user info along with the token's claims
+func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*models.User, *JWTClaims, error) {
 	// Check if token is blacklisted
 	isBlacklisted, err := database.IsTokenBlacklisted(ctx, tokenString)
 	if err == nil && isBlacklisted {
-		return nil, fmt.Errorf("token has been revoked")
+		return nil, nil, fmt.Errorf("token has been revoked")
 	}
 
 	// Validate token
-	claims, err := s.jwtService.ValidateToken(tokenString)
+	claims, err := s.jwtService.ValidateAccessToken(tokenString)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Get user
 	user, err := s.userRepo.FindByID(claims.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("user not found")
+		return nil, nil, fmt.Errorf("user not found")
+	}
+
+	if !user.IsActive {
+		return nil, nil, fmt.Errorf("account is inactive")
+	}
+
+	return user, claims, nil
+}
+
+// PasswordCheck is the result of checking a candidate password against the password policy
+type PasswordCheck struct {
+	util.PasswordStrength
+	Breached   bool   `json:"breached"`
+	Acceptable bool   `json:"acceptable"`
+	Error      string `json:"error,omitempty"`
+}
+
+// CheckPassword reports how a candidate password fares against the password policy,
+// so clients can give feedback before submitting it
+func (s *AuthService) CheckPassword(password, username, email string) *PasswordCheck {
+	check := &PasswordCheck{
+		PasswordStrength: s.passwordPolicy.Estimate(password, username, email),
+		Breached:         s.passwordPolicy.IsBreached(password),
+		Acceptable:       true,
+	}
+
+	if err := s.passwordPolicy.Validate(password, username, email); err != nil {
+		check.Acceptable = false
+		check.Error = err.Error()
+	}
+
+	return check
+}
+
+// MagicLinkInput contains a passwordless login request
+type MagicLinkInput struct {
+	Email     string
+	IPAddress string
+	UserAgent string
+}
+
+// magicLink is a pending login stored in Redis under the hash of the emailed token
+type magicLink struct {
+	Email       string `json:"email"`
+	BrowserHash string `json:"browser_hash"`
+}
+
+// RequestMagicLink emails a single-use login link and returns a browser token that must
+// accompany the link when it is confirmed, binding it to the requesting browser.
+// Addresses without an account only get a link when auto-registration is enabled, but
+// the response is the same either way so it cannot be used to discover accounts.
+func (s *AuthService) RequestMagicLink(ctx context.Context, input MagicLinkInput) (string, error) {
+	if !s.config.MagicLink.Enabled {
+		return "", fmt.Errorf("magic link login is disabled")
+	}
+
+	email := strings.TrimSpace(input.Email)
+	if err := util.ValidateEmail(email); err != nil {
+		return "", err
+	}
+
+	// Stop the endpoint from being used to flood someone's inbox
+	result, err := database.AllowRequest(ctx, "magic_link:"+strings.ToLower(email), maxMagicLinksPerEmail, 15*time.Minute)
+	if err == nil && !result.Allowed {
+		return "", fmt.Errorf("too many login links requested for this address, please try again later")
+	}
+
+	browserToken, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := s.userRepo.FindByEmail(email)
+	switch {
+	case err != nil && !s.canAutoRegister(email):
+		return browserToken, nil
+	case err == nil && !user.IsActive:
+		return browserToken, nil
+	}
+
+	token, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(magicLink{
+		Email:       email,
+		BrowserHash: util.HashToken(browserToken),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode magic link: %w", err)
+	}
+
+	if err := database.StoreMagicLink(ctx, util.HashToken(token), string(payload), s.config.MagicLink.Expiry); err != nil {
+		return "", fmt.Errorf("failed to store magic link: %w", err)
+	}
+
+	link := fmt.Sprintf("%s/magic-link?token=%s", strings.TrimRight(s.config.App.FrontendURL, "/"), url.QueryEscape(token))
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Your Inkstack sign-in link",
+		Body: fmt.Sprintf("Hi,\n\nUse this link to sign in to Inkstack:\n\n%s\n\n"+
+			"The link works once, expires in %s and only in the browser where you requested it. "+
+			"If you didn't ask to sign in, you can ignore this email.\n",
+			link, s.config.MagicLink.Expiry),
+	}); err != nil {
+		database.ConsumeMagicLink(ctx, util.HashToken(token))
+		return "", fmt.Errorf("failed to send login link: %w", err)
+	}
+
+	return browserToken, nil
+}
+
+// ConfirmMagicLink exchanges a magic link token for a normal token pair. Confirming a
+// link for an address without an account creates one when auto-registration is enabled.
+func (s *AuthService) ConfirmMagicLink(ctx context.Context, token, browserToken string, client ClientInfo) (*models.User, *TokenPair, error) {
+	if !s.config.MagicLink.Enabled {
+		return nil, nil, fmt.Errorf("magic link login is disabled")
+	}
+
+	// Consuming the link up front makes it single-use even when the checks below fail
+	payload, err := database.ConsumeMagicLink(ctx, util.HashToken(token))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check login link: %w", err)
+	}
+
+	var link magicLink
+	if payload == "" || json.Unmarshal([]byte(payload), &link) != nil {
+		s.recordMagicLinkFailure(client, "", "invalid_magic_link")
+		return nil, nil, fmt.Errorf("invalid or expired login link")
+	}
+
+	if s.config.MagicLink.SameBrowser &&
+		subtle.ConstantTimeCompare([]byte(util.HashToken(browserToken)), []byte(link.BrowserHash)) != 1 {
+		s.recordMagicLinkFailure(client, link.Email, "magic_link_wrong_browser")
+		return nil, nil, fmt.Errorf("this login link must be opened in the browser that requested it")
+	}
+
+	user, err := s.userRepo.FindByEmail(link.Email)
+	if err != nil {
+		if !s.canAutoRegister(link.Email) {
+			return nil, nil, fmt.Errorf("invalid or expired login link")
+		}
+		if user, err = s.registerPasswordless(link.Email, client); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	if !user.IsActive {
-		return nil, fmt.Errorf("account is inactive")
+		s.recordMagicLinkFailure(client, link.Email, "account_inactive")
+		return nil, nil, fmt.Errorf("account is inactive")
 	}
 
+	// Following the link proves the user controls the address
+	now := time.Now()
+	user.EmailVerified = true
+	user.LastLoginAt = &now
+	s.userRepo.Update(user)
+
+	tokens, err := s.generateTokenPair(ctx, user, client.IPAddress, client.UserAgent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate tokens: %w", err)
+	}
+
+	s.auditService.Record(AuditEvent{
+		Type:       models.AuthEventLoginSuccess,
+		UserID:     user.ID,
+		Identifier: link.Email,
+		Client:     client,
+		Details:    "magic_link",
+	})
+	s.devices.CheckLogin(ctx, user, client, tokens.sessionID)
+
+	return user, tokens, nil
+}
+
+// canAutoRegister reports whether a magic link may create an account for an address.
+// Links carry no invite code, so only addresses the policy admits outright qualify.
+func (s *AuthService) canAutoRegister(email string) bool {
+	return s.config.MagicLink.AutoRegister && s.registration.AllowsWithoutInvite(email)
+}
+
+// usernameInvalidChars matches characters that are not allowed in usernames
+var usernameInvalidChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// registerPasswordless creates an account without a password for a confirmed email
+// address. The username is derived from the address and can be changed later.
+func (s *AuthService) registerPasswordless(email string, client ClientInfo) (*models.User, error) {
+	base := usernameInvalidChars.ReplaceAllString(strings.ToLower(strings.SplitN(email, "@", 2)[0]), "")
+	if len(base) > 20 {
+		base = base[:20]
+	}
+	if len(base) < 3 {
+		base = "reader" + base
+	}
+
+	username := base
+	for attempt := 0; ; attempt++ {
+		exists, err := s.userRepo.ExistsByUsername(username)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check username: %w", err)
+		}
+		if !exists {
+			break
+		}
+		if attempt == 5 {
+			return nil, fmt.Errorf("failed to generate a username")
+		}
+		suffix, err := util.GenerateRandomToken(3)
+		if err != nil {
+			return nil, err
+		}
+		username = base + "-" + strings.ToLower(suffix)
+	}
+
+	// An empty password hash never matches, so the account can only sign in by link
+	user := &models.User{
+		Email:    email,
+		Username: username,
+		IsActive: true,
+		Role:     s.config.Registration.DefaultRole,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	s.auditService.Record(AuditEvent{
+		Type:    models.AuthEventRegister,
+		UserID:  user.ID,
+		Client:  client,
+		Details: "magic_link",
+	})
+
 	return user, nil
 }
 
+// recordMagicLinkFailure logs a rejected magic link confirmation
+func (s *AuthService) recordMagicLinkFailure(client ClientInfo, email, reason string) {
+	s.auditService.Record(AuditEvent{
+		Type:       models.AuthEventLoginFailure,
+		Identifier: email,
+		Client:     client,
+		Details:    reason,
+	})
+}
+
+// recordFailedLogin increments the per-account and per-IP failed login counters and
+// logs a lockout when either counter reaches its limit
+func (s *AuthService) recordFailedLogin(ctx context.Context, input LoginInput, userID uint) {
+	client := ClientInfo{IPAddress: input.IPAddress, UserAgent: input.UserAgent}
+
+	if attempts, err := database.IncrementLoginAttempts(ctx, input.EmailOrUsername); err == nil && attempts == maxLoginAttemptsPerAccount {
+		s.auditService.Record(AuditEvent{
+			Type:       models.AuthEventLockout,
+			UserID:     userID,
+			Identifier: input.EmailOrUsername,
+			Client:     client,
+			Details:    "account",
+		})
+	}
+	if input.IPAddress != "" {
+		if attempts, err := database.IncrementLoginAttempts(ctx, loginAttemptsIPKey(input.IPAddress)); err == nil && attempts == maxLoginAttemptsPerIP {
+			s.auditService.Record(AuditEvent{
+				Type:       models.AuthEventLockout,
+				Identifier: input.EmailOrUsername,
+				Client:     client,
+				Details:    "ip",
+			})
+		}
+	}
+}
+
+// recordLoginFailure logs a failed login attempt with the reason it failed
+func (s *AuthService) recordLoginFailure(input LoginInput, userID uint, reason string) {
+	s.auditService.Record(AuditEvent{
+		Type:       models.AuthEventLoginFailure,
+		UserID:     userID,
+		Identifier: input.EmailOrUsername,
+		Client:     ClientInfo{IPAddress: input.IPAddress, UserAgent: input.UserAgent},
+		Details:    reason,
+	})
+}
+
+// loginAttemptsIPKey builds the login attempts identifier for an IP address.
+// The prefix cannot collide with emails or usernames, which never contain ':'.
+func loginAttemptsIPKey(ipAddress string) string {
+	return "ip:" + ipAddress
+}
+
 // generateTokenPair generates both access and refresh tokens
 func (s *AuthService) generateTokenPair(ctx context.Context, user *models.User, ipAddress, userAgent string) (*TokenPair, error) {
 	// Generate access token
@@ -294,7 +730,7 @@ func (s *AuthService) generateTokenPair(ctx context.Context, user *models.User,
 	}
 
 	// Generate refresh token
-	refreshToken, expiresAt, err := s.jwtService.GenerateRefreshToken(user)
+	refreshToken, expiresAt, err := s.jwtService.GenerateRefreshToken(user, "", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -316,5 +752,6 @@ func (s *AuthService) generateTokenPair(ctx context.Context, user *models.User,
 	return &TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
+		sessionID:    tokenRecord.ID,
 	}, nil
 }
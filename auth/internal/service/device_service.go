@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/database"
+	"inkstack-auth/internal/mailer"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/util"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// loginAlertSendTimeout bounds sending a new device alert, which happens after the response
+const loginAlertSendTimeout = 30 * time.Second
+
+// DeviceService tracks the devices and networks users sign in from and alerts them
+// to sign-ins from new ones
+type DeviceService struct {
+	deviceRepo   repository.KnownDeviceRepository
+	tokenRepo    repository.TokenRepository
+	auditService *AuditService
+	mailer       mailer.Mailer
+	geoIP        *util.GeoIPDatabase
+	config       *config.Config
+}
+
+// NewDeviceService creates a device service, loading the GeoIP database if one is configured
+func NewDeviceService(
+	deviceRepo repository.KnownDeviceRepository,
+	tokenRepo repository.TokenRepository,
+	auditService *AuditService,
+	mailer mailer.Mailer,
+	cfg *config.Config,
+) (*DeviceService, error) {
+	devices := &DeviceService{
+		deviceRepo:   deviceRepo,
+		tokenRepo:    tokenRepo,
+		auditService: auditService,
+		mailer:       mailer,
+		config:       cfg,
+	}
+
+	if cfg.Devices.GeoIPPath == "" {
+		log.Println("GeoIP database not configured, new networks are judged by IP range only")
+		return devices, nil
+	}
+
+	file, err := os.Open(cfg.Devices.GeoIPPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database: %w", err)
+	}
+	defer file.Close()
+
+	devices.geoIP, err = util.ReadGeoIPDatabase(file)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Loaded GeoIP database with %d ranges from %s", devices.geoIP.Len(), cfg.Devices.GeoIPPath)
+	return devices, nil
+}
+
+// loginReport is the session behind a new device alert, stored in Redis under the
+// hash of the emailed "this wasn't me" token
+type loginReport struct {
+	UserID      uint   `json:"user_id"`
+	SessionID   uint   `json:"session_id"` // Refresh token ID
+	Fingerprint string `json:"fingerprint"`
+	IPRange     string `json:"ip_range"`
+}
+
+// CheckLogin records the device and network of a successful sign-in. If the device
+// is new, or the network is new and (when GeoIP is configured) so is its country,
+// the event is audited and the user is emailed a link that revokes the session.
+// A user's first recorded sign-in never alerts. Failures are logged, not returned,
+// so detection never blocks authentication.
+func (s *DeviceService) CheckLogin(ctx context.Context, user *models.User, client ClientInfo, sessionID uint) {
+	description := util.DescribeDevice(client.UserAgent)
+	device := &models.KnownDevice{
+		UserID:      user.ID,
+		Fingerprint: util.HashToken(description),
+		Description: description,
+		IPRange:     util.IPRange(client.IPAddress),
+	}
+	if s.geoIP != nil {
+		device.Country = s.geoIP.Country(client.IPAddress)
+	}
+
+	newDevice, newNetwork, err := s.isNew(device)
+	if err != nil {
+		log.Printf("Failed to check known devices for user %d: %v", user.ID, err)
+		return
+	}
+	if err := s.deviceRepo.Touch(device); err != nil {
+		log.Printf("Failed to record device for user %d: %v", user.ID, err)
+		return
+	}
+	if !newDevice && !newNetwork {
+		return
+	}
+
+	details := fmt.Sprintf("%s, %s", device.Description, device.IPRange)
+	if device.Country != "" {
+		details += ", " + device.Country
+	}
+	s.auditService.Record(AuditEvent{
+		Type:    models.AuthEventNewDevice,
+		UserID:  user.ID,
+		Client:  client,
+		Details: details,
+	})
+
+	if !s.config.Devices.AlertsEnabled {
+		return
+	}
+
+	token, err := s.storeReport(ctx, loginReport{
+		UserID:      user.ID,
+		SessionID:   sessionID,
+		Fingerprint: device.Fingerprint,
+		IPRange:     device.IPRange,
+	})
+	if err != nil {
+		log.Printf("Failed to store login report token for user %d: %v", user.ID, err)
+		return
+	}
+
+	// Don't hold up the sign-in waiting for the mail server
+	go s.sendAlert(user, device, client, token)
+}
+
+// ReportLogin handles a "this wasn't me" link: it revokes the reported session and
+// forgets the device so a later sign-in from it alerts again
+func (s *DeviceService) ReportLogin(ctx context.Context, token string, client ClientInfo) error {
+	payload, err := database.ConsumeLoginReport(ctx, util.HashToken(token))
+	if err != nil {
+		return fmt.Errorf("failed to check link: %w", err)
+	}
+
+	var report loginReport
+	if payload == "" || json.Unmarshal([]byte(payload), &report) != nil {
+		return fmt.Errorf("invalid or expired link")
+	}
+
+	if err := s.tokenRepo.RevokeTokenByID(report.UserID, report.SessionID); err != nil {
+		return err
+	}
+	if err := s.deviceRepo.Delete(report.UserID, report.Fingerprint, report.IPRange); err != nil {
+		log.Printf("Failed to forget reported device for user %d: %v", report.UserID, err)
+	}
+
+	s.auditService.Record(AuditEvent{
+		Type:    models.AuthEventLoginReported,
+		UserID:  report.UserID,
+		Client:  client,
+		Details: fmt.Sprintf("session:%d", report.SessionID),
+	})
+
+	return nil
+}
+
+// isNew reports whether a device has never been seen for the user, and whether its
+// network has never been seen. Neither is new on a user's first recorded sign-in.
+func (s *DeviceService) isNew(device *models.KnownDevice) (newDevice, newNetwork bool, err error) {
+	count, err := s.deviceRepo.CountByUser(device.UserID)
+	if err != nil || count == 0 {
+		return false, false, err
+	}
+
+	knownDevice, err := s.deviceRepo.HasFingerprint(device.UserID, device.Fingerprint)
+	if err != nil {
+		return false, false, err
+	}
+
+	knownNetwork, err := s.deviceRepo.HasIPRange(device.UserID, device.IPRange)
+	if err != nil {
+		return false, false, err
+	}
+
+	// A new network in a country the user has signed in from before is most likely
+	// a phone changing networks or a new ISP lease, not worth an alert
+	if !knownNetwork && device.Country != "" {
+		knownNetwork, err = s.deviceRepo.HasCountry(device.UserID, device.Country)
+		if err != nil {
+			return false, false, err
+		}
+	}
+
+	return !knownDevice, !knownNetwork, nil
+}
+
+// storeReport saves a login report and returns the token for its link
+func (s *DeviceService) storeReport(ctx context.Context, report loginReport) (string, error) {
+	token, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode login report: %w", err)
+	}
+
+	// The link is useless once the session could no longer be refreshed
+	if err := database.StoreLoginReport(ctx, util.HashToken(token), string(payload), s.config.JWT.RefreshExpiry); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// sendAlert emails the user about a sign-in from a new device or network
+func (s *DeviceService) sendAlert(user *models.User, device *models.KnownDevice, client ClientInfo, token string) {
+	ctx, cancel := context.WithTimeout(context.Background(), loginAlertSendTimeout)
+	defer cancel()
+
+	location := client.IPAddress
+	if device.Country != "" {
+		location = fmt.Sprintf("%s (%s)", client.IPAddress, device.Country)
+	}
+
+	link := fmt.Sprintf("%s/login-alert?token=%s", strings.TrimRight(s.config.App.FrontendURL, "/"), url.QueryEscape(token))
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "New sign-in to your Inkstack account",
+		Body: fmt.Sprintf("Hi %s,\n\nYour Inkstack account was just signed in to from a new device or location:\n\n"+
+			"Device: %s\nIP address: %s\nTime: %s\n\n"+
+			"If this was you, you can ignore this email.\n\n"+
+			"If this wasn't you, use this link to sign that session out, then change your password right away:\n\n%s\n",
+			user.Username, device.Description, location, time.Now().UTC().Format(time.RFC1123), link),
+	}); err != nil {
+		log.Printf("Failed to send new device alert to user %d: %v", user.ID, err)
+	}
+}
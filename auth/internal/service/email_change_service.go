@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/mailer"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/util"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// EmailChangeService handles changing a user's email address
+type EmailChangeService struct {
+	userRepo        repository.UserRepository
+	tokenRepo       repository.TokenRepository
+	emailChangeRepo repository.EmailChangeRepository
+	mailer          mailer.Mailer
+	reauth          *Reauthenticator
+	config          *config.Config
+}
+
+// NewEmailChangeService creates a new email change service
+func NewEmailChangeService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	emailChangeRepo repository.EmailChangeRepository,
+	mailer mailer.Mailer,
+	reauth *Reauthenticator,
+	cfg *config.Config,
+) *EmailChangeService {
+	return &EmailChangeService{
+		userRepo:        userRepo,
+		tokenRepo:       tokenRepo,
+		emailChangeRepo: emailChangeRepo,
+		mailer:          mailer,
+		reauth:          reauth,
+		config:          cfg,
+	}
+}
+
+// RequestChange starts an email change. The address is only swapped once the
+// link sent to the new address is confirmed; the old address gets a notice. Accounts
+// without a password confirm with an emailed code; see Reauthenticator.
+func (s *EmailChangeService) RequestChange(ctx context.Context, userID uint, password, code, newEmail string) error {
+	newEmail = strings.TrimSpace(newEmail)
+	if err := util.ValidateEmail(newEmail); err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	if strings.EqualFold(user.Email, newEmail) {
+		return fmt.Errorf("new email must be different from the current email")
+	}
+
+	exists, err := s.userRepo.ExistsByEmail(newEmail)
+	if err != nil {
+		return fmt.Errorf("failed to check email: %w", err)
+	}
+	if exists {
+		return fmt.Errorf("email already registered")
+	}
+
+	if err := s.reauth.Verify(ctx, user, ReauthChangeEmail, password, code); err != nil {
+		return err
+	}
+
+	// Only the latest request can be confirmed
+	if err := s.emailChangeRepo.DeleteByUserID(user.ID); err != nil {
+		return err
+	}
+
+	token, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	request := &models.EmailChangeRequest{
+		UserID:    user.ID,
+		NewEmail:  newEmail,
+		TokenHash: util.HashToken(token),
+		ExpiresAt: time.Now().Add(s.config.Account.EmailChangeExpiry),
+	}
+	if err := s.emailChangeRepo.Create(request); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/confirm-email?token=%s", strings.TrimRight(s.config.App.FrontendURL, "/"), url.QueryEscape(token))
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new Inkstack email address",
+		Body: fmt.Sprintf("Hi %s,\n\nConfirm that you want to use this address for your Inkstack account:\n\n%s\n\n"+
+			"The link expires in %s. If you didn't request this, you can ignore this email.\n",
+			user.Username, link, s.config.Account.EmailChangeExpiry),
+	}); err != nil {
+		s.emailChangeRepo.DeleteByUserID(user.ID)
+		return fmt.Errorf("failed to send confirmation email: %w", err)
+	}
+
+	// The notice is best effort; the change can't happen without the new address anyway
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your Inkstack email address is being changed",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to change the email address of your Inkstack account to %s. "+
+			"It will change once the new address is confirmed.\n\n"+
+			"If this wasn't you, change your password right away.\n",
+			user.Username, newEmail),
+	}); err != nil {
+		log.Printf("Failed to send email change notice to user %d: %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// ConfirmChange swaps in the new address for a valid confirmation token and signs
+// the user out everywhere
+func (s *EmailChangeService) ConfirmChange(ctx context.Context, token string) (*models.User, error) {
+	request, err := s.emailChangeRepo.FindByHash(util.HashToken(token))
+	if err != nil || !request.IsValid() {
+		return nil, fmt.Errorf("invalid or expired confirmation token")
+	}
+
+	user, err := s.userRepo.FindByID(request.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired confirmation token")
+	}
+
+	// The address may have been taken since the request was made
+	exists, err := s.userRepo.ExistsByEmail(request.NewEmail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("email already registered")
+	}
+
+	confirmed, err := s.emailChangeRepo.MarkConfirmed(request.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, fmt.Errorf("invalid or expired confirmation token")
+	}
+
+	// Following the link proves the user controls the new address
+	user.Email = request.NewEmail
+	user.EmailVerified = true
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update email: %w", err)
+	}
+
+	// Sessions were issued for the old address, so end them all
+	if err := s.tokenRepo.RevokeAllUserTokens(user.ID); err != nil {
+		log.Printf("Failed to revoke tokens after email change for user %d: %v", user.ID, err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/util"
+	"strings"
+	"time"
+)
+
+const (
+	// InviteCodePrefix identifies invite codes
+	InviteCodePrefix = "inv_"
+
+	// inviteCodeDisplayLength is how much of the code is kept to identify it
+	inviteCodeDisplayLength = 8
+
+	// defaultInviteExpiry applies when an invite is created without an expiry
+	defaultInviteExpiry = 7 * 24 * time.Hour
+
+	// maxInviteUses caps how many accounts one invite can create
+	maxInviteUses = 1000
+)
+
+// InviteService manages registration invites
+type InviteService struct {
+	inviteRepo repository.InviteRepository
+}
+
+// NewInviteService creates a new invite service
+func NewInviteService(inviteRepo repository.InviteRepository) *InviteService {
+	return &InviteService{
+		inviteRepo: inviteRepo,
+	}
+}
+
+// CreateInviteInput contains invite creation data
+type CreateInviteInput struct {
+	Email     string // Optional; restricts the invite to one address
+	MaxUses   int
+	ExpiresIn time.Duration
+}
+
+// Create issues an invite and returns it along with the plaintext code, which is only available now
+func (s *InviteService) Create(ctx context.Context, inviterID uint, input CreateInviteInput) (*models.Invite, string, error) {
+	email := strings.TrimSpace(input.Email)
+	if email != "" {
+		if err := util.ValidateEmail(email); err != nil {
+			return nil, "", err
+		}
+	}
+
+	maxUses := input.MaxUses
+	if maxUses == 0 {
+		maxUses = 1
+	}
+	if maxUses < 0 || maxUses > maxInviteUses {
+		return nil, "", fmt.Errorf("max uses must be between 1 and %d", maxInviteUses)
+	}
+
+	expiresIn := input.ExpiresIn
+	if expiresIn == 0 {
+		expiresIn = defaultInviteExpiry
+	}
+	if expiresIn < 0 {
+		return nil, "", fmt.Errorf("expiry must be in the future")
+	}
+
+	secret, err := util.GenerateRandomToken(16)
+	if err != nil {
+		return nil, "", err
+	}
+	code := InviteCodePrefix + secret
+
+	invite := &models.Invite{
+		CodePrefix: code[:inviteCodeDisplayLength],
+		CodeHash:   util.HashToken(code),
+		Email:      email,
+		InviterID:  &inviterID,
+		MaxUses:    maxUses,
+		ExpiresAt:  time.Now().Add(expiresIn),
+	}
+	if err := s.inviteRepo.Create(invite); err != nil {
+		return nil, "", err
+	}
+
+	return invite, code, nil
+}
+
+// List returns a page of invites, newest first
+func (s *InviteService) List(ctx context.Context, page, pageSize int) ([]models.Invite, int64, error) {
+	return s.inviteRepo.List(pageSize, (page-1)*pageSize)
+}
+
+// Revoke stops an invite from being used
+func (s *InviteService) Revoke(ctx context.Context, id uint) error {
+	if _, err := s.inviteRepo.FindByID(id); err != nil {
+		return err
+	}
+	return s.inviteRepo.Revoke(id)
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"inkstack-auth/internal/config"
 	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/util"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,18 +18,57 @@ type JWTClaims struct {
 	Email    string `json:"email"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	ClientID string `json:"client_id,omitempty"` // Set for tokens issued to OAuth clients
+	Scope    string `json:"scope,omitempty"`     // Space-separated scopes granted to the OAuth client
+
+	// TokenType separates access tokens from refresh tokens, which must never be
+	// accepted as bearer tokens. Service tokens carry neither.
+	TokenType string `json:"typ,omitempty"`
+
+	// Permissions granted by the user's role when the token was issued
+	Permissions []string `json:"permissions,omitempty"`
+
+	// Actor is set when a staff member is acting as this user (RFC 8693 "act" claim)
+	Actor *ActorClaim `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// ActorClaim identifies the staff member behind an impersonation token
+type ActorClaim struct {
+	Subject  string `json:"sub"`
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+}
+
+// Token types carried in the typ claim
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// ServiceAudience is the audience of tokens that services sign to call each other.
+// Tokens issued to users and OAuth clients never carry it.
+const ServiceAudience = "service"
+
+// serviceTokenExpiry is how long a service token is valid
+const serviceTokenExpiry = 5 * time.Minute
+
+// PermissionResolver looks up the permissions granted to a role
+type PermissionResolver interface {
+	Permissions(role string) []string
+}
+
 // JWTService handles JWT token operations
 type JWTService struct {
-	config *config.Config
+	config      *config.Config
+	permissions PermissionResolver
 }
 
 // NewJWTService creates a new JWT service
-func NewJWTService(cfg *config.Config) *JWTService {
+func NewJWTService(cfg *config.Config, permissions PermissionResolver) *JWTService {
 	return &JWTService{
-		config: cfg,
+		config:      cfg,
+		permissions: permissions,
 	}
 }
 
@@ -36,10 +78,12 @@ func (s *JWTService) GenerateAccessToken(user *models.User) (string, error) {
 	expiresAt := now.Add(s.config.JWT.AccessExpiry)
 
 	claims := JWTClaims{
-		UserID:   user.ID,
-		Email:    user.Email,
-		Username: user.Username,
-		Role:     user.Role,
+		UserID:      user.ID,
+		Email:       user.Email,
+		Username:    user.Username,
+		Role:        user.Role,
+		Permissions: s.permissions.Permissions(user.Role),
+		TokenType:   TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -58,16 +102,97 @@ func (s *JWTService) GenerateAccessToken(user *models.User) (string, error) {
 	return tokenString, nil
 }
 
-// GenerateRefreshToken generates a long-lived refresh token
-func (s *JWTService) GenerateRefreshToken(user *models.User) (string, time.Time, error) {
+// GenerateImpersonationToken generates a short-lived access token for target that
+// names actor in its "act" claim. The token's ID is the impersonation session ID.
+func (s *JWTService) GenerateImpersonationToken(target, actor *models.User, sessionID string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.config.JWT.ImpersonationExpiry)
+
+	claims := JWTClaims{
+		UserID:      target.ID,
+		Email:       target.Email,
+		Username:    target.Username,
+		Role:        target.Role,
+		Permissions: s.permissions.Permissions(target.Role),
+		Actor: &ActorClaim{
+			Subject:  fmt.Sprintf("%d", actor.ID),
+			UserID:   actor.ID,
+			Username: actor.Username,
+		},
+		TokenType: TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "inkstack-auth",
+			Subject:   fmt.Sprintf("%d", target.ID),
+			ID:        sessionID,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(s.config.JWT.Secret))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign impersonation token: %w", err)
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+// GenerateRefreshToken generates a long-lived refresh token. Tokens issued to an OAuth
+// client are bound to it and to the scopes it was granted.
+func (s *JWTService) GenerateRefreshToken(user *models.User, clientID string, scopes []string) (string, time.Time, error) {
 	now := time.Now()
 	expiresAt := now.Add(s.config.JWT.RefreshExpiry)
 
+	// A unique ID keeps tokens issued in the same second distinct
+	jti, err := util.GenerateRandomToken(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	claims := JWTClaims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Username:  user.Username,
+		Role:      user.Role,
+		ClientID:  clientID,
+		Scope:     strings.Join(scopes, " "),
+		TokenType: TokenTypeRefresh,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "inkstack-auth",
+			Subject:   fmt.Sprintf("%d", user.ID),
+			ID:        jti,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(s.config.JWT.Secret))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign refresh token: %w", err)
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+// GenerateOAuthAccessToken generates an access token delegated to an OAuth client on behalf of a user
+func (s *JWTService) GenerateOAuthAccessToken(user *models.User, clientID string, scopes []string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.config.JWT.AccessExpiry)
+
 	claims := JWTClaims{
 		UserID:   user.ID,
 		Email:    user.Email,
 		Username: user.Username,
 		Role:     user.Role,
+		ClientID: clientID,
+		Scope:    strings.Join(scopes, " "),
+
+		Permissions: s.permissions.Permissions(user.Role),
+		TokenType:   TokenTypeAccess,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -80,7 +205,63 @@ func (s *JWTService) GenerateRefreshToken(user *models.User) (string, time.Time,
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	tokenString, err := token.SignedString([]byte(s.config.JWT.Secret))
 	if err != nil {
-		return "", time.Time{}, fmt.Errorf("failed to sign refresh token: %w", err)
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+// GenerateClientAccessToken generates an access token for an OAuth client acting on its own behalf
+// (client credentials grant). The token carries no user identity.
+func (s *JWTService) GenerateClientAccessToken(clientID string, scopes []string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.config.JWT.AccessExpiry)
+
+	claims := JWTClaims{
+		ClientID:  clientID,
+		Scope:     strings.Join(scopes, " "),
+		TokenType: TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "inkstack-auth",
+			Subject:   "client:" + clientID,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(s.config.JWT.Secret))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign client access token: %w", err)
+	}
+
+	return tokenString, expiresAt, nil
+}
+
+// GenerateServiceToken generates a short-lived token that identifies this service to
+// another internal service. The token carries no user identity.
+func (s *JWTService) GenerateServiceToken(clientID string, scopes []string) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(serviceTokenExpiry)
+
+	claims := JWTClaims{
+		ClientID: clientID,
+		Scope:    strings.Join(scopes, " "),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "inkstack-auth",
+			Subject:   "service:" + clientID,
+			Audience:  jwt.ClaimStrings{ServiceAudience},
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, err := token.SignedString([]byte(s.config.JWT.Secret))
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign service token: %w", err)
 	}
 
 	return tokenString, expiresAt, nil
@@ -112,6 +293,47 @@ func (s *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
 	return claims, nil
 }
 
+// ValidateAccessToken validates a JWT and checks that it is an access token, not a
+// refresh or service token
+func (s *JWTService) ValidateAccessToken(tokenString string) (*JWTClaims, error) {
+	return s.validateTokenType(tokenString, TokenTypeAccess)
+}
+
+// ValidateRefreshToken validates a JWT and checks that it is a refresh token.
+// Refresh tokens issued before the typ claim existed carry none and are accepted until
+// they expire, so deploying typed tokens doesn't sign everyone out. That is safe because
+// every refresh also requires the token's row in refresh_tokens, which never holds an
+// access token, and legacy tokens take their client binding from that row.
+func (s *JWTService) ValidateRefreshToken(tokenString string) (*JWTClaims, error) {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType == "" && !slices.Contains(claims.Audience, ServiceAudience) {
+		return claims, nil
+	}
+	if claims.TokenType != TokenTypeRefresh {
+		return nil, fmt.Errorf("unexpected token type %q", claims.TokenType)
+	}
+
+	return claims, nil
+}
+
+// validateTokenType validates a JWT and checks its typ claim
+func (s *JWTService) validateTokenType(tokenString, tokenType string) (*JWTClaims, error) {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != tokenType {
+		return nil, fmt.Errorf("unexpected token type %q", claims.TokenType)
+	}
+
+	return claims, nil
+}
+
 // ExtractUserIDFromToken extracts user ID from token without full validation
 // Used for logging/tracking purposes
 func (s *JWTService) ExtractUserIDFromToken(tokenString string) (uint, error) {
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// noPermissions grants no permissions to any role
+type noPermissions struct{}
+
+func (noPermissions) Permissions(role string) []string { return nil }
+
+func testJWTService() *JWTService {
+	cfg := &config.Config{JWT: config.JWTConfig{
+		Secret:        strings.Repeat("s", 32),
+		AccessExpiry:  15 * time.Minute,
+		RefreshExpiry: time.Hour,
+	}}
+	return NewJWTService(cfg, noPermissions{})
+}
+
+// signClaims signs claims the way tokens were issued before the typ claim existed
+func signClaims(t *testing.T, s *JWTService, claims JWTClaims) string {
+	t.Helper()
+	claims.RegisteredClaims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Hour))
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.config.JWT.Secret))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return token
+}
+
+func TestValidateRefreshToken(t *testing.T) {
+	s := testJWTService()
+	user := &models.User{BaseModel: models.BaseModel{ID: 7}, Username: "ada", Role: "author"}
+
+	refresh, _, err := s.GenerateRefreshToken(user, "", nil)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken: %v", err)
+	}
+	access, err := s.GenerateAccessToken(user)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	serviceToken, _, err := s.GenerateServiceToken("inkstack-api", []string{"users:read"})
+	if err != nil {
+		t.Fatalf("GenerateServiceToken: %v", err)
+	}
+	legacy := signClaims(t, s, JWTClaims{UserID: user.ID, Username: user.Username})
+
+	for _, tc := range []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"refresh token", refresh, true},
+		{"legacy refresh token without typ", legacy, true},
+		{"access token", access, false},
+		{"service token", serviceToken, false},
+	} {
+		if _, err := s.ValidateRefreshToken(tc.token); (err == nil) != tc.valid {
+			t.Errorf("%s: ValidateRefreshToken error = %v, want valid %v", tc.name, err, tc.valid)
+		}
+	}
+
+	if _, err := s.ValidateAccessToken(refresh); err == nil {
+		t.Error("ValidateAccessToken accepted a refresh token")
+	}
+}
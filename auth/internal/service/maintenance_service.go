@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/database"
+	"inkstack-auth/internal/repository"
+)
+
+// MaintenanceService purges expired credentials and repairs stale rate limiting state
+type MaintenanceService struct {
+	tokenRepo     repository.TokenRepository
+	oauthCodeRepo repository.OAuthAuthorizationCodeRepository
+	config        *config.Config
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(
+	tokenRepo repository.TokenRepository,
+	oauthCodeRepo repository.OAuthAuthorizationCodeRepository,
+	cfg *config.Config,
+) *MaintenanceService {
+	return &MaintenanceService{
+		tokenRepo:     tokenRepo,
+		oauthCodeRepo: oauthCodeRepo,
+		config:        cfg,
+	}
+}
+
+// CleanupTokens deletes expired refresh tokens, revoked refresh tokens past their
+// retention and expired OAuth authorization codes. Returns the total rows deleted.
+func (s *MaintenanceService) CleanupTokens(ctx context.Context) (int64, error) {
+	expired, err := s.tokenRepo.DeleteExpired()
+	if err != nil {
+		return 0, err
+	}
+
+	// Revoked tokens are kept for a while so that reusing one is still detected
+	revoked, err := s.tokenRepo.CleanupRevokedTokens(s.config.Scheduler.RevokedTokenRetention)
+	if err != nil {
+		return expired, err
+	}
+
+	codes, err := s.oauthCodeRepo.DeleteExpired()
+	if err != nil {
+		return expired + revoked, err
+	}
+
+	return expired + revoked + codes, nil
+}
+
+// RepairLoginAttempts restores the expiry on failed login counters that lost it
+func (s *MaintenanceService) RepairLoginAttempts(ctx context.Context) (int64, error) {
+	return database.RepairLoginAttempts(ctx)
+}
@@ -0,0 +1,709 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/database"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/util"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// OAuth error codes (RFC 6749 section 5.2)
+const (
+	OAuthErrInvalidRequest       = "invalid_request"
+	OAuthErrInvalidClient        = "invalid_client"
+	OAuthErrInvalidGrant         = "invalid_grant"
+	OAuthErrUnauthorizedClient   = "unauthorized_client"
+	OAuthErrUnsupportedGrantType = "unsupported_grant_type"
+	OAuthErrInvalidScope         = "invalid_scope"
+	OAuthErrAccessDenied         = "access_denied"
+	OAuthErrServerError          = "server_error"
+)
+
+// PKCE code challenge methods
+const (
+	CodeChallengeMethodS256 = "S256"
+)
+
+// OAuthError is an error that maps onto an OAuth error response
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+func newOAuthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+// OAuthService implements the OAuth2 authorization server
+type OAuthService struct {
+	clientRepo  repository.OAuthClientRepository
+	codeRepo    repository.OAuthAuthorizationCodeRepository
+	consentRepo repository.OAuthConsentRepository
+	userRepo    repository.UserRepository
+	tokenRepo   repository.TokenRepository
+	jwtService  *JWTService
+	config      *config.Config
+}
+
+// NewOAuthService creates a new OAuth service
+func NewOAuthService(
+	clientRepo repository.OAuthClientRepository,
+	codeRepo repository.OAuthAuthorizationCodeRepository,
+	consentRepo repository.OAuthConsentRepository,
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	jwtService *JWTService,
+	cfg *config.Config,
+) *OAuthService {
+	return &OAuthService{
+		clientRepo:  clientRepo,
+		codeRepo:    codeRepo,
+		consentRepo: consentRepo,
+		userRepo:    userRepo,
+		tokenRepo:   tokenRepo,
+		jwtService:  jwtService,
+		config:      cfg,
+	}
+}
+
+// RegisterClientInput contains data for registering an OAuth client
+type RegisterClientInput struct {
+	Name         string
+	RedirectURIs []string
+	GrantTypes   []string
+	Scopes       []string
+	Confidential bool
+}
+
+// AuthorizeRequest contains the parameters of an authorization request
+type AuthorizeRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// ConsentDetails is the data a consent screen needs to render
+type ConsentDetails struct {
+	Client            *models.OAuthClient
+	Scopes            []string
+	RedirectURI       string
+	State             string
+	PreviouslyGranted bool
+}
+
+// TokenRequest contains the parameters of a token request
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+	ClientID     string
+	ClientSecret string
+}
+
+// OAuthTokenResponse is a successful token response (RFC 6749 section 5.1)
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// IntrospectionResponse is a token introspection response (RFC 7662 section 2.2)
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	Iss       string `json:"iss,omitempty"`
+}
+
+// RegisterClient registers a new OAuth client. The plaintext secret is only returned here
+// and is empty for public clients.
+func (s *OAuthService) RegisterClient(ctx context.Context, ownerID uint, input RegisterClientInput) (*models.OAuthClient, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, "", fmt.Errorf("client name is required")
+	}
+
+	grantTypes := input.GrantTypes
+	if len(grantTypes) == 0 {
+		grantTypes = []string{models.GrantTypeAuthorizationCode, models.GrantTypeRefreshToken}
+	}
+	for _, grantType := range grantTypes {
+		switch grantType {
+		case models.GrantTypeAuthorizationCode, models.GrantTypeRefreshToken:
+		case models.GrantTypeClientCredentials:
+			if !input.Confidential {
+				return nil, "", fmt.Errorf("public clients cannot use the client_credentials grant")
+			}
+		default:
+			return nil, "", fmt.Errorf("unsupported grant type: %s", grantType)
+		}
+	}
+
+	usesCode := containsString(grantTypes, models.GrantTypeAuthorizationCode)
+	if usesCode && len(input.RedirectURIs) == 0 {
+		return nil, "", fmt.Errorf("at least one redirect URI is required")
+	}
+	for _, uri := range input.RedirectURIs {
+		if err := validateRedirectURI(uri); err != nil {
+			return nil, "", err
+		}
+	}
+
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	clientID, err := util.GenerateRandomToken(24)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := &models.OAuthClient{
+		ClientID:       clientID,
+		Name:           name,
+		OwnerID:        ownerID,
+		RedirectURIs:   strings.Join(input.RedirectURIs, " "),
+		GrantTypes:     strings.Join(grantTypes, " "),
+		Scopes:         strings.Join(scopes, " "),
+		IsConfidential: input.Confidential,
+		IsActive:       true,
+	}
+
+	var secret string
+	if input.Confidential {
+		secret, err = util.GenerateRandomToken(32)
+		if err != nil {
+			return nil, "", err
+		}
+		client.ClientSecretHash = util.HashToken(secret)
+	}
+
+	if err := s.clientRepo.Create(client); err != nil {
+		return nil, "", err
+	}
+
+	return client, secret, nil
+}
+
+// ListClients returns the OAuth clients registered by a user
+func (s *OAuthService) ListClients(ctx context.Context, ownerID uint) ([]models.OAuthClient, error) {
+	return s.clientRepo.FindByOwnerID(ownerID)
+}
+
+// DeleteClient deletes one of the user's OAuth clients
+func (s *OAuthService) DeleteClient(ctx context.Context, ownerID uint, clientID string) error {
+	client, err := s.clientRepo.FindByClientID(clientID)
+	if err != nil || client.OwnerID != ownerID {
+		return fmt.Errorf("oauth client not found")
+	}
+
+	return s.clientRepo.Delete(client.ID)
+}
+
+// PrepareAuthorization validates an authorization request and returns the consent screen data
+func (s *OAuthService) PrepareAuthorization(ctx context.Context, userID uint, req AuthorizeRequest) (*ConsentDetails, error) {
+	client, scopes, err := s.validateAuthorizeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	previouslyGranted := false
+	if consent, err := s.consentRepo.Find(userID, client.ClientID); err == nil {
+		previouslyGranted = consent.Covers(scopes)
+	}
+
+	return &ConsentDetails{
+		Client:            client,
+		Scopes:            scopes,
+		RedirectURI:       req.RedirectURI,
+		State:             req.State,
+		PreviouslyGranted: previouslyGranted,
+	}, nil
+}
+
+// Authorize records the user's consent decision and returns the URL to redirect the user agent to
+func (s *OAuthService) Authorize(ctx context.Context, userID uint, req AuthorizeRequest, approved bool) (string, error) {
+	client, scopes, err := s.validateAuthorizeRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+
+	if !approved {
+		params.Set("error", OAuthErrAccessDenied)
+		params.Set("error_description", "the user denied the request")
+		return appendQuery(req.RedirectURI, params), nil
+	}
+
+	if err := s.saveConsent(userID, client.ClientID, scopes); err != nil {
+		return "", err
+	}
+
+	code, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	authCode := &models.OAuthAuthorizationCode{
+		CodeHash:            util.HashToken(code),
+		ClientID:            client.ClientID,
+		UserID:              userID,
+		RedirectURI:         req.RedirectURI,
+		Scopes:              strings.Join(scopes, " "),
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		ExpiresAt:           time.Now().Add(s.config.OAuth.CodeExpiry),
+	}
+	if err := s.codeRepo.Create(authCode); err != nil {
+		return "", err
+	}
+
+	params.Set("code", code)
+	return appendQuery(req.RedirectURI, params), nil
+}
+
+// Token handles a token request for any supported grant type
+func (s *OAuthService) Token(ctx context.Context, req TokenRequest) (*OAuthTokenResponse, error) {
+	client, err := s.AuthenticateClient(req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if !client.AllowsGrantType(req.GrantType) {
+		switch req.GrantType {
+		case models.GrantTypeAuthorizationCode, models.GrantTypeRefreshToken, models.GrantTypeClientCredentials:
+			return nil, newOAuthError(OAuthErrUnauthorizedClient, "client is not allowed to use this grant type")
+		default:
+			return nil, newOAuthError(OAuthErrUnsupportedGrantType, "unsupported grant type")
+		}
+	}
+
+	switch req.GrantType {
+	case models.GrantTypeAuthorizationCode:
+		return s.exchangeAuthorizationCode(ctx, client, req)
+	case models.GrantTypeRefreshToken:
+		return s.exchangeRefreshToken(ctx, client, req)
+	default:
+		return s.issueClientCredentials(ctx, client, req)
+	}
+}
+
+// AuthenticateClient verifies client credentials. Public clients authenticate with their ID only.
+func (s *OAuthService) AuthenticateClient(clientID, clientSecret string) (*models.OAuthClient, error) {
+	if clientID == "" {
+		return nil, newOAuthError(OAuthErrInvalidClient, "client authentication failed")
+	}
+
+	client, err := s.clientRepo.FindByClientID(clientID)
+	if err != nil || !client.IsActive {
+		return nil, newOAuthError(OAuthErrInvalidClient, "client authentication failed")
+	}
+
+	if client.IsConfidential {
+		hash := util.HashToken(clientSecret)
+		if clientSecret == "" || subtle.ConstantTimeCompare([]byte(hash), []byte(client.ClientSecretHash)) != 1 {
+			return nil, newOAuthError(OAuthErrInvalidClient, "client authentication failed")
+		}
+	}
+
+	return client, nil
+}
+
+// Introspect reports whether a token is active (RFC 7662). Only confidential clients may introspect.
+func (s *OAuthService) Introspect(ctx context.Context, client *models.OAuthClient, token, tokenTypeHint string) *IntrospectionResponse {
+	inactive := &IntrospectionResponse{Active: false}
+	if !client.IsConfidential || token == "" {
+		return inactive
+	}
+
+	if tokenTypeHint != models.GrantTypeRefreshToken {
+		if resp := s.introspectAccessToken(ctx, token); resp != nil {
+			return resp
+		}
+	}
+
+	// Refresh tokens may only be introspected by the client they were issued to
+	record, err := s.tokenRepo.FindByToken(token)
+	if err != nil || record.ClientID != client.ClientID || !record.IsValid() {
+		return inactive
+	}
+
+	claims, err := s.jwtService.ValidateRefreshToken(token)
+	if err != nil || !refreshTokenIssuedTo(claims, client.ClientID) {
+		return inactive
+	}
+
+	user, err := s.userRepo.FindByID(record.UserID)
+	if err != nil || !user.IsActive {
+		return inactive
+	}
+
+	return &IntrospectionResponse{
+		Active:    true,
+		Scope:     record.Scopes,
+		ClientID:  record.ClientID,
+		Username:  user.Username,
+		TokenType: models.GrantTypeRefreshToken,
+		Exp:       record.ExpiresAt.Unix(),
+		Iat:       claims.IssuedAt.Unix(),
+		Sub:       claims.Subject,
+		Iss:       claims.Issuer,
+	}
+}
+
+// Revoke revokes a token issued to the client (RFC 7009). Unknown tokens are ignored.
+func (s *OAuthService) Revoke(ctx context.Context, client *models.OAuthClient, token, tokenTypeHint string) error {
+	if token == "" {
+		return newOAuthError(OAuthErrInvalidRequest, "token is required")
+	}
+
+	if record, err := s.tokenRepo.FindByToken(token); err == nil {
+		if record.ClientID == client.ClientID {
+			return s.tokenRepo.RevokeToken(token)
+		}
+		return nil
+	}
+
+	claims, err := s.jwtService.ValidateAccessToken(token)
+	if err != nil || claims.ClientID != client.ClientID {
+		return nil
+	}
+
+	if ttl := time.Until(claims.ExpiresAt.Time); ttl > 0 {
+		database.BlacklistToken(ctx, token, ttl)
+	}
+
+	return nil
+}
+
+// exchangeAuthorizationCode handles the authorization_code grant
+func (s *OAuthService) exchangeAuthorizationCode(ctx context.Context, client *models.OAuthClient, req TokenRequest) (*OAuthTokenResponse, error) {
+	if req.Code == "" || req.RedirectURI == "" {
+		return nil, newOAuthError(OAuthErrInvalidRequest, "code and redirect_uri are required")
+	}
+
+	code, err := s.codeRepo.FindByHash(util.HashToken(req.Code))
+	if err != nil || code.ClientID != client.ClientID {
+		return nil, newOAuthError(OAuthErrInvalidGrant, "invalid authorization code")
+	}
+
+	if code.RedirectURI != req.RedirectURI {
+		return nil, newOAuthError(OAuthErrInvalidGrant, "redirect_uri does not match")
+	}
+
+	if code.IsExpired() {
+		return nil, newOAuthError(OAuthErrInvalidGrant, "authorization code has expired")
+	}
+
+	if code.CodeChallenge != "" && !verifyCodeChallenge(code.CodeChallenge, req.CodeVerifier) {
+		return nil, newOAuthError(OAuthErrInvalidGrant, "invalid code_verifier")
+	}
+
+	used, err := s.codeRepo.MarkUsed(code.ID)
+	if err != nil {
+		return nil, newOAuthError(OAuthErrServerError, "failed to redeem authorization code")
+	}
+	if !used {
+		// A replayed code may have been intercepted; revoke everything issued from this grant
+		s.tokenRepo.RevokeClientTokens(code.UserID, client.ClientID)
+		return nil, newOAuthError(OAuthErrInvalidGrant, "authorization code has already been used")
+	}
+
+	user, err := s.userRepo.FindByID(code.UserID)
+	if err != nil || !user.IsActive {
+		return nil, newOAuthError(OAuthErrInvalidGrant, "user is not active")
+	}
+
+	return s.issueUserTokens(client, user, code.ScopeList())
+}
+
+// exchangeRefreshToken handles the refresh_token grant with rotation
+func (s *OAuthService) exchangeRefreshToken(ctx context.Context, client *models.OAuthClient, req TokenRequest) (*OAuthTokenResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, newOAuthError(OAuthErrInvalidRequest, "refresh_token is required")
+	}
+
+	record, err := s.tokenRepo.FindByToken(req.RefreshToken)
+	if err != nil || record.ClientID != client.ClientID {
+		return nil, newOAuthError(OAuthErrInvalidGrant, "invalid refresh token")
+	}
+
+	if !record.IsValid() {
+		return nil, newOAuthError(OAuthErrInvalidGrant, "refresh token is invalid or expired")
+	}
+
+	claims, err := s.jwtService.ValidateRefreshToken(req.RefreshToken)
+	if err != nil || !refreshTokenIssuedTo(claims, client.ClientID) {
+		return nil, newOAuthError(OAuthErrInvalidGrant, "invalid refresh token")
+	}
+
+	// A refresh may narrow but never widen the original grant
+	scopes := strings.Fields(record.Scopes)
+	if req.Scope != "" {
+		requested := strings.Fields(req.Scope)
+		for _, scope := range requested {
+			if !containsString(scopes, scope) {
+				return nil, newOAuthError(OAuthErrInvalidScope, "requested scope exceeds the original grant")
+			}
+		}
+		scopes = requested
+	}
+
+	user, err := s.userRepo.FindByID(record.UserID)
+	if err != nil || !user.IsActive {
+		return nil, newOAuthError(OAuthErrInvalidGrant, "user is not active")
+	}
+
+	if err := s.tokenRepo.RevokeToken(req.RefreshToken); err != nil {
+		return nil, newOAuthError(OAuthErrServerError, "failed to rotate refresh token")
+	}
+
+	return s.issueUserTokens(client, user, scopes)
+}
+
+// issueClientCredentials handles the client_credentials grant
+func (s *OAuthService) issueClientCredentials(ctx context.Context, client *models.OAuthClient, req TokenRequest) (*OAuthTokenResponse, error) {
+	if !client.IsConfidential {
+		return nil, newOAuthError(OAuthErrUnauthorizedClient, "public clients cannot use client_credentials")
+	}
+
+	scopes := client.ScopeList()
+	if req.Scope != "" {
+		scopes = strings.Fields(req.Scope)
+		for _, scope := range scopes {
+			if !client.AllowsScope(scope) {
+				return nil, newOAuthError(OAuthErrInvalidScope, "scope not allowed for this client")
+			}
+		}
+	}
+
+	accessToken, expiresAt, err := s.jwtService.GenerateClientAccessToken(client.ClientID, scopes)
+	if err != nil {
+		return nil, newOAuthError(OAuthErrServerError, "failed to issue access token")
+	}
+
+	return &OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(expiresAt).Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}, nil
+}
+
+// issueUserTokens issues an access token and, if allowed, a refresh token bound to the client
+func (s *OAuthService) issueUserTokens(client *models.OAuthClient, user *models.User, scopes []string) (*OAuthTokenResponse, error) {
+	accessToken, expiresAt, err := s.jwtService.GenerateOAuthAccessToken(user, client.ClientID, scopes)
+	if err != nil {
+		return nil, newOAuthError(OAuthErrServerError, "failed to issue access token")
+	}
+
+	response := &OAuthTokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(time.Until(expiresAt).Seconds()),
+		Scope:       strings.Join(scopes, " "),
+	}
+
+	if client.AllowsGrantType(models.GrantTypeRefreshToken) {
+		refreshToken, refreshExpiresAt, err := s.jwtService.GenerateRefreshToken(user, client.ClientID, scopes)
+		if err != nil {
+			return nil, newOAuthError(OAuthErrServerError, "failed to issue refresh token")
+		}
+
+		if err := s.tokenRepo.Create(&models.RefreshToken{
+			UserID:    user.ID,
+			Token:     refreshToken,
+			ExpiresAt: refreshExpiresAt,
+			ClientID:  client.ClientID,
+			Scopes:    response.Scope,
+		}); err != nil {
+			return nil, newOAuthError(OAuthErrServerError, "failed to store refresh token")
+		}
+
+		response.RefreshToken = refreshToken
+	}
+
+	return response, nil
+}
+
+// introspectAccessToken introspects a JWT access token, returning nil if it is not one
+func (s *OAuthService) introspectAccessToken(ctx context.Context, token string) *IntrospectionResponse {
+	claims, err := s.jwtService.ValidateAccessToken(token)
+	if err != nil {
+		return nil
+	}
+
+	if blacklisted, err := database.IsTokenBlacklisted(ctx, token); err == nil && blacklisted {
+		return &IntrospectionResponse{Active: false}
+	}
+
+	response := &IntrospectionResponse{
+		Active:    true,
+		Scope:     claims.Scope,
+		ClientID:  claims.ClientID,
+		TokenType: "access_token",
+		Exp:       claims.ExpiresAt.Unix(),
+		Iat:       claims.IssuedAt.Unix(),
+		Sub:       claims.Subject,
+		Iss:       claims.Issuer,
+	}
+
+	if claims.UserID != 0 {
+		user, err := s.userRepo.FindByID(claims.UserID)
+		if err != nil || !user.IsActive {
+			return &IntrospectionResponse{Active: false}
+		}
+		response.Username = user.Username
+	}
+
+	return response
+}
+
+// validateAuthorizeRequest checks an authorization request and resolves the requested scopes
+func (s *OAuthService) validateAuthorizeRequest(req AuthorizeRequest) (*models.OAuthClient, []string, error) {
+	client, err := s.clientRepo.FindByClientID(req.ClientID)
+	if err != nil || !client.IsActive {
+		return nil, nil, newOAuthError(OAuthErrInvalidClient, "unknown client")
+	}
+
+	if !client.HasRedirectURI(req.RedirectURI) {
+		return nil, nil, newOAuthError(OAuthErrInvalidRequest, "redirect_uri is not registered for this client")
+	}
+
+	if req.ResponseType != "code" {
+		return nil, nil, newOAuthError(OAuthErrInvalidRequest, "response_type must be code")
+	}
+
+	if !client.AllowsGrantType(models.GrantTypeAuthorizationCode) {
+		return nil, nil, newOAuthError(OAuthErrUnauthorizedClient, "client is not allowed to use the authorization code flow")
+	}
+
+	// PKCE is mandatory for public clients and optional for confidential ones
+	if req.CodeChallenge == "" && !client.IsConfidential {
+		return nil, nil, newOAuthError(OAuthErrInvalidRequest, "code_challenge is required for public clients")
+	}
+	if req.CodeChallenge != "" && req.CodeChallengeMethod != CodeChallengeMethodS256 {
+		return nil, nil, newOAuthError(OAuthErrInvalidRequest, "code_challenge_method must be S256")
+	}
+
+	scopes := client.ScopeList()
+	if req.Scope != "" {
+		scopes = strings.Fields(req.Scope)
+		for _, scope := range scopes {
+			if !client.AllowsScope(scope) {
+				return nil, nil, newOAuthError(OAuthErrInvalidScope, "scope not allowed for this client: "+scope)
+			}
+		}
+	}
+
+	return client, scopes, nil
+}
+
+// saveConsent remembers the scopes a user granted so the consent screen can be skipped next time
+func (s *OAuthService) saveConsent(userID uint, clientID string, scopes []string) error {
+	consent, err := s.consentRepo.Find(userID, clientID)
+	if err != nil {
+		consent = &models.OAuthConsent{UserID: userID, ClientID: clientID}
+	}
+
+	granted := consent.ScopeList()
+	for _, scope := range scopes {
+		if !containsString(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	consent.Scopes = strings.Join(granted, " ")
+
+	return s.consentRepo.Save(consent)
+}
+
+// verifyCodeChallenge checks a PKCE code verifier against the stored S256 challenge
+func verifyCodeChallenge(challenge, verifier string) bool {
+	// RFC 7636 section 4.1: 43-128 characters
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// validateRedirectURI checks that a redirect URI is absolute, has no fragment and uses
+// HTTPS unless it points at a loopback address
+func validateRedirectURI(uri string) error {
+	parsed, err := url.Parse(uri)
+	if err != nil || !parsed.IsAbs() || parsed.Host == "" {
+		return fmt.Errorf("invalid redirect URI: %s", uri)
+	}
+
+	if parsed.Fragment != "" {
+		return fmt.Errorf("redirect URI must not contain a fragment: %s", uri)
+	}
+
+	host := parsed.Hostname()
+	isLoopback := host == "localhost" || host == "127.0.0.1" || host == "::1"
+	if parsed.Scheme != "https" && !(parsed.Scheme == "http" && isLoopback) {
+		return fmt.Errorf("redirect URI must use https: %s", uri)
+	}
+
+	return nil
+}
+
+// appendQuery adds query parameters to a URL that may already have some
+func appendQuery(rawURL string, params url.Values) string {
+	separator := "?"
+	if strings.Contains(rawURL, "?") {
+		separator = "&"
+	}
+	return rawURL + separator + params.Encode()
+}
+
+// containsString checks if a slice contains a value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshTokenIssuedTo reports whether refresh token claims are bound to the client.
+// Legacy tokens without a typ claim carry no client; the caller has already checked
+// the client of their refresh_tokens row.
+func refreshTokenIssuedTo(claims *JWTClaims, clientID string) bool {
+	return claims.ClientID == clientID || claims.TokenType == ""
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/util"
+	"log"
+	"os"
+)
+
+// PasswordPolicy decides whether a new password is acceptable
+type PasswordPolicy struct {
+	breached *util.BloomFilter
+	minScore int
+}
+
+// NewPasswordPolicy creates a password policy, loading the breached password filter if one is configured
+func NewPasswordPolicy(cfg *config.Config) (*PasswordPolicy, error) {
+	policy := &PasswordPolicy{
+		minScore: cfg.Password.MinStrength,
+	}
+
+	if cfg.Password.BreachFilterPath == "" {
+		log.Println("Breached password filter not configured, skipping breached password checks")
+		return policy, nil
+	}
+
+	file, err := os.Open(cfg.Password.BreachFilterPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password filter: %w", err)
+	}
+	defer file.Close()
+
+	policy.breached, err = util.ReadBloomFilter(file)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Loaded breached password filter from %s", cfg.Password.BreachFilterPath)
+	return policy, nil
+}
+
+// IsBreached reports whether the password appears in the breached password filter
+func (p *PasswordPolicy) IsBreached(password string) bool {
+	if p.breached == nil {
+		return false
+	}
+	digest := sha1.Sum([]byte(password))
+	return p.breached.Test(digest[:])
+}
+
+// Estimate estimates the strength of a password for the given user details
+func (p *PasswordPolicy) Estimate(password string, userInputs ...string) util.PasswordStrength {
+	return util.EstimatePasswordStrength(password, userInputs...)
+}
+
+// Validate checks a new password against the character rules, the breached password
+// filter and the minimum strength score. userInputs are the user's username and email.
+func (p *PasswordPolicy) Validate(password string, userInputs ...string) error {
+	if err := util.ValidatePasswordStrength(password); err != nil {
+		return err
+	}
+
+	if p.IsBreached(password) {
+		return fmt.Errorf("this password has appeared in a data breach, please choose a different one")
+	}
+
+	strength := p.Estimate(password, userInputs...)
+	if strength.Score < p.minScore {
+		if strength.Warning != "" {
+			return fmt.Errorf("password is too easy to guess: %s", strength.Warning)
+		}
+		return fmt.Errorf("password is too easy to guess")
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/util"
+	"strings"
+	"time"
+)
+
+const (
+	// PersonalAccessTokenPrefix marks a bearer token as a personal access token rather than a JWT
+	PersonalAccessTokenPrefix = "inkpat_"
+
+	// personalAccessTokenDisplayLength is how much of the token is kept for identification
+	personalAccessTokenDisplayLength = 12
+
+	// maxPersonalAccessTokensPerUser limits how many active tokens a user can hold
+	maxPersonalAccessTokensPerUser = 50
+)
+
+// PersonalAccessTokenService handles personal access token operations
+type PersonalAccessTokenService struct {
+	patRepo  repository.PersonalAccessTokenRepository
+	userRepo repository.UserRepository
+}
+
+// NewPersonalAccessTokenService creates a new personal access token service
+func NewPersonalAccessTokenService(
+	patRepo repository.PersonalAccessTokenRepository,
+	userRepo repository.UserRepository,
+) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{
+		patRepo:  patRepo,
+		userRepo: userRepo,
+	}
+}
+
+// CreatePersonalAccessTokenInput contains data for a new personal access token
+type CreatePersonalAccessTokenInput struct {
+	Name      string
+	Scopes    []string
+	ExpiresIn time.Duration // Zero means the token never expires
+}
+
+// PersonalAccessTokenIdentity is the result of validating a personal access token
+type PersonalAccessTokenIdentity struct {
+	User  *models.User
+	Token *models.PersonalAccessToken
+}
+
+// IsPersonalAccessToken reports whether a bearer token looks like a personal access token
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// Create issues a new personal access token and returns the record with the plaintext token.
+// The plaintext is only available at creation time.
+func (s *PersonalAccessTokenService) Create(ctx context.Context, userID uint, input CreatePersonalAccessTokenInput) (*models.PersonalAccessToken, string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, "", fmt.Errorf("token name is required")
+	}
+	if len(name) > 100 {
+		return nil, "", fmt.Errorf("token name must not exceed 100 characters")
+	}
+
+	scopes, err := normalizeScopes(input.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if input.ExpiresIn < 0 {
+		return nil, "", fmt.Errorf("expiry must be in the future")
+	}
+
+	existing, err := s.patRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, "", err
+	}
+	active := 0
+	for _, t := range existing {
+		if t.IsValid() {
+			active++
+		}
+	}
+	if active >= maxPersonalAccessTokensPerUser {
+		return nil, "", fmt.Errorf("maximum of %d active tokens reached", maxPersonalAccessTokensPerUser)
+	}
+
+	secret, err := util.GenerateRandomToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	plaintext := PersonalAccessTokenPrefix + secret
+
+	token := &models.PersonalAccessToken{
+		UserID:      userID,
+		Name:        name,
+		TokenPrefix: plaintext[:personalAccessTokenDisplayLength],
+		TokenHash:   util.HashToken(plaintext),
+		Scopes:      strings.Join(scopes, " "),
+	}
+	if input.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(input.ExpiresIn)
+		token.ExpiresAt = &expiresAt
+	}
+
+	if err := s.patRepo.Create(token); err != nil {
+		return nil, "", err
+	}
+
+	return token, plaintext, nil
+}
+
+// List returns all personal access tokens belonging to a user
+func (s *PersonalAccessTokenService) List(ctx context.Context, userID uint) ([]models.PersonalAccessToken, error) {
+	return s.patRepo.FindByUserID(userID)
+}
+
+// Revoke revokes one of the user's personal access tokens
+func (s *PersonalAccessTokenService) Revoke(ctx context.Context, userID, tokenID uint) error {
+	token, err := s.patRepo.FindByID(tokenID)
+	if err != nil || token.UserID != userID {
+		return fmt.Errorf("personal access token not found")
+	}
+
+	if token.RevokedAt != nil {
+		return nil
+	}
+
+	return s.patRepo.Revoke(token.ID)
+}
+
+// Validate checks a plaintext personal access token and records its usage
+func (s *PersonalAccessTokenService) Validate(ctx context.Context, plaintext, ipAddress string) (*PersonalAccessTokenIdentity, error) {
+	if !IsPersonalAccessToken(plaintext) {
+		return nil, fmt.Errorf("invalid personal access token")
+	}
+
+	token, err := s.patRepo.FindByHash(util.HashToken(plaintext))
+	if err != nil {
+		return nil, fmt.Errorf("invalid personal access token")
+	}
+
+	if !token.IsValid() {
+		return nil, fmt.Errorf("personal access token is revoked or expired")
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if !user.IsActive {
+		return nil, fmt.Errorf("account is inactive")
+	}
+
+	// Usage tracking is best-effort and must not fail the request
+	s.patRepo.UpdateLastUsed(token.ID, ipAddress)
+
+	return &PersonalAccessTokenIdentity{
+		User:  user,
+		Token: token,
+	}, nil
+}
+
+// normalizeScopes validates and de-duplicates requested scopes
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("at least one scope is required")
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !models.IsValidScope(scope) {
+			return nil, fmt.Errorf("unknown scope: %s", scope)
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		result = append(result, scope)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"inkstack-auth/internal/database"
+	"inkstack-auth/internal/mailer"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/util"
+	"math/big"
+	"time"
+)
+
+// Sensitive actions that need the user to authenticate again
+const (
+	ReauthDeleteAccount = "delete_account"
+	ReauthChangeEmail   = "change_email"
+)
+
+const (
+	// reauthCodeExpiry is how long an emailed confirmation code is valid
+	reauthCodeExpiry = 15 * time.Minute
+
+	// maxReauthCodes is how many confirmation codes a user may be sent per 15 minutes
+	maxReauthCodes = 3
+)
+
+// ErrConfirmationCodeSent is returned when an account without a password has been
+// emailed a confirmation code. The action succeeds when repeated with the code.
+var ErrConfirmationCodeSent = errors.New("a confirmation code has been sent to your email address")
+
+// reauthActionNames describe actions in confirmation emails
+var reauthActionNames = map[string]string{
+	ReauthDeleteAccount: "delete your account",
+	ReauthChangeEmail:   "change your email address",
+}
+
+// Reauthenticator confirms that the user behind a session is present before a
+// sensitive action. Users enter their password; accounts created by magic link have
+// none, so they confirm with a single-use code emailed to their address instead.
+type Reauthenticator struct {
+	mailer mailer.Mailer
+}
+
+// NewReauthenticator creates a new reauthenticator
+func NewReauthenticator(mailer mailer.Mailer) *Reauthenticator {
+	return &Reauthenticator{
+		mailer: mailer,
+	}
+}
+
+// Verify checks the password of an account that has one. For an account without a
+// password it checks code, or emails a new code and returns ErrConfirmationCodeSent
+// when code is empty.
+func (r *Reauthenticator) Verify(ctx context.Context, user *models.User, action, password, code string) error {
+	if user.PasswordHash != "" {
+		if !util.ComparePassword(user.PasswordHash, password) {
+			return fmt.Errorf("invalid password")
+		}
+		return nil
+	}
+
+	if code == "" {
+		if err := r.sendCode(ctx, user, action); err != nil {
+			return err
+		}
+		return ErrConfirmationCodeSent
+	}
+
+	codeHash, err := database.ConsumeReauthCode(ctx, user.ID, action)
+	if err != nil {
+		return fmt.Errorf("failed to check confirmation code: %w", err)
+	}
+	if codeHash == "" || subtle.ConstantTimeCompare([]byte(codeHash), []byte(util.HashToken(code))) != 1 {
+		return fmt.Errorf("invalid or expired confirmation code")
+	}
+
+	return nil
+}
+
+// sendCode emails a new confirmation code for action, replacing any earlier one
+func (r *Reauthenticator) sendCode(ctx context.Context, user *models.User, action string) error {
+	result, err := database.AllowRequest(ctx, fmt.Sprintf("reauth:%d", user.ID), maxReauthCodes, 15*time.Minute)
+	if err == nil && !result.Allowed {
+		return fmt.Errorf("too many confirmation codes requested, try again later")
+	}
+
+	code, err := generateConfirmationCode()
+	if err != nil {
+		return err
+	}
+
+	if err := database.StoreReauthCode(ctx, user.ID, action, util.HashToken(code), reauthCodeExpiry); err != nil {
+		return fmt.Errorf("failed to store confirmation code: %w", err)
+	}
+
+	if err := r.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your Inkstack confirmation code",
+		Body: fmt.Sprintf("Hi %s,\n\nYour code to %s is:\n\n%s\n\n"+
+			"The code expires in %s. If you didn't request this, someone may be signed in to your account; "+
+			"sign out of all sessions right away.\n",
+			user.Username, reauthActionNames[action], code, reauthCodeExpiry),
+	}); err != nil {
+		database.ConsumeReauthCode(ctx, user.ID, action)
+		return fmt.Errorf("failed to send confirmation code: %w", err)
+	}
+
+	return nil
+}
+
+// generateConfirmationCode returns a random 8-digit code
+func generateConfirmationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(100000000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate confirmation code: %w", err)
+	}
+	return fmt.Sprintf("%08d", n.Int64()), nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"fmt"
+	"inkstack-auth/internal/config"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/util"
+	"log"
+	"strings"
+)
+
+// Registration modes
+const (
+	RegistrationOpen   = "open"   // Anyone may register
+	RegistrationInvite = "invite" // An invite code is required
+	RegistrationDomain = "domain" // Allowed email domains may register; others need an invite
+	RegistrationClosed = "closed" // Nobody may register
+)
+
+// RegistrationPolicy decides whether a new account may be created
+type RegistrationPolicy struct {
+	mode           string
+	allowedDomains map[string]bool
+	inviteRepo     repository.InviteRepository
+}
+
+// NewRegistrationPolicy creates a registration policy from configuration
+func NewRegistrationPolicy(cfg *config.Config, inviteRepo repository.InviteRepository) (*RegistrationPolicy, error) {
+	policy := &RegistrationPolicy{
+		mode:           strings.ToLower(cfg.Registration.Mode),
+		allowedDomains: make(map[string]bool, len(cfg.Registration.AllowedDomains)),
+		inviteRepo:     inviteRepo,
+	}
+
+	switch policy.mode {
+	case RegistrationOpen, RegistrationInvite, RegistrationClosed:
+	case RegistrationDomain:
+		if len(cfg.Registration.AllowedDomains) == 0 {
+			return nil, fmt.Errorf("REGISTRATION_ALLOWED_DOMAINS is required in domain registration mode")
+		}
+	default:
+		return nil, fmt.Errorf("unknown registration mode %q", cfg.Registration.Mode)
+	}
+
+	for _, domain := range cfg.Registration.AllowedDomains {
+		policy.allowedDomains[strings.ToLower(strings.TrimPrefix(domain, "@"))] = true
+	}
+
+	log.Printf("Registration mode: %s", policy.mode)
+	return policy, nil
+}
+
+// Mode returns the configured registration mode
+func (p *RegistrationPolicy) Mode() string {
+	return p.mode
+}
+
+// AllowsWithoutInvite reports whether an address may register without an invite
+func (p *RegistrationPolicy) AllowsWithoutInvite(email string) bool {
+	switch p.mode {
+	case RegistrationOpen:
+		return true
+	case RegistrationDomain:
+		at := strings.LastIndex(email, "@")
+		return at >= 0 && p.allowedDomains[strings.ToLower(email[at+1:])]
+	default:
+		return false
+	}
+}
+
+// Admit checks whether an address may register, redeeming a use of the invite code when
+// one is needed. The returned invite is nil if none was used; pass it to Release if the
+// account is not created after all.
+func (p *RegistrationPolicy) Admit(email, inviteCode string) (*models.Invite, error) {
+	if p.mode == RegistrationClosed {
+		return nil, fmt.Errorf("registration is closed")
+	}
+
+	if p.AllowsWithoutInvite(email) {
+		return nil, nil
+	}
+
+	if inviteCode == "" {
+		if p.mode == RegistrationDomain {
+			return nil, fmt.Errorf("registration is limited to approved email domains; an invite code is required")
+		}
+		return nil, fmt.Errorf("an invite code is required to register")
+	}
+
+	invite, err := p.inviteRepo.FindByCodeHash(util.HashToken(strings.TrimSpace(inviteCode)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid invite code")
+	}
+
+	if invite.Email != "" && !strings.EqualFold(invite.Email, email) {
+		return nil, fmt.Errorf("this invite is for a different email address")
+	}
+
+	redeemed, err := p.inviteRepo.Redeem(invite.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !redeemed {
+		return nil, fmt.Errorf("invite code has expired or has already been used")
+	}
+
+	return invite, nil
+}
+
+// Release gives back an invite use when registration fails after Admit
+func (p *RegistrationPolicy) Release(invite *models.Invite) {
+	if invite == nil {
+		return
+	}
+	if err := p.inviteRepo.Release(invite.ID); err != nil {
+		log.Printf("Failed to release invite %d: %v", invite.ID, err)
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"log"
+	"sync"
+)
+
+// RoleService resolves role permissions and assigns roles to users
+type RoleService struct {
+	roleRepo     repository.RoleRepository
+	userRepo     repository.UserRepository
+	auditService *AuditService
+
+	mu          sync.RWMutex
+	permissions map[string][]string // Role name to permission names, loaded on first use
+}
+
+// NewRoleService creates a new role service
+func NewRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository, auditService *AuditService) *RoleService {
+	return &RoleService{
+		roleRepo:     roleRepo,
+		userRepo:     userRepo,
+		auditService: auditService,
+	}
+}
+
+// Permissions returns the permissions granted to a role. Unknown roles have none.
+func (s *RoleService) Permissions(role string) []string {
+	s.mu.RLock()
+	permissions, ok := s.permissions[role]
+	s.mu.RUnlock()
+
+	if ok {
+		return permissions
+	}
+
+	// Roles are seeded by migrations and rarely change, so only reload on a miss
+	if err := s.reload(); err != nil {
+		log.Printf("Failed to load role permissions: %v", err)
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.permissions[role]
+}
+
+// HasPermission reports whether a role grants a permission
+func (s *RoleService) HasPermission(role, permission string) bool {
+	for _, p := range s.Permissions(role) {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// ListRoles returns every role with its permissions
+func (s *RoleService) ListRoles() ([]models.Role, error) {
+	return s.roleRepo.FindAll()
+}
+
+// AssignRole changes a user's role. Admins cannot change their own role, so the
+// last admin cannot lock everyone out by accident. The change applies to access
+// tokens issued from now on.
+func (s *RoleService) AssignRole(ctx context.Context, actorID, userID uint, roleName string, client ClientInfo) (*models.User, error) {
+	if actorID == userID {
+		return nil, fmt.Errorf("you cannot change your own role")
+	}
+
+	role, err := s.roleRepo.FindByName(roleName)
+	if err != nil {
+		return nil, fmt.Errorf("unknown role %q", roleName)
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if user.Role == role.Name {
+		return user, nil
+	}
+
+	previous := user.Role
+	user.Role = role.Name
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	s.auditService.Record(AuditEvent{
+		Type:    models.AuthEventRoleChange,
+		UserID:  user.ID,
+		Client:  client,
+		Details: fmt.Sprintf("%s -> %s by user %d", previous, role.Name, actorID),
+	})
+
+	return user, nil
+}
+
+// reload replaces the cached role permissions with the current database state
+func (s *RoleService) reload() error {
+	roles, err := s.roleRepo.FindAll()
+	if err != nil {
+		return err
+	}
+
+	permissions := make(map[string][]string, len(roles))
+	for i := range roles {
+		permissions[roles[i].Name] = roles[i].PermissionNames()
+	}
+
+	s.mu.Lock()
+	s.permissions = permissions
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"fmt"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/util"
+	"log"
+	"strings"
+)
+
+// maxGeneratePasswordAttempts bounds how often GeneratePassword retries a random
+// password that misses one of the character rules
+const maxGeneratePasswordAttempts = 100
+
+// UserAdminService manages accounts from the command line and at startup. Changes
+// are audited with "cli" in the details, since there is no signed-in actor.
+type UserAdminService struct {
+	userRepo       repository.UserRepository
+	tokenRepo      repository.TokenRepository
+	roleRepo       repository.RoleRepository
+	passwordPolicy *PasswordPolicy
+	auditService   *AuditService
+}
+
+// NewUserAdminService creates a new user admin service
+func NewUserAdminService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	roleRepo repository.RoleRepository,
+	passwordPolicy *PasswordPolicy,
+	auditService *AuditService,
+) *UserAdminService {
+	return &UserAdminService{
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		roleRepo:       roleRepo,
+		passwordPolicy: passwordPolicy,
+		auditService:   auditService,
+	}
+}
+
+// CreateUserInput contains the details of an account created by an operator
+type CreateUserInput struct {
+	Email    string
+	Username string
+	Password string
+	Role     string
+}
+
+// CreateUser creates an active account with a verified email address. Registration
+// policy does not apply, but the password policy does.
+func (s *UserAdminService) CreateUser(input CreateUserInput) (*models.User, error) {
+	if err := util.ValidateEmail(input.Email); err != nil {
+		return nil, err
+	}
+	if err := util.ValidateUsername(input.Username); err != nil {
+		return nil, err
+	}
+	if err := s.passwordPolicy.Validate(input.Password, input.Username, input.Email); err != nil {
+		return nil, err
+	}
+
+	role, err := s.roleRepo.FindByName(input.Role)
+	if err != nil {
+		return nil, fmt.Errorf("unknown role %q", input.Role)
+	}
+
+	exists, err := s.userRepo.ExistsByEmail(input.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check email: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("email already registered")
+	}
+
+	exists, err = s.userRepo.ExistsByUsername(input.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check username: %w", err)
+	}
+	if exists {
+		return nil, fmt.Errorf("username already taken")
+	}
+
+	passwordHash, err := util.HashPassword(input.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user := &models.User{
+		Email:         input.Email,
+		Username:      input.Username,
+		PasswordHash:  passwordHash,
+		EmailVerified: true,
+		IsActive:      true,
+		Role:          role.Name,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	s.auditService.Record(AuditEvent{
+		Type:    models.AuthEventRegister,
+		UserID:  user.ID,
+		Details: "cli role:" + role.Name,
+	})
+
+	return user, nil
+}
+
+// BootstrapAdmin creates an admin account unless one with the email already exists,
+// so it is safe to run on every startup. An existing account is left untouched,
+// including its password and role.
+func (s *UserAdminService) BootstrapAdmin(email, username, password string) (*models.User, bool, error) {
+	if user, err := s.userRepo.FindByEmail(email); err == nil {
+		if user.Role != models.RoleAdmin {
+			log.Printf("Warning: bootstrap admin %s exists with role %q; use \"user set-role\" to change it", email, user.Role)
+		}
+		return user, false, nil
+	}
+
+	user, err := s.CreateUser(CreateUserInput{
+		Email:    email,
+		Username: username,
+		Password: password,
+		Role:     models.RoleAdmin,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// SetRole changes the role of the user with the given email or username
+func (s *UserAdminService) SetRole(identifier, roleName string) (*models.User, error) {
+	role, err := s.roleRepo.FindByName(roleName)
+	if err != nil {
+		return nil, fmt.Errorf("unknown role %q", roleName)
+	}
+
+	user, err := s.findUser(identifier)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role.Name {
+		return user, nil
+	}
+
+	previous := user.Role
+	user.Role = role.Name
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	s.auditService.Record(AuditEvent{
+		Type:    models.AuthEventRoleChange,
+		UserID:  user.ID,
+		Details: fmt.Sprintf("%s -> %s by cli", previous, role.Name),
+	})
+
+	return user, nil
+}
+
+// SetActive activates or deactivates the user with the given email or username.
+// Deactivating signs the user out of every session.
+func (s *UserAdminService) SetActive(identifier string, active bool) (*models.User, error) {
+	user, err := s.findUser(identifier)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsActive == active {
+		return user, nil
+	}
+
+	user.IsActive = active
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	details := "activated by cli"
+	if !active {
+		details = "deactivated by cli"
+		if err := s.tokenRepo.RevokeAllUserTokens(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	s.auditService.Record(AuditEvent{
+		Type:    models.AuthEventAccountStatus,
+		UserID:  user.ID,
+		Details: details,
+	})
+
+	return user, nil
+}
+
+// ResetPassword sets a new password for the user with the given email or username
+// and signs them out of every session
+func (s *UserAdminService) ResetPassword(identifier, password string) (*models.User, error) {
+	user, err := s.findUser(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.passwordPolicy.Validate(password, user.Username, user.Email); err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := util.HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	user.PasswordHash = passwordHash
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.tokenRepo.RevokeAllUserTokens(user.ID); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(AuditEvent{
+		Type:    models.AuthEventPasswordChange,
+		UserID:  user.ID,
+		Details: "reset by cli",
+	})
+
+	return user, nil
+}
+
+// GeneratePassword returns a random password that satisfies the password policy
+func (s *UserAdminService) GeneratePassword(userInputs ...string) (string, error) {
+	for i := 0; i < maxGeneratePasswordAttempts; i++ {
+		password, err := util.GenerateRandomToken(18)
+		if err != nil {
+			return "", err
+		}
+		if s.passwordPolicy.Validate(password, userInputs...) == nil {
+			return password, nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate a password that meets the password policy")
+}
+
+// findUser looks up a user by email or username
+func (s *UserAdminService) findUser(identifier string) (*models.User, error) {
+	user, err := s.userRepo.FindByEmailOrUsername(strings.TrimSpace(identifier))
+	if err != nil {
+		return nil, fmt.Errorf("user %q not found", identifier)
+	}
+	return user, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"inkstack-auth/internal/models"
+	"inkstack-auth/internal/repository"
+	"inkstack-auth/internal/util"
+	"strings"
+)
+
+// UserService handles user profile operations
+type UserService struct {
+	userRepo repository.UserRepository
+}
+
+// NewUserService creates a new user service
+func NewUserService(userRepo repository.UserRepository) *UserService {
+	return &UserService{
+		userRepo: userRepo,
+	}
+}
+
+// UpdateProfileInput contains profile fields to change. Nil fields are left untouched.
+type UpdateProfileInput struct {
+	DisplayName *string
+	Bio         *string
+	AvatarURL   *string
+}
+
+// GetProfile returns the public profile of an active user
+func (s *UserService) GetProfile(ctx context.Context, username string) (*models.User, error) {
+	user, err := s.userRepo.FindByUsername(username)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	// Inactive accounts are hidden rather than reported, so they can't be enumerated
+	if !user.IsActive {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return user, nil
+}
+
+// MaxProfileLookup is the most users that can be looked up in one request
+const MaxProfileLookup = 500
+
+// LookupProfiles returns the public profiles of the active users among ids and usernames,
+// each user once. Unknown and inactive users are left out, as GetProfile hides them.
+func (s *UserService) LookupProfiles(ctx context.Context, ids []uint, usernames []string) ([]models.PublicProfile, error) {
+	users, err := s.userRepo.FindActiveByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byUsername, err := s.userRepo.FindActiveByUsernames(usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make([]models.PublicProfile, 0, len(users)+len(byUsername))
+	seen := make(map[uint]bool, len(users))
+	for _, user := range append(users, byUsername...) {
+		if !seen[user.ID] {
+			seen[user.ID] = true
+			profiles = append(profiles, user.ToProfile())
+		}
+	}
+	return profiles, nil
+}
+
+// UpdateProfile updates the authenticated user's profile fields
+func (s *UserService) UpdateProfile(ctx context.Context, userID uint, input UpdateProfileInput) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if input.DisplayName != nil {
+		displayName := strings.TrimSpace(*input.DisplayName)
+		if err := util.ValidateDisplayName(displayName); err != nil {
+			return nil, err
+		}
+		user.DisplayName = displayName
+	}
+
+	if input.Bio != nil {
+		bio := strings.TrimSpace(*input.Bio)
+		if err := util.ValidateBio(bio); err != nil {
+			return nil, err
+		}
+		user.Bio = bio
+	}
+
+	if input.AvatarURL != nil {
+		avatarURL := strings.TrimSpace(*input.AvatarURL)
+		if err := util.ValidateAvatarURL(avatarURL); err != nil {
+			return nil, err
+		}
+		user.AvatarURL = avatarURL
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,116 @@
+package util
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// bloomFilterMagic identifies a serialized bloom filter file
+const bloomFilterMagic = "INKBLOOM"
+
+// BloomFilter is a probabilistic set of SHA-1 digests. It never reports a false
+// negative, and reports a false positive at roughly the rate it was sized for.
+type BloomFilter struct {
+	bits   []uint64
+	m      uint64 // number of bits
+	hashes uint32 // number of hash functions
+}
+
+// NewBloomFilter sizes a filter for n items at the given false positive rate
+func NewBloomFilter(n uint64, falsePositiveRate float64) *BloomFilter {
+	if n == 0 {
+		n = 1
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	k := uint32(math.Max(1, math.Round(float64(m)/float64(n)*math.Ln2)))
+	words := (m + 63) / 64
+	return &BloomFilter{
+		bits:   make([]uint64, words),
+		m:      words * 64,
+		hashes: k,
+	}
+}
+
+// Add inserts a SHA-1 digest
+func (f *BloomFilter) Add(digest []byte) {
+	h1, h2 := bloomHashes(digest)
+	for i := uint64(0); i < uint64(f.hashes); i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// Test reports whether a SHA-1 digest may be in the set
+func (f *BloomFilter) Test(digest []byte) bool {
+	h1, h2 := bloomHashes(digest)
+	for i := uint64(0); i < uint64(f.hashes); i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteTo serializes the filter
+func (f *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	header := make([]byte, len(bloomFilterMagic)+4+8)
+	copy(header, bloomFilterMagic)
+	binary.BigEndian.PutUint32(header[len(bloomFilterMagic):], f.hashes)
+	binary.BigEndian.PutUint64(header[len(bloomFilterMagic)+4:], f.m)
+	if _, err := bw.Write(header); err != nil {
+		return 0, err
+	}
+
+	word := make([]byte, 8)
+	for _, bits := range f.bits {
+		binary.BigEndian.PutUint64(word, bits)
+		if _, err := bw.Write(word); err != nil {
+			return 0, err
+		}
+	}
+
+	return int64(len(header) + len(f.bits)*8), bw.Flush()
+}
+
+// ReadBloomFilter deserializes a filter written by WriteTo
+func ReadBloomFilter(r io.Reader) (*BloomFilter, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, len(bloomFilterMagic)+4+8)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("failed to read bloom filter header: %w", err)
+	}
+	if string(header[:len(bloomFilterMagic)]) != bloomFilterMagic {
+		return nil, fmt.Errorf("not a bloom filter file")
+	}
+
+	f := &BloomFilter{
+		hashes: binary.BigEndian.Uint32(header[len(bloomFilterMagic):]),
+		m:      binary.BigEndian.Uint64(header[len(bloomFilterMagic)+4:]),
+	}
+	if f.hashes == 0 || f.m == 0 || f.m%64 != 0 {
+		return nil, fmt.Errorf("invalid bloom filter parameters")
+	}
+
+	f.bits = make([]uint64, f.m/64)
+	word := make([]byte, 8)
+	for i := range f.bits {
+		if _, err := io.ReadFull(br, word); err != nil {
+			return nil, fmt.Errorf("failed to read bloom filter: %w", err)
+		}
+		f.bits[i] = binary.BigEndian.Uint64(word)
+	}
+
+	return f, nil
+}
+
+// bloomHashes derives two independent hashes from a digest for double hashing
+func bloomHashes(digest []byte) (uint64, uint64) {
+	h1 := binary.BigEndian.Uint64(digest[0:8])
+	h2 := binary.BigEndian.Uint64(digest[8:16]) | 1 // odd, so every bit is reachable
+	return h1, h2
+}
@@ -0,0 +1,72 @@
+package util
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// userAgentBrowsers maps user agent tokens to browser names. Order matters: Edge and
+// Opera also claim to be Chrome, and Chrome also claims to be Safari.
+var userAgentBrowsers = []struct{ token, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"CriOS/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+}
+
+// userAgentSystems maps user agent tokens to operating system names. iOS and Android
+// are checked before the desktop systems their user agents also mention.
+var userAgentSystems = []struct{ token, name string }{
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Android", "Android"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// DescribeDevice turns a user agent into a short description such as "Firefox on
+// Windows". Versions are left out so browser updates don't look like a new device.
+func DescribeDevice(userAgent string) string {
+	browser := "Unknown browser"
+	for _, b := range userAgentBrowsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	system := "unknown system"
+	for _, s := range userAgentSystems {
+		if strings.Contains(userAgent, s.token) {
+			system = s.name
+			break
+		}
+	}
+
+	return browser + " on " + system
+}
+
+// IPRange returns the network an address belongs to: the /24 for IPv4 or the /48 for
+// IPv6, roughly what one household or office gets. Returns "unknown" for invalid input.
+func IPRange(ipAddress string) string {
+	addr, err := netip.ParseAddr(ipAddress)
+	if err != nil {
+		return "unknown"
+	}
+	addr = addr.Unmap()
+
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return "unknown"
+	}
+	return prefix.String()
+}
@@ -0,0 +1,87 @@
+package util
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// geoIPRange maps an inclusive range of addresses to a country
+type geoIPRange struct {
+	start   netip.Addr
+	end     netip.Addr
+	country string
+}
+
+// GeoIPDatabase looks up the country of an IP address offline
+type GeoIPDatabase struct {
+	ranges []geoIPRange // Sorted by start, non-overlapping
+}
+
+// ReadGeoIPDatabase parses an IP-to-country CSV with one "first_ip,last_ip,country_code"
+// row per range, the format of the free DB-IP "IP to Country Lite" download.
+func ReadGeoIPDatabase(r io.Reader) (*GeoIPDatabase, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	db := &GeoIPDatabase{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GeoIP database: %w", err)
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("invalid GeoIP database row %d", line)
+		}
+
+		start, err := netip.ParseAddr(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid GeoIP database row %d: %w", line, err)
+		}
+		end, err := netip.ParseAddr(strings.TrimSpace(record[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid GeoIP database row %d: %w", line, err)
+		}
+		country := strings.ToUpper(strings.TrimSpace(record[2]))
+		if len(country) != 2 || country == "ZZ" {
+			continue // Reserved and unassigned ranges use "ZZ" or an empty code
+		}
+
+		db.ranges = append(db.ranges, geoIPRange{start: start.Unmap(), end: end.Unmap(), country: country})
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool { return db.ranges[i].start.Less(db.ranges[j].start) })
+	return db, nil
+}
+
+// Country returns the ISO 3166-1 alpha-2 country code of an address, or "" if unknown
+func (db *GeoIPDatabase) Country(ipAddress string) string {
+	addr, err := netip.ParseAddr(ipAddress)
+	if err != nil {
+		return ""
+	}
+	addr = addr.Unmap()
+
+	// Find the last range starting at or before the address
+	i := sort.Search(len(db.ranges), func(i int) bool { return addr.Less(db.ranges[i].start) }) - 1
+	if i < 0 {
+		return ""
+	}
+	rng := db.ranges[i]
+	if rng.start.BitLen() != addr.BitLen() || rng.end.Less(addr) {
+		return ""
+	}
+	return rng.country
+}
+
+// Len returns the number of ranges in the database
+func (db *GeoIPDatabase) Len() int {
+	return len(db.ranges)
+}
@@ -1,47 +1,211 @@
 package util
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"fmt"
 	"regexp"
+	"strings"
 	"unicode"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
 const (
-	// BcryptCost is the cost factor for bcrypt hashing
+	// BcryptCost is the default cost factor for bcrypt hashing
 	BcryptCost = 12
 
 	// MinPasswordLength is the minimum required password length
 	MinPasswordLength = 8
 
-	// MaxPasswordLength is the maximum allowed password length
-	MaxPasswordLength = 72 // bcrypt limit
+	// MaxPasswordLength is the maximum allowed password length in bytes.
+	// It only guards against abuse; argon2id has no input limit.
+	MaxPasswordLength = 1024
+
+	// BcryptMaxPasswordLength is the longest password bcrypt can hash without truncating
+	BcryptMaxPasswordLength = 72
+)
+
+// Password hashing algorithms
+const (
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
 )
 
-// HashPassword hashes a password using bcrypt
+// Argon2Params are the argon2id cost parameters
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follow the OWASP recommendation for argon2id
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// PasswordHashConfig selects the algorithm and parameters for new password hashes
+type PasswordHashConfig struct {
+	Algorithm  string
+	Argon2     Argon2Params
+	BcryptCost int
+}
+
+// passwordHashConfig is used by HashPassword and PasswordNeedsRehash
+var passwordHashConfig = PasswordHashConfig{
+	Algorithm:  PasswordAlgorithmArgon2id,
+	Argon2:     DefaultArgon2Params,
+	BcryptCost: BcryptCost,
+}
+
+// ConfigurePasswordHashing sets the algorithm and parameters for new password hashes.
+// Existing hashes in any supported format keep verifying.
+func ConfigurePasswordHashing(cfg PasswordHashConfig) error {
+	switch cfg.Algorithm {
+	case PasswordAlgorithmArgon2id:
+		if cfg.Argon2.Memory == 0 || cfg.Argon2.Iterations == 0 || cfg.Argon2.Parallelism == 0 {
+			return fmt.Errorf("argon2id memory, iterations and parallelism must be positive")
+		}
+		if cfg.Argon2.SaltLength == 0 {
+			cfg.Argon2.SaltLength = DefaultArgon2Params.SaltLength
+		}
+		if cfg.Argon2.KeyLength == 0 {
+			cfg.Argon2.KeyLength = DefaultArgon2Params.KeyLength
+		}
+	case PasswordAlgorithmBcrypt:
+		if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+			return fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return fmt.Errorf("unsupported password hashing algorithm: %s", cfg.Algorithm)
+	}
+
+	passwordHashConfig = cfg
+	return nil
+}
+
+// HashPassword hashes a password with the configured algorithm.
+// Argon2id hashes use the PHC string format: $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), BcryptCost)
-	if err != nil {
-		return "", fmt.Errorf("failed to hash password: %w", err)
+	if passwordHashConfig.Algorithm == PasswordAlgorithmBcrypt {
+		bytes, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashConfig.BcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash password: %w", err)
+		}
+		return string(bytes), nil
+	}
+
+	params := passwordHashConfig.Argon2
+	salt := make([]byte, params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
 	}
-	return string(bytes), nil
+
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Iterations, params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
-// ComparePassword compares a hashed password with a plaintext password
+// ComparePassword compares a hashed password with a plaintext password.
+// Both argon2id PHC strings and bcrypt hashes are accepted.
 func ComparePassword(hashedPassword, password string) bool {
+	if strings.HasPrefix(hashedPassword, "$argon2id$") {
+		params, salt, key, err := decodeArgon2Hash(hashedPassword)
+		if err != nil {
+			return false
+		}
+		candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+		return subtle.ConstantTimeCompare(key, candidate) == 1
+	}
+
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err == nil
 }
 
+// PasswordNeedsRehash reports whether a hash was made with a different algorithm
+// or weaker parameters than currently configured
+func PasswordNeedsRehash(hashedPassword string) bool {
+	if passwordHashConfig.Algorithm == PasswordAlgorithmBcrypt {
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err != nil || cost < passwordHashConfig.BcryptCost
+	}
+
+	if !strings.HasPrefix(hashedPassword, "$argon2id$") {
+		return true
+	}
+
+	params, salt, key, err := decodeArgon2Hash(hashedPassword)
+	if err != nil {
+		return true
+	}
+
+	want := passwordHashConfig.Argon2
+	return params.Memory < want.Memory ||
+		params.Iterations < want.Iterations ||
+		params.Parallelism < want.Parallelism ||
+		uint32(len(salt)) < want.SaltLength ||
+		uint32(len(key)) < want.KeyLength
+}
+
+// decodeArgon2Hash parses an argon2id PHC string
+func decodeArgon2Hash(encoded string) (*Argon2Params, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("unsupported argon2id version: %d", version)
+	}
+
+	params := &Argon2Params{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
+
 // ValidatePasswordStrength validates password strength
 func ValidatePasswordStrength(password string) error {
 	if len(password) < MinPasswordLength {
 		return fmt.Errorf("password must be at least %d characters long", MinPasswordLength)
 	}
 
-	if len(password) > MaxPasswordLength {
-		return fmt.Errorf("password must not exceed %d characters", MaxPasswordLength)
+	maxLength := MaxPasswordLength
+	if passwordHashConfig.Algorithm == PasswordAlgorithmBcrypt {
+		maxLength = BcryptMaxPasswordLength
+	}
+	if len(password) > maxLength {
+		return fmt.Errorf("password must not exceed %d characters", maxLength)
 	}
 
 	var (
@@ -0,0 +1,326 @@
+package util
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// PasswordStrength is an estimate of how hard a password is to guess, modelled on zxcvbn
+type PasswordStrength struct {
+	Score        int      `json:"score"`         // 0 (too guessable) to 4 (very unguessable)
+	GuessesLog10 float64  `json:"guesses_log10"` // Estimated guesses needed, as a power of ten
+	Warning      string   `json:"warning,omitempty"`
+	Suggestions  []string `json:"suggestions,omitempty"`
+}
+
+// commonPasswordWords are ranked by frequency in leaked password lists; lower rank is guessed first
+var commonPasswordWords = strings.Fields(`
+password 123456 qwerty letmein welcome admin iloveyou monkey dragon football baseball
+master sunshine princess shadow superman batman trustno1 michael jessica charlie
+login abc123 starwars whatever freedom hello secret summer winter spring autumn
+love lovely flower angel pokemon jordan hunter ranger soccer hockey killer
+pepper ginger cookie cheese chocolate computer internet google yahoo facebook
+mustang ferrari corvette harley chelsea arsenal liverpool barcelona madrid
+orange banana apple purple yellow silver golden diamond tiger lion eagle
+family friend forever happy lucky magic money power peace heaven jesus
+matrix ninja pirate wizard zombie soldier thunder flash knight robert thomas
+daniel andrew joshua matthew jennifer ashley amanda nicole michelle hannah
+inkstack blog post comment author writer reader access secure change
+`)
+
+// commonPasswordRanks maps each common word to its rank
+var commonPasswordRanks = func() map[string]int {
+	ranks := make(map[string]int, len(commonPasswordWords))
+	for i, word := range commonPasswordWords {
+		if _, exists := ranks[word]; !exists {
+			ranks[word] = i + 1
+		}
+	}
+	return ranks
+}()
+
+// keyboardRows are adjacent-key runs that are commonly typed as passwords
+var keyboardRows = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm", "1234567890", "qazwsxedc"}
+
+// leetSubstitutions maps common character substitutions back to letters
+var leetSubstitutions = map[rune]rune{
+	'4': 'a', '@': 'a', '3': 'e', '1': 'i', '!': 'i', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't',
+}
+
+// passwordMatch is a guessable pattern found in a password
+type passwordMatch struct {
+	start, end   int // rune offsets, end exclusive
+	guessesLog10 float64
+	kind         string
+}
+
+// Password pattern kinds
+const (
+	matchDictionary = "dictionary"
+	matchUserInput  = "user_input"
+	matchSequence   = "sequence"
+	matchRepeat     = "repeat"
+	matchKeyboard   = "keyboard"
+	matchYear       = "year"
+)
+
+// EstimatePasswordStrength estimates how many guesses an attacker needs, accounting for
+// common words, the user's own details, sequences, repeats, keyboard runs and years.
+// userInputs are values like the username and email that should not appear in the password.
+func EstimatePasswordStrength(password string, userInputs ...string) PasswordStrength {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return PasswordStrength{
+			Score:       0,
+			Warning:     "Password is empty",
+			Suggestions: []string{"Use a few words, avoid common phrases"},
+		}
+	}
+
+	matches := findPasswordMatches(runes, userInputs)
+
+	// best[i] is the cheapest way (in log10 guesses) to produce the first i runes.
+	// Unmatched runes are brute forced one at a time.
+	best := make([]float64, len(runes)+1)
+	via := make([]*passwordMatch, len(runes)+1)
+	for i := 1; i <= len(runes); i++ {
+		best[i] = best[i-1] + math.Log10(float64(runeCardinality(runes[i-1])))
+		via[i] = nil
+		for j := range matches {
+			m := &matches[j]
+			if m.end == i && best[m.start]+m.guessesLog10 < best[i] {
+				best[i] = best[m.start] + m.guessesLog10
+				via[i] = m
+			}
+		}
+	}
+
+	// Collect the patterns on the cheapest path for feedback
+	var used []*passwordMatch
+	for i := len(runes); i > 0; {
+		if via[i] != nil {
+			used = append(used, via[i])
+			i = via[i].start
+		} else {
+			i--
+		}
+	}
+
+	guessesLog10 := math.Round(best[len(runes)]*100) / 100
+	strength := PasswordStrength{
+		Score:        passwordScore(guessesLog10),
+		GuessesLog10: guessesLog10,
+	}
+	strength.Warning, strength.Suggestions = passwordFeedback(strength.Score, used)
+	return strength
+}
+
+// findPasswordMatches finds every guessable pattern in a password
+func findPasswordMatches(runes []rune, userInputs []string) []passwordMatch {
+	lower := []rune(strings.ToLower(string(runes)))
+	unleet := make([]rune, len(lower))
+	for i, r := range lower {
+		if sub, ok := leetSubstitutions[r]; ok {
+			unleet[i] = sub
+		} else {
+			unleet[i] = r
+		}
+	}
+
+	inputs := make(map[string]bool)
+	for _, input := range userInputs {
+		input = strings.ToLower(input)
+		// Check the local part of email addresses separately
+		if at := strings.Index(input, "@"); at > 0 {
+			inputs[input[:at]] = true
+		}
+		if len(input) >= 3 {
+			inputs[input] = true
+		}
+	}
+
+	var matches []passwordMatch
+	for i := 0; i < len(lower); i++ {
+		for j := i + 3; j <= len(lower); j++ {
+			word, plainWord := string(unleet[i:j]), string(lower[i:j])
+			variations := casingVariations(runes[i:j])
+			if word != plainWord {
+				variations += math.Log10(2) // Leet substitutions roughly double the guesses
+			}
+
+			if rank, ok := commonPasswordRanks[word]; ok {
+				matches = append(matches, passwordMatch{i, j, math.Log10(float64(rank)) + variations, matchDictionary})
+			}
+			if inputs[word] || inputs[plainWord] {
+				matches = append(matches, passwordMatch{i, j, variations, matchUserInput})
+			}
+		}
+	}
+
+	// Sequences (abc, 987) and repeats (aaa) of three or more runes
+	for i := 0; i < len(lower); {
+		j := i + 1
+		delta := 0
+		if j < len(lower) {
+			delta = int(lower[j]) - int(lower[i])
+		}
+		for j < len(lower) && int(lower[j])-int(lower[j-1]) == delta && (delta == 0 || delta == 1 || delta == -1) {
+			j++
+		}
+		if j-i >= 3 {
+			kind, base := matchSequence, 10.0
+			if delta == 0 {
+				kind, base = matchRepeat, float64(runeCardinality(lower[i]))
+			}
+			matches = append(matches, passwordMatch{i, j, math.Log10(base * float64(j-i)), kind})
+		}
+		if j > i+1 {
+			i = j - 1
+		} else {
+			i = j
+		}
+	}
+
+	// Keyboard runs of four or more keys
+	text := string(lower)
+	for _, row := range keyboardRows {
+		for length := len(row); length >= 4; length-- {
+			for start := 0; start+length <= len(row); start++ {
+				run := row[start : start+length]
+				for offset := strings.Index(text, run); offset >= 0; {
+					runeStart := len([]rune(text[:offset]))
+					matches = append(matches, passwordMatch{runeStart, runeStart + length, math.Log10(float64(100 * length)), matchKeyboard})
+					next := strings.Index(text[offset+1:], run)
+					if next < 0 {
+						break
+					}
+					offset += next + 1
+				}
+			}
+		}
+	}
+
+	// Years between 1900 and 2039
+	for i := 0; i+4 <= len(lower); i++ {
+		year := string(lower[i : i+4])
+		if (strings.HasPrefix(year, "19") || strings.HasPrefix(year, "20")) && isDigits(year) && year < "2040" {
+			matches = append(matches, passwordMatch{i, i + 4, math.Log10(140), matchYear})
+		}
+	}
+
+	return matches
+}
+
+// casingVariations estimates the extra guesses (log10) caused by capitalization
+func casingVariations(runes []rune) float64 {
+	upper := 0
+	for _, r := range runes {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	switch {
+	case upper == 0, upper == len(runes):
+		return 0
+	case upper == 1 && unicode.IsUpper(runes[0]):
+		return math.Log10(2) // Capitalized first letter is the first thing tried
+	default:
+		return float64(upper) * math.Log10(2)
+	}
+}
+
+// runeCardinality is the size of the character class a rune belongs to
+func runeCardinality(r rune) int {
+	switch {
+	case unicode.IsLower(r):
+		return 26
+	case unicode.IsUpper(r):
+		return 26
+	case unicode.IsDigit(r):
+		return 10
+	case r < unicode.MaxASCII:
+		return 33
+	default:
+		return 100
+	}
+}
+
+// isDigits reports whether s consists only of ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// passwordScore buckets log10 guesses into zxcvbn's 0-4 score
+func passwordScore(guessesLog10 float64) int {
+	switch {
+	case guessesLog10 < 3:
+		return 0
+	case guessesLog10 < 6:
+		return 1
+	case guessesLog10 < 8:
+		return 2
+	case guessesLog10 < 10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// passwordFeedback explains a weak score using the patterns that made it guessable
+func passwordFeedback(score int, used []*passwordMatch) (string, []string) {
+	if score >= 3 {
+		return "", nil
+	}
+
+	suggestions := []string{"Add another word or two. Uncommon words are better."}
+	warning := ""
+	for _, m := range used {
+		switch m.kind {
+		case matchUserInput:
+			warning = "Avoid using your username or email address"
+		case matchDictionary:
+			if warning == "" {
+				warning = "This is similar to a commonly used password"
+			}
+			suggestions = append(suggestions, "Predictable substitutions like '@' instead of 'a' don't help very much")
+		case matchSequence:
+			if warning == "" {
+				warning = "Sequences like abc or 6543 are easy to guess"
+			}
+		case matchRepeat:
+			if warning == "" {
+				warning = "Repeats like \"aaa\" are easy to guess"
+			}
+		case matchKeyboard:
+			if warning == "" {
+				warning = "Straight rows of keys are easy to guess"
+			}
+		case matchYear:
+			if warning == "" {
+				warning = "Recent years are easy to guess"
+			}
+			suggestions = append(suggestions, "Avoid years that are associated with you")
+		}
+	}
+
+	return warning, dedupeStrings(suggestions)
+}
+
+// dedupeStrings removes repeated values, keeping the first occurrence
+func dedupeStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := values[:0]
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package util
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxDisplayNameLength is the maximum display name length in characters
+	MaxDisplayNameLength = 100
+
+	// MaxBioLength is the maximum bio length in characters
+	MaxBioLength = 1000
+
+	// MaxAvatarURLLength is the maximum avatar URL length
+	MaxAvatarURLLength = 500
+)
+
+// ValidateDisplayName validates a display name
+func ValidateDisplayName(displayName string) error {
+	if utf8.RuneCountInString(displayName) > MaxDisplayNameLength {
+		return fmt.Errorf("display name must not exceed %d characters", MaxDisplayNameLength)
+	}
+
+	for _, char := range displayName {
+		if unicode.IsControl(char) {
+			return fmt.Errorf("display name must not contain control characters")
+		}
+	}
+
+	return nil
+}
+
+// ValidateBio validates a profile bio
+func ValidateBio(bio string) error {
+	if utf8.RuneCountInString(bio) > MaxBioLength {
+		return fmt.Errorf("bio must not exceed %d characters", MaxBioLength)
+	}
+
+	return nil
+}
+
+// ValidateAvatarURL validates an avatar URL. An empty URL clears the avatar.
+func ValidateAvatarURL(avatarURL string) error {
+	if avatarURL == "" {
+		return nil
+	}
+
+	if len(avatarURL) > MaxAvatarURLLength {
+		return fmt.Errorf("avatar URL must not exceed %d characters", MaxAvatarURLLength)
+	}
+
+	parsed, err := url.Parse(avatarURL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("invalid avatar URL")
+	}
+
+	// Only https avatars, so profiles never cause mixed content or javascript: links
+	if !strings.EqualFold(parsed.Scheme, "https") {
+		return fmt.Errorf("avatar URL must use https")
+	}
+
+	return nil
+}
@@ -16,6 +16,14 @@ type SuccessResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// PaginationResponse represents pagination metadata
+type PaginationResponse struct {
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	PageSize   int   `json:"page_size"`
+	TotalPages int   `json:"total_pages"`
+}
+
 // RespondBadRequest responds with a 400 Bad Request
 func RespondBadRequest(c *gin.Context, message string) {
 	c.JSON(400, ErrorResponse{
@@ -90,3 +98,28 @@ func RespondCreated(c *gin.Context, data interface{}) {
 func RespondNoContent(c *gin.Context) {
 	c.Status(204)
 }
+
+// CalculatePagination calculates pagination values
+func CalculatePagination(page, pageSize int, total int64) PaginationResponse {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	totalPages := int(total) / pageSize
+	if int(total)%pageSize != 0 {
+		totalPages++
+	}
+
+	return PaginationResponse{
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
+}
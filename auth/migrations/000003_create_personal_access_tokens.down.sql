@@ -0,0 +1,7 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_personal_access_tokens_deleted_at;
+DROP INDEX IF EXISTS idx_personal_access_tokens_token_hash;
+DROP INDEX IF EXISTS idx_personal_access_tokens_user_id;
+
+-- Drop personal_access_tokens table
+DROP TABLE IF EXISTS personal_access_tokens;
@@ -0,0 +1,30 @@
+-- Create personal_access_tokens table
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    token_prefix VARCHAR(16) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    scopes VARCHAR(500) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP,
+    last_used_at TIMESTAMP,
+    last_used_ip VARCHAR(45),
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_personal_access_tokens_user_id ON personal_access_tokens(user_id);
+CREATE INDEX idx_personal_access_tokens_token_hash ON personal_access_tokens(token_hash);
+CREATE INDEX idx_personal_access_tokens_deleted_at ON personal_access_tokens(deleted_at);
+
+-- Add comments
+COMMENT ON TABLE personal_access_tokens IS 'Long-lived scoped tokens for automation (CI, scripts)';
+COMMENT ON COLUMN personal_access_tokens.token_prefix IS 'First characters of the token, shown to users to identify it';
+COMMENT ON COLUMN personal_access_tokens.token_hash IS 'SHA-256 hash of the token; the plaintext is never stored';
+COMMENT ON COLUMN personal_access_tokens.scopes IS 'Space-separated list of granted scopes';
+COMMENT ON COLUMN personal_access_tokens.expires_at IS 'Optional expiration timestamp (NULL = never expires)';
+COMMENT ON COLUMN personal_access_tokens.revoked_at IS 'When the token was revoked (NULL = active)';
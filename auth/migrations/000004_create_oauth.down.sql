@@ -0,0 +1,20 @@
+-- Remove OAuth columns from refresh_tokens
+DROP INDEX IF EXISTS idx_refresh_tokens_client_id;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS scopes;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS client_id;
+
+-- Drop oauth_consents table
+DROP INDEX IF EXISTS idx_oauth_consents_deleted_at;
+DROP TABLE IF EXISTS oauth_consents;
+
+-- Drop oauth_authorization_codes table
+DROP INDEX IF EXISTS idx_oauth_authorization_codes_deleted_at;
+DROP INDEX IF EXISTS idx_oauth_authorization_codes_expires_at;
+DROP INDEX IF EXISTS idx_oauth_authorization_codes_code_hash;
+DROP TABLE IF EXISTS oauth_authorization_codes;
+
+-- Drop oauth_clients table
+DROP INDEX IF EXISTS idx_oauth_clients_deleted_at;
+DROP INDEX IF EXISTS idx_oauth_clients_owner_id;
+DROP INDEX IF EXISTS idx_oauth_clients_client_id;
+DROP TABLE IF EXISTS oauth_clients;
@@ -0,0 +1,80 @@
+-- Create oauth_clients table
+CREATE TABLE IF NOT EXISTS oauth_clients (
+    id SERIAL PRIMARY KEY,
+    client_id VARCHAR(64) UNIQUE NOT NULL,
+    client_secret_hash VARCHAR(64),
+    name VARCHAR(100) NOT NULL,
+    owner_id INTEGER NOT NULL,
+    redirect_uris TEXT NOT NULL DEFAULT '',
+    grant_types VARCHAR(255) NOT NULL DEFAULT 'authorization_code refresh_token',
+    scopes VARCHAR(500) NOT NULL DEFAULT '',
+    is_confidential BOOLEAN NOT NULL DEFAULT TRUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_oauth_clients_client_id ON oauth_clients(client_id);
+CREATE INDEX idx_oauth_clients_owner_id ON oauth_clients(owner_id);
+CREATE INDEX idx_oauth_clients_deleted_at ON oauth_clients(deleted_at);
+
+COMMENT ON TABLE oauth_clients IS 'Third-party applications registered to act on behalf of users';
+COMMENT ON COLUMN oauth_clients.client_secret_hash IS 'SHA-256 hash of the client secret (NULL for public clients)';
+COMMENT ON COLUMN oauth_clients.redirect_uris IS 'Space-separated list of allowed redirect URIs (exact match)';
+COMMENT ON COLUMN oauth_clients.grant_types IS 'Space-separated list of allowed grant types';
+COMMENT ON COLUMN oauth_clients.scopes IS 'Space-separated list of scopes the client may request';
+
+-- Create oauth_authorization_codes table
+CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
+    id SERIAL PRIMARY KEY,
+    code_hash VARCHAR(64) UNIQUE NOT NULL,
+    client_id VARCHAR(64) NOT NULL,
+    user_id INTEGER NOT NULL,
+    redirect_uri VARCHAR(500) NOT NULL,
+    scopes VARCHAR(500) NOT NULL DEFAULT '',
+    code_challenge VARCHAR(128),
+    code_challenge_method VARCHAR(10),
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (client_id) REFERENCES oauth_clients(client_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_oauth_authorization_codes_code_hash ON oauth_authorization_codes(code_hash);
+CREATE INDEX idx_oauth_authorization_codes_expires_at ON oauth_authorization_codes(expires_at);
+CREATE INDEX idx_oauth_authorization_codes_deleted_at ON oauth_authorization_codes(deleted_at);
+
+COMMENT ON TABLE oauth_authorization_codes IS 'Short-lived, single-use authorization codes';
+COMMENT ON COLUMN oauth_authorization_codes.code_challenge IS 'PKCE code challenge (RFC 7636)';
+
+-- Create oauth_consents table
+CREATE TABLE IF NOT EXISTS oauth_consents (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    client_id VARCHAR(64) NOT NULL,
+    scopes VARCHAR(500) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    UNIQUE (user_id, client_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (client_id) REFERENCES oauth_clients(client_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_oauth_consents_deleted_at ON oauth_consents(deleted_at);
+
+COMMENT ON TABLE oauth_consents IS 'Scopes a user has already granted to a client';
+
+-- Bind refresh tokens to the OAuth client they were issued to
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS client_id VARCHAR(64);
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS scopes VARCHAR(500);
+
+CREATE INDEX idx_refresh_tokens_client_id ON refresh_tokens(client_id);
+
+COMMENT ON COLUMN refresh_tokens.client_id IS 'OAuth client the token was issued to (NULL = first-party session)';
+COMMENT ON COLUMN refresh_tokens.scopes IS 'Space-separated scopes granted to the OAuth client';
@@ -0,0 +1,10 @@
+-- Drop data_exports table
+DROP INDEX IF EXISTS idx_data_exports_deleted_at;
+DROP INDEX IF EXISTS idx_data_exports_expires_at;
+DROP INDEX IF EXISTS idx_data_exports_status;
+DROP INDEX IF EXISTS idx_data_exports_user_id;
+DROP TABLE IF EXISTS data_exports;
+
+-- Remove deletion scheduling from users
+DROP INDEX IF EXISTS idx_users_deletion_scheduled_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deletion_scheduled_at;
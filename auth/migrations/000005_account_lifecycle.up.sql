@@ -0,0 +1,34 @@
+-- Track scheduled self-service account deletion
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users(deletion_scheduled_at);
+
+COMMENT ON COLUMN users.deletion_scheduled_at IS 'When the account will be permanently deleted; NULL if no deletion is pending';
+
+-- Create data_exports table
+CREATE TABLE IF NOT EXISTS data_exports (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_path VARCHAR(500),
+    file_size BIGINT DEFAULT 0,
+    error TEXT,
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_data_exports_user_id ON data_exports(user_id);
+CREATE INDEX idx_data_exports_status ON data_exports(status);
+CREATE INDEX idx_data_exports_expires_at ON data_exports(expires_at);
+CREATE INDEX idx_data_exports_deleted_at ON data_exports(deleted_at);
+
+-- Add comments
+COMMENT ON TABLE data_exports IS 'Personal data export archives requested by users';
+COMMENT ON COLUMN data_exports.status IS 'Export status: pending, processing, ready, failed';
+COMMENT ON COLUMN data_exports.file_path IS 'Location of the generated archive on disk';
+COMMENT ON COLUMN data_exports.expires_at IS 'When the archive is deleted and can no longer be downloaded';
@@ -0,0 +1,5 @@
+-- Drop email_change_requests table
+DROP INDEX IF EXISTS idx_email_change_requests_deleted_at;
+DROP INDEX IF EXISTS idx_email_change_requests_token_hash;
+DROP INDEX IF EXISTS idx_email_change_requests_user_id;
+DROP TABLE IF EXISTS email_change_requests;
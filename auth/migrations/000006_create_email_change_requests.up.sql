@@ -0,0 +1,23 @@
+-- Create email_change_requests table
+CREATE TABLE IF NOT EXISTS email_change_requests (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    new_email VARCHAR(255) NOT NULL,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_email_change_requests_user_id ON email_change_requests(user_id);
+CREATE INDEX idx_email_change_requests_token_hash ON email_change_requests(token_hash);
+CREATE INDEX idx_email_change_requests_deleted_at ON email_change_requests(deleted_at);
+
+-- Add comments
+COMMENT ON TABLE email_change_requests IS 'Pending email address changes awaiting confirmation from the new address';
+COMMENT ON COLUMN email_change_requests.token_hash IS 'SHA-256 hash of the confirmation token sent to the new address';
+COMMENT ON COLUMN email_change_requests.confirmed_at IS 'When the new address was confirmed and swapped in';
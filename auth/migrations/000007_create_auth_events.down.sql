@@ -0,0 +1,6 @@
+-- Drop auth_events table
+DROP INDEX IF EXISTS idx_auth_events_created_at;
+DROP INDEX IF EXISTS idx_auth_events_ip_address;
+DROP INDEX IF EXISTS idx_auth_events_event_type_created_at;
+DROP INDEX IF EXISTS idx_auth_events_user_id_created_at;
+DROP TABLE IF EXISTS auth_events;
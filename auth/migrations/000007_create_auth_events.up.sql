@@ -0,0 +1,24 @@
+-- Create auth_events table
+CREATE TABLE IF NOT EXISTS auth_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INTEGER,
+    event_type VARCHAR(50) NOT NULL,
+    identifier VARCHAR(255),
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    details VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_auth_events_user_id_created_at ON auth_events(user_id, created_at DESC);
+CREATE INDEX idx_auth_events_event_type_created_at ON auth_events(event_type, created_at DESC);
+CREATE INDEX idx_auth_events_ip_address ON auth_events(ip_address);
+CREATE INDEX idx_auth_events_created_at ON auth_events(created_at);
+
+-- Add comments
+COMMENT ON TABLE auth_events IS 'Append-only security audit log of authentication events';
+COMMENT ON COLUMN auth_events.user_id IS 'Account the event belongs to; NULL when the login name did not match an account';
+COMMENT ON COLUMN auth_events.identifier IS 'Email or username as submitted, kept for failed logins against unknown accounts';
+COMMENT ON COLUMN auth_events.details IS 'Short machine-readable reason, e.g. the failure cause';
@@ -0,0 +1,5 @@
+-- Drop invites table
+DROP INDEX IF EXISTS idx_invites_deleted_at;
+DROP INDEX IF EXISTS idx_invites_inviter_id;
+DROP INDEX IF EXISTS idx_invites_code_hash;
+DROP TABLE IF EXISTS invites;
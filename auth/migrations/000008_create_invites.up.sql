@@ -0,0 +1,31 @@
+-- Create invites table
+CREATE TABLE IF NOT EXISTS invites (
+    id SERIAL PRIMARY KEY,
+    code_prefix VARCHAR(16) NOT NULL,
+    code_hash VARCHAR(64) UNIQUE NOT NULL,
+    email VARCHAR(255),
+    inviter_id INTEGER,
+    max_uses INTEGER NOT NULL DEFAULT 1,
+    use_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP,
+    FOREIGN KEY (inviter_id) REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (max_uses > 0),
+    CHECK (use_count <= max_uses)
+);
+
+-- Create indexes
+CREATE INDEX idx_invites_code_hash ON invites(code_hash);
+CREATE INDEX idx_invites_inviter_id ON invites(inviter_id);
+CREATE INDEX idx_invites_deleted_at ON invites(deleted_at);
+
+-- Add comments
+COMMENT ON TABLE invites IS 'Invite codes that allow registration when signups are restricted';
+COMMENT ON COLUMN invites.code_prefix IS 'First characters of the code, shown to admins to identify it';
+COMMENT ON COLUMN invites.code_hash IS 'SHA-256 hash of the invite code; the plaintext is never stored';
+COMMENT ON COLUMN invites.email IS 'Optional address the invite is restricted to (NULL = anyone with the code)';
+COMMENT ON COLUMN invites.use_count IS 'Number of accounts registered with this invite';
+COMMENT ON COLUMN invites.revoked_at IS 'When the invite was revoked (NULL = active)';
@@ -0,0 +1,12 @@
+-- Restore the free-form role column
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_role;
+ALTER TABLE users ALTER COLUMN role DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'user';
+UPDATE users SET role = 'user' WHERE role <> 'admin';
+COMMENT ON COLUMN users.role IS 'User role: user, admin';
+
+-- Drop roles and permissions tables
+DROP INDEX IF EXISTS idx_role_permissions_permission_id;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
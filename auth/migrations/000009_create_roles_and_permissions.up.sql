@@ -0,0 +1,81 @@
+-- Create roles table
+CREATE TABLE IF NOT EXISTS roles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(20) UNIQUE NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create permissions table
+CREATE TABLE IF NOT EXISTS permissions (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create role_permissions join table
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id INTEGER NOT NULL,
+    permission_id INTEGER NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    FOREIGN KEY (role_id) REFERENCES roles(id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES permissions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_role_permissions_permission_id ON role_permissions(permission_id);
+
+-- Seed roles
+INSERT INTO roles (name, description) VALUES
+    ('reader', 'Can comment on posts'),
+    ('author', 'Can write and publish their own posts'),
+    ('moderator', 'Can moderate comments on any post'),
+    ('editor', 'Can edit any post and moderate comments'),
+    ('admin', 'Full access, including user roles, invites and the audit log')
+ON CONFLICT (name) DO NOTHING;
+
+-- Seed permissions
+INSERT INTO permissions (name, description) VALUES
+    ('comments:write', 'Create, edit and delete own comments'),
+    ('comments:moderate', 'Approve, reject and delete any comment'),
+    ('posts:write', 'Create, edit, publish and delete own posts'),
+    ('posts:edit_any', 'Edit, publish and delete posts by other authors'),
+    ('users:manage_roles', 'Assign roles to users'),
+    ('invites:manage', 'Issue and revoke registration invites'),
+    ('audit:read', 'Query the security audit log')
+ON CONFLICT (name) DO NOTHING;
+
+-- Grant permissions; each role includes everything the roles below it can do
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r
+JOIN permissions p ON (r.name, p.name) IN (
+    ('reader', 'comments:write'),
+    ('author', 'comments:write'),
+    ('author', 'posts:write'),
+    ('moderator', 'comments:write'),
+    ('moderator', 'comments:moderate'),
+    ('editor', 'comments:write'),
+    ('editor', 'comments:moderate'),
+    ('editor', 'posts:write'),
+    ('editor', 'posts:edit_any')
+)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- Existing users could write posts before roles existed, so they become authors
+UPDATE users SET role = 'author' WHERE role IS NULL OR role NOT IN (SELECT name FROM roles);
+
+ALTER TABLE users ALTER COLUMN role SET DEFAULT 'author';
+ALTER TABLE users ALTER COLUMN role SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT fk_users_role FOREIGN KEY (role) REFERENCES roles(name) ON UPDATE CASCADE;
+
+-- Add comments
+COMMENT ON TABLE roles IS 'Named sets of permissions assigned to users';
+COMMENT ON TABLE permissions IS 'Individual actions a role can grant';
+COMMENT ON TABLE role_permissions IS 'Permissions granted to each role';
+COMMENT ON COLUMN users.role IS 'Name of the user''s role (see roles table)';
@@ -0,0 +1,2 @@
+-- Remove the job status permission (role_permissions rows cascade)
+DELETE FROM permissions WHERE name = 'jobs:read';
@@ -0,0 +1,9 @@
+-- Allow admins to view background job status
+INSERT INTO permissions (name, description) VALUES
+    ('jobs:read', 'View background maintenance job status')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.name = 'jobs:read'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
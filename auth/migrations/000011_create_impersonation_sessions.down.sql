@@ -0,0 +1,8 @@
+-- Remove the impersonation permission (role_permissions rows cascade)
+DELETE FROM permissions WHERE name = 'users:impersonate';
+
+-- Drop impersonation_sessions table
+DROP INDEX IF EXISTS idx_impersonation_sessions_created_at;
+DROP INDEX IF EXISTS idx_impersonation_sessions_target_id;
+DROP INDEX IF EXISTS idx_impersonation_sessions_actor_id;
+DROP TABLE IF EXISTS impersonation_sessions;
@@ -0,0 +1,36 @@
+-- Create impersonation_sessions table
+CREATE TABLE IF NOT EXISTS impersonation_sessions (
+    id SERIAL PRIMARY KEY,
+    session_id VARCHAR(64) NOT NULL UNIQUE,
+    actor_id INTEGER,
+    target_id INTEGER NOT NULL,
+    reason VARCHAR(500) NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (target_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Create indexes
+CREATE INDEX idx_impersonation_sessions_actor_id ON impersonation_sessions(actor_id);
+CREATE INDEX idx_impersonation_sessions_target_id ON impersonation_sessions(target_id);
+CREATE INDEX idx_impersonation_sessions_created_at ON impersonation_sessions(created_at);
+
+-- Allow admins to impersonate other users
+INSERT INTO permissions (name, description) VALUES
+    ('users:impersonate', 'Act as another user to reproduce issues')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.name = 'users:impersonate'
+WHERE r.name = 'admin'
+ON CONFLICT DO NOTHING;
+
+-- Add comments
+COMMENT ON TABLE impersonation_sessions IS 'Every access token issued to staff acting as another user';
+COMMENT ON COLUMN impersonation_sessions.session_id IS 'JWT ID of the impersonation token';
+COMMENT ON COLUMN impersonation_sessions.actor_id IS 'Staff member who impersonated; kept NULL if their account is deleted';
+COMMENT ON COLUMN impersonation_sessions.target_id IS 'User being impersonated';
+COMMENT ON COLUMN impersonation_sessions.reason IS 'Why the session was started, e.g. a support ticket reference';
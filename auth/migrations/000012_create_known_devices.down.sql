@@ -0,0 +1,3 @@
+-- Drop known_devices table
+DROP INDEX IF EXISTS idx_known_devices_last_seen_at;
+DROP TABLE IF EXISTS known_devices;
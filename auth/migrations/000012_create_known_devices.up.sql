@@ -0,0 +1,23 @@
+-- Create known_devices table
+CREATE TABLE IF NOT EXISTS known_devices (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    fingerprint VARCHAR(64) NOT NULL,
+    description VARCHAR(100) NOT NULL,
+    ip_range VARCHAR(50) NOT NULL,
+    country VARCHAR(2),
+    first_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT uq_known_devices UNIQUE (user_id, fingerprint, ip_range)
+);
+
+-- Create indexes
+CREATE INDEX idx_known_devices_last_seen_at ON known_devices(last_seen_at);
+
+-- Add comments
+COMMENT ON TABLE known_devices IS 'Devices and networks each user has signed in from, used to flag logins from new ones';
+COMMENT ON COLUMN known_devices.fingerprint IS 'SHA-256 of the browser family and operating system parsed from the user agent';
+COMMENT ON COLUMN known_devices.description IS 'Human-readable device, e.g. "Firefox on Windows"';
+COMMENT ON COLUMN known_devices.ip_range IS 'Network the login came from: the /24 for IPv4 or /48 for IPv6';
+COMMENT ON COLUMN known_devices.country IS 'ISO 3166-1 alpha-2 country of the IP address, if a GeoIP database is configured';
@@ -0,0 +1,8 @@
+-- Keep the audit trail when an account is deleted; AUDIT_RETENTION still expires it
+ALTER TABLE auth_events DROP CONSTRAINT IF EXISTS auth_events_user_id_fkey;
+ALTER TABLE auth_events
+    ADD CONSTRAINT auth_events_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+
+-- Update comments
+COMMENT ON COLUMN auth_events.user_id IS 'Account the event belongs to; NULL when the login name did not match an account or the account was deleted';
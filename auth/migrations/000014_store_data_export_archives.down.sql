@@ -0,0 +1,7 @@
+-- Restore the on-disk archive location; archives stored in the database are lost
+ALTER TABLE data_exports ADD COLUMN IF NOT EXISTS file_path VARCHAR(500);
+UPDATE data_exports SET expires_at = CURRENT_TIMESTAMP WHERE status = 'ready';
+COMMENT ON COLUMN data_exports.file_path IS 'Location of the generated archive on disk';
+
+-- Drop data_export_archives table
+DROP TABLE IF EXISTS data_export_archives;
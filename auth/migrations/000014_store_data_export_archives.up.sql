@@ -0,0 +1,15 @@
+-- Create data_export_archives table so every replica can serve downloads
+CREATE TABLE IF NOT EXISTS data_export_archives (
+    export_id INTEGER PRIMARY KEY,
+    content BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (export_id) REFERENCES data_exports(id) ON DELETE CASCADE
+);
+
+-- Archives written to local disk can't be moved here; expire them so users request a new export
+UPDATE data_exports SET expires_at = CURRENT_TIMESTAMP WHERE status = 'ready';
+ALTER TABLE data_exports DROP COLUMN IF EXISTS file_path;
+
+-- Add comments
+COMMENT ON TABLE data_export_archives IS 'Zip archives of ready data exports, kept apart from data_exports so listing exports does not load them';
+COMMENT ON COLUMN data_export_archives.content IS 'The zip archive';